@@ -21,7 +21,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 )
 
 func TestReadinessCheckValidate(t *testing.T) {
@@ -86,6 +88,31 @@ func TestReadinessCheckValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidTypeCEL": {
+			reason: "Type CEL should be valid without a field path",
+			args: args{
+				r: &ReadinessCheck{
+					Type: ReadinessCheckTypeCEL,
+					CEL: &CELReadinessCheck{
+						Expression: "self.status.ready",
+					},
+				},
+			},
+		},
+		"InvalidTypeCELMissingExpression": {
+			reason: "Type CEL should require an expression",
+			args: args{
+				r: &ReadinessCheck{
+					Type: ReadinessCheckTypeCEL,
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "cel.expression",
+				},
+			},
+		},
 		"InvalidType": {
 			reason: "Invalid type",
 			args: args{
@@ -111,3 +138,246 @@ func TestReadinessCheckValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionValidate(t *testing.T) {
+	type args struct {
+		c *Condition
+	}
+	type want struct {
+		output *field.Error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidExists": {
+			reason: "Operator Exists should be valid without a value",
+			args: args{
+				c: &Condition{
+					FieldPath: "spec.foo",
+					Operator:  ConditionOperatorExists,
+				},
+			},
+		},
+		"ValidEqualsEnvironment": {
+			reason: "Operator Equals with a value and an Environment source should be valid",
+			args: args{
+				c: &Condition{
+					Source:    ConditionSourceEnvironment,
+					FieldPath: "foo",
+					Operator:  ConditionOperatorEquals,
+					Value:     &extv1.JSON{Raw: []byte(`"bar"`)},
+				},
+			},
+		},
+		"ValidIn": {
+			reason: "Operator In with values should be valid",
+			args: args{
+				c: &Condition{
+					FieldPath: "spec.foo",
+					Operator:  ConditionOperatorIn,
+					Values:    []extv1.JSON{{Raw: []byte(`"bar"`)}},
+				},
+			},
+		},
+		"InvalidSource": {
+			reason: "An unknown source should be invalid",
+			args: args{
+				c: &Condition{
+					Source:    "Claim",
+					FieldPath: "spec.foo",
+					Operator:  ConditionOperatorExists,
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "source",
+				},
+			},
+		},
+		"InvalidEmptyFieldPath": {
+			reason: "An empty field path should be invalid",
+			args: args{
+				c: &Condition{
+					Operator: ConditionOperatorExists,
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "fieldPath",
+				},
+			},
+		},
+		"InvalidNotEqualsMissingValue": {
+			reason: "Operator NotEquals without a value should be invalid",
+			args: args{
+				c: &Condition{
+					FieldPath: "spec.foo",
+					Operator:  ConditionOperatorNotEquals,
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "value",
+				},
+			},
+		},
+		"InvalidNotInMissingValues": {
+			reason: "Operator NotIn without values should be invalid",
+			args: args{
+				c: &Condition{
+					FieldPath: "spec.foo",
+					Operator:  ConditionOperatorNotIn,
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "values",
+				},
+			},
+		},
+		"InvalidOperator": {
+			reason: "An unknown operator should be invalid",
+			args: args{
+				c: &Condition{
+					FieldPath: "spec.foo",
+					Operator:  "Matches",
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "operator",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.c.Validate()
+			if diff := cmp.Diff(tc.want.output, got, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConnectionDetailValidate(t *testing.T) {
+	type args struct {
+		d *ConnectionDetail
+	}
+	type want struct {
+		output *field.Error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidInferredFromConnectionSecretKey": {
+			reason: "A connection detail with only a connection secret key should be valid",
+			args: args{
+				d: &ConnectionDetail{
+					FromConnectionSecretKey: pointer.String("password"),
+				},
+			},
+		},
+		"InvalidFromFieldPathMissingName": {
+			reason: "A connection detail of type FromFieldPath should require a name",
+			args: args{
+				d: &ConnectionDetail{
+					FromFieldPath: pointer.String("status.endpoint"),
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "name",
+				},
+			},
+		},
+		"ValidCombine": {
+			reason: "A connection detail of type Combine should be valid",
+			args: args{
+				d: &ConnectionDetail{
+					Name: pointer.String("url"),
+					Combine: &ConnectionDetailCombine{
+						Variables: []ConnectionDetailVariable{
+							{FromConnectionSecretKey: pointer.String("host")},
+							{FromFieldPath: pointer.String("spec.forProvider.port")},
+						},
+						Format: "jdbc:postgresql://%s:%v/db",
+					},
+				},
+			},
+		},
+		"InvalidCombineMissingCombine": {
+			reason: "A connection detail of type Combine should require a combine configuration",
+			args: args{
+				d: &ConnectionDetail{
+					Name: pointer.String("url"),
+					Type: func() *ConnectionDetailType { t := ConnectionDetailTypeCombine; return &t }(),
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "combine",
+				},
+			},
+		},
+		"InvalidCombineVariable": {
+			reason: "A combine variable should set exactly one source",
+			args: args{
+				d: &ConnectionDetail{
+					Name: pointer.String("url"),
+					Combine: &ConnectionDetailCombine{
+						Variables: []ConnectionDetailVariable{
+							{FromConnectionSecretKey: pointer.String("host")},
+							{FromConnectionSecretKey: pointer.String("port"), FromFieldPath: pointer.String("spec.forProvider.port")},
+						},
+						Format: "%s:%s",
+					},
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "combine.variables[1].fromFieldPath",
+				},
+			},
+		},
+		"InvalidTransform": {
+			reason: "A connection detail's transforms should be validated",
+			args: args{
+				d: &ConnectionDetail{
+					FromConnectionSecretKey: pointer.String("password"),
+					Transforms: []Transform{{
+						Type: TransformTypeMath,
+					}},
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "transforms[0].math",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.d.Validate()
+			if diff := cmp.Diff(tc.want.output, got, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
 
@@ -160,3 +161,166 @@ func TestEnvironmentShouldResolve(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentSourceValidate(t *testing.T) {
+	type args struct {
+		src *EnvironmentSource
+	}
+	type want struct {
+		output *field.Error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidConfigMap": {
+			reason: "Should accept a reference to a ConfigMap in a namespace",
+			args: args{
+				src: &EnvironmentSource{
+					Type: EnvironmentSourceTypeReference,
+					Ref:  &EnvironmentSourceReference{Name: "cool-config"},
+					From: &EnvironmentSourceFrom{
+						Type:      EnvironmentSourceFromTypeConfigMap,
+						Namespace: pointer.String("cool-namespace"),
+					},
+				},
+			},
+			want: want{output: nil},
+		},
+		"ValidObject": {
+			reason: "Should accept a reference to a field of an arbitrary object",
+			args: args{
+				src: &EnvironmentSource{
+					Type: EnvironmentSourceTypeReference,
+					Ref:  &EnvironmentSourceReference{Name: "cool-object"},
+					From: &EnvironmentSourceFrom{
+						Type:       EnvironmentSourceFromTypeObject,
+						APIVersion: pointer.String("example.org/v1"),
+						Kind:       pointer.String("CoolObject"),
+						FieldPath:  pointer.String("status.atProvider"),
+					},
+				},
+			},
+			want: want{output: nil},
+		},
+		"InvalidSecretMissingNamespace": {
+			reason: "Should reject a reference to a Secret without a namespace",
+			args: args{
+				src: &EnvironmentSource{
+					Type: EnvironmentSourceTypeReference,
+					Ref:  &EnvironmentSourceReference{Name: "cool-secret"},
+					From: &EnvironmentSourceFrom{
+						Type: EnvironmentSourceFromTypeSecret,
+					},
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "from.namespace",
+				},
+			},
+		},
+		"InvalidObjectMissingFieldPath": {
+			reason: "Should reject a reference to an arbitrary object without a field path",
+			args: args{
+				src: &EnvironmentSource{
+					Type: EnvironmentSourceTypeReference,
+					Ref:  &EnvironmentSourceReference{Name: "cool-object"},
+					From: &EnvironmentSourceFrom{
+						Type:       EnvironmentSourceFromTypeObject,
+						APIVersion: pointer.String("example.org/v1"),
+						Kind:       pointer.String("CoolObject"),
+					},
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "from.fieldPath",
+				},
+			},
+		},
+		"ValidSelectorWithMatchExpressions": {
+			reason: "Should accept a selector with only match expressions",
+			args: args{
+				src: &EnvironmentSource{
+					Type: EnvironmentSourceTypeSelector,
+					Selector: &EnvironmentSourceSelector{
+						Mode: EnvironmentSourceSelectorMultiMode,
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"bronze"}},
+						},
+					},
+				},
+			},
+			want: want{output: nil},
+		},
+		"InvalidSelectorMatchExpression": {
+			reason: "Should reject a selector with an invalid match expression",
+			args: args{
+				src: &EnvironmentSource{
+					Type: EnvironmentSourceTypeSelector,
+					Selector: &EnvironmentSourceSelector{
+						Mode: EnvironmentSourceSelectorMultiMode,
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "tier", Operator: metav1.LabelSelectorOpIn},
+						},
+					},
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "selector.matchExpressions[0]",
+				},
+			},
+		},
+		"ValidSelectorFromClaimNamespace": {
+			reason: "Should accept a label value drawn from the claim's namespace",
+			args: args{
+				src: &EnvironmentSource{
+					Type: EnvironmentSourceTypeSelector,
+					Selector: &EnvironmentSourceSelector{
+						Mode: EnvironmentSourceSelectorSingleMode,
+						MatchLabels: []EnvironmentSourceSelectorLabelMatcher{
+							{Type: EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespace, Key: "team"},
+						},
+					},
+				},
+			},
+			want: want{output: nil},
+		},
+		"InvalidSelectorFromClaimNamespaceLabelMissingKey": {
+			reason: "Should reject a label value drawn from a label of the claim's namespace without a namespace label key",
+			args: args{
+				src: &EnvironmentSource{
+					Type: EnvironmentSourceTypeSelector,
+					Selector: &EnvironmentSourceSelector{
+						Mode: EnvironmentSourceSelectorSingleMode,
+						MatchLabels: []EnvironmentSourceSelectorLabelMatcher{
+							{Type: EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespaceLabel, Key: "team"},
+						},
+					},
+				},
+			},
+			want: want{
+				output: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "selector.matchLabels[0].namespaceLabelKey",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.src.Validate()
+			if diff := cmp.Diff(tc.want.output, got, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
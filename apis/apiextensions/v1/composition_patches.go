@@ -18,6 +18,7 @@ package v1
 
 import (
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -40,6 +41,8 @@ const (
 	PatchTypeCombineFromComposite     PatchType = "CombineFromComposite"
 	PatchTypeCombineToComposite       PatchType = "CombineToComposite"
 	PatchTypeCombineToEnvironment     PatchType = "CombineToEnvironment"
+	PatchTypeFromEachFieldPath        PatchType = "FromEachFieldPath"
+	PatchTypeFromComposedFieldPath    PatchType = "FromComposedFieldPath"
 )
 
 // A FromFieldPathPolicy determines how to patch from a field path.
@@ -79,13 +82,18 @@ type Patch struct {
 	// Type sets the patching behaviour to be used. Each patch type may require
 	// its own fields to be set on the Patch object.
 	// +optional
-	// +kubebuilder:validation:Enum=FromCompositeFieldPath;FromEnvironmentFieldPath;PatchSet;ToCompositeFieldPath;ToEnvironmentFieldPath;CombineFromEnvironment;CombineFromComposite;CombineToComposite;CombineToEnvironment
+	// +kubebuilder:validation:Enum=FromCompositeFieldPath;FromEnvironmentFieldPath;PatchSet;ToCompositeFieldPath;ToEnvironmentFieldPath;CombineFromEnvironment;CombineFromComposite;CombineToComposite;CombineToEnvironment;FromEachFieldPath;FromComposedFieldPath
 	// +kubebuilder:default=FromCompositeFieldPath
 	Type PatchType `json:"type,omitempty"`
 
 	// FromFieldPath is the path of the field on the resource whose value is
 	// to be used as input. Required when type is FromCompositeFieldPath,
-	// FromEnvironmentFieldPath, ToCompositeFieldPath, ToEnvironmentFieldPath.
+	// FromEnvironmentFieldPath, ToCompositeFieldPath, ToEnvironmentFieldPath,
+	// FromEachFieldPath, FromComposedFieldPath. FromEachFieldPath patches read
+	// from an object with the "index", "key", and "value" of the copy of a
+	// resource being composed by a count or for-each.
+	// Use [*] wildcards to patch each element of an array individually,
+	// applying any transforms to each element.
 	// +optional
 	FromFieldPath *string `json:"fromFieldPath,omitempty"`
 
@@ -96,7 +104,12 @@ type Patch struct {
 
 	// ToFieldPath is the path of the field on the resource whose value will
 	// be changed with the result of transforms. Leave empty if you'd like to
-	// propagate to the same path as fromFieldPath.
+	// propagate to the same path as fromFieldPath. When fromFieldPath contains
+	// wildcards toFieldPath may contain either no wildcards, in which case the
+	// transformed elements are patched as an array, or exactly one wildcard to
+	// match a single wildcard in fromFieldPath, in which case each transformed
+	// element is patched to the element at the same index and the destination
+	// array is truncated to the length of the source array.
 	// +optional
 	ToFieldPath *string `json:"toFieldPath,omitempty"`
 
@@ -104,6 +117,14 @@ type Patch struct {
 	// +optional
 	PatchSetName *string `json:"patchSetName,omitempty"`
 
+	// FromResourceName is the name of the composed resource to patch from.
+	// Required when type is FromComposedFieldPath. The observed state of the
+	// named resource is used if it exists, otherwise the state it was just
+	// rendered with. Resources are rendered after any resources they patch
+	// from.
+	// +optional
+	FromResourceName *string `json:"fromResourceName,omitempty"`
+
 	// Transforms are the list of functions that are used as a FIFO pipe for the
 	// input to be transformed.
 	// +optional
@@ -112,6 +133,11 @@ type Patch struct {
 	// Policy configures the specifics of patching behaviour.
 	// +optional
 	Policy *PatchPolicy `json:"policy,omitempty"`
+
+	// Condition determines whether this patch is applied. Patches without a
+	// condition are always applied. Not supported for PatchSet patches.
+	// +optional
+	Condition *Condition `json:"condition,omitempty"`
 }
 
 // GetFromFieldPath returns the FromFieldPath for this Patch, or an empty string if it is nil.
@@ -130,6 +156,15 @@ func (p *Patch) GetToFieldPath() string {
 	return *p.ToFieldPath
 }
 
+// GetFromResourceName returns the FromResourceName for this Patch, or an empty
+// string if it is nil.
+func (p *Patch) GetFromResourceName() string {
+	if p.FromResourceName == nil {
+		return ""
+	}
+	return *p.FromResourceName
+}
+
 // GetType returns the patch type. If the type is not set, it returns the default type.
 func (p *Patch) GetType() PatchType {
 	if p.Type == "" {
@@ -145,10 +180,36 @@ func (p *Patch) Validate() *field.Error {
 		if p.FromFieldPath == nil {
 			return field.Required(field.NewPath("fromFieldPath"), fmt.Sprintf("fromFieldPath must be set for patch type %s", p.Type))
 		}
+		if err := validateWildcards(*p.FromFieldPath, p.ToFieldPath); err != nil {
+			return err
+		}
+	case PatchTypeFromEachFieldPath:
+		if p.FromFieldPath == nil {
+			return field.Required(field.NewPath("fromFieldPath"), fmt.Sprintf("fromFieldPath must be set for patch type %s", p.Type))
+		}
+		if p.ToFieldPath == nil {
+			return field.Required(field.NewPath("toFieldPath"), fmt.Sprintf("toFieldPath must be set for patch type %s", p.Type))
+		}
+		if err := validateWildcards(*p.FromFieldPath, p.ToFieldPath); err != nil {
+			return err
+		}
+	case PatchTypeFromComposedFieldPath:
+		if p.FromResourceName == nil || *p.FromResourceName == "" {
+			return field.Required(field.NewPath("fromResourceName"), fmt.Sprintf("fromResourceName must be set for patch type %s", p.Type))
+		}
+		if p.FromFieldPath == nil {
+			return field.Required(field.NewPath("fromFieldPath"), fmt.Sprintf("fromFieldPath must be set for patch type %s", p.Type))
+		}
+		if err := validateWildcards(*p.FromFieldPath, p.ToFieldPath); err != nil {
+			return err
+		}
 	case PatchTypePatchSet:
 		if p.PatchSetName == nil {
 			return field.Required(field.NewPath("patchSetName"), fmt.Sprintf("patchSetName must be set for patch type %s", p.Type))
 		}
+		if p.Condition != nil {
+			return field.Forbidden(field.NewPath("condition"), fmt.Sprintf("condition is not supported for patch type %s", p.Type))
+		}
 	case PatchTypeCombineFromEnvironment, PatchTypeCombineFromComposite, PatchTypeCombineToComposite, PatchTypeCombineToEnvironment:
 		if p.Combine == nil {
 			return field.Required(field.NewPath("combine"), fmt.Sprintf("combine must be set for patch type %s", p.Type))
@@ -165,10 +226,33 @@ func (p *Patch) Validate() *field.Error {
 			return verrors.WrapFieldError(err, field.NewPath("transforms").Index(i))
 		}
 	}
+	if p.Condition != nil {
+		if err := p.Condition.Validate(); err != nil {
+			return verrors.WrapFieldError(err, field.NewPath("condition"))
+		}
+	}
 
 	return nil
 }
 
+// validateWildcards validates that the wildcards in the supplied field paths
+// can be used to patch arrays element-wise. A nil toFieldPath defaults to the
+// fromFieldPath.
+func validateWildcards(fromFieldPath string, toFieldPath *string) *field.Error {
+	from := strings.Count(fromFieldPath, "[*]")
+	if from == 0 {
+		return nil
+	}
+	to := fromFieldPath
+	if toFieldPath != nil {
+		to = *toFieldPath
+	}
+	if n := strings.Count(to, "[*]"); n == 0 || (from == 1 && n == 1) {
+		return nil
+	}
+	return field.Invalid(field.NewPath("toFieldPath"), to, "must contain no wildcards, or exactly one wildcard when fromFieldPath contains exactly one wildcard")
+}
+
 // A CombineVariable defines the source of a value that is combined with
 // others to form and patch an output value. Currently, this only supports
 // retrieving values from a field path.
@@ -185,6 +269,7 @@ type CombineStrategy string
 // CombineStrategy strategy definitions.
 const (
 	CombineStrategyString CombineStrategy = "string"
+	CombineStrategyMath   CombineStrategy = "math"
 )
 
 // A Combine configures a patch that combines more than
@@ -196,14 +281,19 @@ type Combine struct {
 	Variables []CombineVariable `json:"variables"`
 
 	// Strategy defines the strategy to use to combine the input variable values.
-	// Currently only string is supported.
-	// +kubebuilder:validation:Enum=string
+	// Currently string and math are supported.
+	// +kubebuilder:validation:Enum=string;math
 	Strategy CombineStrategy `json:"strategy"`
 
 	// String declares that input variables should be combined into a single
 	// string, using the relevant settings for formatting purposes.
 	// +optional
 	String *StringCombine `json:"string,omitempty"`
+
+	// Math declares that input variables should be combined into a single
+	// number using a mathematical operation.
+	// +optional
+	Math *MathCombine `json:"math,omitempty"`
 }
 
 // A StringCombine combines multiple input values into a single string.
@@ -212,3 +302,20 @@ type StringCombine struct {
 	// https://golang.org/pkg/fmt/ for details.
 	Format string `json:"fmt"`
 }
+
+// MathCombineType is a mathematical operation used to combine numbers.
+type MathCombineType string
+
+// Accepted MathCombineType.
+const (
+	MathCombineTypeMin MathCombineType = "Min"
+	MathCombineTypeMax MathCombineType = "Max"
+)
+
+// A MathCombine combines multiple numeric input values into a single number.
+type MathCombine struct {
+	// Type of the mathematical operation used to combine the input values.
+	// Min returns the smallest input value, and Max the largest.
+	// +kubebuilder:validation:Enum=Min;Max
+	Type MathCombineType `json:"type"`
+}
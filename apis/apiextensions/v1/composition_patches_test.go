@@ -69,6 +69,92 @@ func TestPatchValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidFromCompositeFieldPathElementWise": {
+			reason: "FromCompositeFieldPath patch with one wildcard in each field path should be valid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.parameters.subnets[*].id"),
+					ToFieldPath:   pointer.String("spec.forProvider.subnets[*].subnetId"),
+				},
+			},
+		},
+		"ValidFromCompositeFieldPathGatherWildcards": {
+			reason: "FromCompositeFieldPath patch with wildcards only in FromFieldPath should be valid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.parameters.networks[*].subnets[*].id"),
+					ToFieldPath:   pointer.String("spec.forProvider.subnetIds"),
+				},
+			},
+		},
+		"InvalidFromCompositeFieldPathTooManyWildcards": {
+			reason: "FromCompositeFieldPath patch with multiple wildcards in both field paths should return error",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.parameters.networks[*].subnets[*].id"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "toFieldPath",
+				},
+			},
+		},
+		"ValidFromCompositeFieldPathWithCondition": {
+			reason: "FromCompositeFieldPath patch with a valid condition should be valid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.forProvider.foo"),
+					Condition: &Condition{
+						FieldPath: "spec.parameters.kms",
+						Operator:  ConditionOperatorExists,
+					},
+				},
+			},
+		},
+		"InvalidFromCompositeFieldPathCondition": {
+			reason: "FromCompositeFieldPath patch with an invalid condition should return error",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("spec.forProvider.foo"),
+					Condition: &Condition{
+						FieldPath: "spec.parameters.tier",
+						Operator:  ConditionOperatorEquals,
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "condition.value",
+				},
+			},
+		},
+		"InvalidPatchSetCondition": {
+			reason: "PatchSet patch with a condition should return error",
+			args: args{
+				patch: &Patch{
+					Type:         PatchTypePatchSet,
+					PatchSetName: pointer.String("foo"),
+					Condition: &Condition{
+						FieldPath: "spec.parameters.kms",
+						Operator:  ConditionOperatorExists,
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeForbidden,
+					Field: "condition",
+				},
+			},
+		},
 		"InvalidFromCompositeFieldPathMissingFromFieldPath": {
 			reason: "Invalid FromCompositeFieldPath missing FromFieldPath should return error",
 			args: args{
@@ -99,6 +185,36 @@ func TestPatchValidate(t *testing.T) {
 				},
 			},
 		},
+		"InvalidFromEachFieldPathMissingToFieldPath": {
+			reason: "Invalid FromEachFieldPath missing ToFieldPath should return error",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromEachFieldPath,
+					FromFieldPath: pointer.String("value"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "toFieldPath",
+				},
+			},
+		},
+		"InvalidFromComposedFieldPathMissingFromResourceName": {
+			reason: "Invalid FromComposedFieldPath missing FromResourceName should return error",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromComposedFieldPath,
+					FromFieldPath: pointer.String("status.atProvider.id"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "fromResourceName",
+				},
+			},
+		},
 		"InvalidPatchSetMissingPatchSetName": {
 			reason: "Invalid PatchSet missing PatchSetName should return error",
 			args: args{
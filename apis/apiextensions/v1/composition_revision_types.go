@@ -64,6 +64,32 @@ type CompositionRevisionSpec struct {
 	// +optional
 	Functions []Function `json:"functions,omitempty"`
 
+	// Mode controls what type or "mode" of Composition will be used.
+	//
+	// "Resources" (the default) indicates that a Composition uses what is
+	// commonly referred to as "Patch & Transform" or P&T composition. This mode
+	// of Composition uses an array of resources, each a template for a composed
+	// resource. It may optionally be followed by an array of functions.
+	//
+	// "Pipeline" indicates that a Composition specifies a pipeline
+	// of Composition Functions, each of which is responsible for producing
+	// composed resources that Crossplane should create or update.
+	// +optional
+	// +kubebuilder:validation:Enum=Resources;Pipeline
+	// +kubebuilder:default=Resources
+	Mode *CompositionMode `json:"mode,omitempty"`
+
+	// Pipeline is a list of composition function steps that will be used when a
+	// composite resource referring to this composition is created. One of
+	// resources and pipeline must be specified - you cannot specify both.
+	//
+	// The Pipeline is only used by the "Pipeline" mode of Composition. It is
+	// ignored by other modes.
+	// +optional
+	// +listType=map
+	// +listMapKey=step
+	Pipeline []PipelineStep `json:"pipeline,omitempty"`
+
 	// WriteConnectionSecretsToNamespace specifies the namespace in which the
 	// connection secrets of composite resource dynamically provisioned using
 	// this composition will be created.
@@ -87,6 +113,14 @@ type CompositionRevisionSpec struct {
 	Revision int64 `json:"revision"`
 }
 
+// GetMode returns the mode of Composition, defaulting to Resources.
+func (cs *CompositionRevisionSpec) GetMode() CompositionMode {
+	if cs.Mode == nil {
+		return CompositionModeResources
+	}
+	return *cs.Mode
+}
+
 // CompositionRevisionStatus shows the observed state of the composition
 // revision.
 type CompositionRevisionStatus struct {
@@ -40,6 +40,7 @@ const (
 	TransformTypeMath    TransformType = "math"
 	TransformTypeString  TransformType = "string"
 	TransformTypeConvert TransformType = "convert"
+	TransformTypeCEL     TransformType = "cel"
 )
 
 // Transform is a unit of process whose input is transformed into an output with
@@ -47,7 +48,7 @@ const (
 type Transform struct {
 
 	// Type of the transform to be run.
-	// +kubebuilder:validation:Enum=map;match;math;string;convert
+	// +kubebuilder:validation:Enum=map;match;math;string;convert;cel
 	Type TransformType `json:"type"`
 
 	// Math is used to transform the input via mathematical operations such as
@@ -71,6 +72,11 @@ type Transform struct {
 	// Convert is used to cast the input into the given output type.
 	// +optional
 	Convert *ConvertTransform `json:"convert,omitempty"`
+
+	// CEL is used to transform the input by evaluating a Common Expression
+	// Language (CEL) expression.
+	// +optional
+	CEL *CELTransform `json:"cel,omitempty"`
 }
 
 // Validate this Transform is valid.
@@ -105,6 +111,11 @@ func (t *Transform) Validate() *field.Error {
 		if err := t.Convert.Validate(); err != nil {
 			return verrors.WrapFieldError(err, field.NewPath("convert"))
 		}
+	case TransformTypeCEL:
+		if t.CEL == nil {
+			return field.Required(field.NewPath("cel"), "given transform type cel requires configuration")
+		}
+		return verrors.WrapFieldError(t.CEL.Validate(), field.NewPath("cel"))
 	default:
 		// Should never happen
 		return field.Invalid(field.NewPath("type"), t.Type, "unknown transform type")
@@ -127,12 +138,18 @@ func (t *ConvertTransform) GetFormat() ConvertTransformFormat {
 func (t *Transform) GetOutputType() (*TransformIOType, error) {
 	var out TransformIOType
 	switch t.Type {
-	case TransformTypeMap, TransformTypeMatch:
+	case TransformTypeMap, TransformTypeMatch, TransformTypeCEL:
 		return nil, nil
 	case TransformTypeMath:
 		out = TransformIOTypeFloat64
+		if t.Math != nil {
+			out = t.Math.GetOutputType()
+		}
 	case TransformTypeString:
 		out = TransformIOTypeString
+		if t.String != nil {
+			out = t.String.GetOutputType()
+		}
 	case TransformTypeConvert:
 		out = t.Convert.ToType
 	default:
@@ -146,17 +163,28 @@ type MathTransformType string
 
 // Accepted MathTransformType.
 const (
-	MathTransformTypeMultiply MathTransformType = "Multiply" // Default
-	MathTransformTypeClampMin MathTransformType = "ClampMin"
-	MathTransformTypeClampMax MathTransformType = "ClampMax"
+	MathTransformTypeMultiply    MathTransformType = "Multiply" // Default
+	MathTransformTypeClampMin    MathTransformType = "ClampMin"
+	MathTransformTypeClampMax    MathTransformType = "ClampMax"
+	MathTransformTypeAdd         MathTransformType = "Add"
+	MathTransformTypeSubtract    MathTransformType = "Subtract"
+	MathTransformTypeDivide      MathTransformType = "Divide"
+	MathTransformTypeModulo      MathTransformType = "Modulo"
+	MathTransformTypeRound       MathTransformType = "Round"
+	MathTransformTypeFloor       MathTransformType = "Floor"
+	MathTransformTypeCeil        MathTransformType = "Ceil"
+	MathTransformTypeConvertUnit MathTransformType = "ConvertUnit"
 )
 
 // MathTransform conducts mathematical operations on the input with the given
 // configuration in its properties.
 type MathTransform struct {
-	// Type of the math transform to be run.
+	// Type of the math transform to be run. Integer inputs produce integer
+	// outputs, and float inputs produce float outputs, except for Round, Floor
+	// and Ceil, which always produce integer outputs. Operations that would
+	// overflow a 64-bit integer return an error.
 	// +optional
-	// +kubebuilder:validation:Enum=Multiply;ClampMin;ClampMax
+	// +kubebuilder:validation:Enum=Multiply;ClampMin;ClampMax;Add;Subtract;Divide;Modulo;Round;Floor;Ceil;ConvertUnit
 	// +kubebuilder:default=Multiply
 	Type MathTransformType `json:"type,omitempty"`
 
@@ -169,6 +197,104 @@ type MathTransform struct {
 	// ClampMax makes sure that the value is not bigger than the given value.
 	// +optional
 	ClampMax *int64 `json:"clampMax,omitempty"`
+	// Add the given value to the value.
+	// +optional
+	Add *int64 `json:"add,omitempty"`
+	// Subtract the given value from the value.
+	// +optional
+	Subtract *int64 `json:"subtract,omitempty"`
+	// Divide the value by the given value. Integer division truncates toward
+	// zero.
+	// +optional
+	Divide *int64 `json:"divide,omitempty"`
+	// Modulo returns the remainder of dividing the value by the given value.
+	// +optional
+	Modulo *int64 `json:"modulo,omitempty"`
+	// ConvertUnit converts the value from one unit to another. Integer values
+	// that can't be represented exactly in the target unit are truncated
+	// toward zero.
+	// +optional
+	ConvertUnit *ConvertUnitTransform `json:"convertUnit,omitempty"`
+}
+
+// A Unit of quantity that a ConvertUnit math transform may convert between.
+type Unit string
+
+// Accepted Units. Units without a suffix are decimal (SI) multiples, while
+// units with an "i" suffix are binary multiples.
+const (
+	UnitNone Unit = ""
+	UnitKilo Unit = "k"
+	UnitMega Unit = "M"
+	UnitGiga Unit = "G"
+	UnitTera Unit = "T"
+	UnitPeta Unit = "P"
+	UnitExa  Unit = "E"
+	UnitKibi Unit = "Ki"
+	UnitMebi Unit = "Mi"
+	UnitGibi Unit = "Gi"
+	UnitTebi Unit = "Ti"
+	UnitPebi Unit = "Pi"
+	UnitExbi Unit = "Ei"
+)
+
+// Factor returns the number of base units represented by this Unit, or zero
+// if the unit is unknown.
+func (u Unit) Factor() int64 {
+	switch u {
+	case UnitNone:
+		return 1
+	case UnitKilo:
+		return 1e3
+	case UnitMega:
+		return 1e6
+	case UnitGiga:
+		return 1e9
+	case UnitTera:
+		return 1e12
+	case UnitPeta:
+		return 1e15
+	case UnitExa:
+		return 1e18
+	case UnitKibi:
+		return 1 << 10
+	case UnitMebi:
+		return 1 << 20
+	case UnitGibi:
+		return 1 << 30
+	case UnitTebi:
+		return 1 << 40
+	case UnitPebi:
+		return 1 << 50
+	case UnitExbi:
+		return 1 << 60
+	}
+	return 0
+}
+
+// A ConvertUnitTransform converts a value from one unit to another, for
+// example from Gi to Mi.
+type ConvertUnitTransform struct {
+	// From is the unit of the input value. Omit it for base units.
+	// +optional
+	// +kubebuilder:validation:Enum="";k;M;G;T;P;E;Ki;Mi;Gi;Ti;Pi;Ei
+	From Unit `json:"from,omitempty"`
+
+	// To is the unit of the output value. Omit it for base units.
+	// +optional
+	// +kubebuilder:validation:Enum="";k;M;G;T;P;E;Ki;Mi;Gi;Ti;Pi;Ei
+	To Unit `json:"to,omitempty"`
+}
+
+// Validate checks this ConvertUnitTransform is valid.
+func (c *ConvertUnitTransform) Validate() *field.Error {
+	if c.From.Factor() == 0 {
+		return field.Invalid(field.NewPath("from"), c.From, "unknown unit")
+	}
+	if c.To.Factor() == 0 {
+		return field.Invalid(field.NewPath("to"), c.To, "unknown unit")
+	}
+	return nil
 }
 
 // GetType returns the type of the math transform, returning the default if not specified.
@@ -179,8 +305,18 @@ func (m *MathTransform) GetType() MathTransformType {
 	return m.Type
 }
 
+// GetOutputType returns the output type of the math transform.
+func (m *MathTransform) GetOutputType() TransformIOType {
+	switch m.GetType() { //nolint:exhaustive // Only rounding changes the output type.
+	case MathTransformTypeRound, MathTransformTypeFloor, MathTransformTypeCeil:
+		return TransformIOTypeInt64
+	default:
+		return TransformIOTypeFloat64
+	}
+}
+
 // Validate checks this MathTransform is valid.
-func (m *MathTransform) Validate() *field.Error {
+func (m *MathTransform) Validate() *field.Error { //nolint:gocyclo // This is a long but simple/same-y switch.
 	switch m.GetType() {
 	case MathTransformTypeMultiply:
 		if m.Multiply == nil {
@@ -194,6 +330,36 @@ func (m *MathTransform) Validate() *field.Error {
 		if m.ClampMax == nil {
 			return field.Required(field.NewPath("clampMax"), "must specify a value if a clamp max math transform is specified")
 		}
+	case MathTransformTypeAdd:
+		if m.Add == nil {
+			return field.Required(field.NewPath("add"), "must specify a value if an add math transform is specified")
+		}
+	case MathTransformTypeSubtract:
+		if m.Subtract == nil {
+			return field.Required(field.NewPath("subtract"), "must specify a value if a subtract math transform is specified")
+		}
+	case MathTransformTypeDivide:
+		if m.Divide == nil {
+			return field.Required(field.NewPath("divide"), "must specify a value if a divide math transform is specified")
+		}
+		if *m.Divide == 0 {
+			return field.Invalid(field.NewPath("divide"), *m.Divide, "cannot divide by zero")
+		}
+	case MathTransformTypeModulo:
+		if m.Modulo == nil {
+			return field.Required(field.NewPath("modulo"), "must specify a value if a modulo math transform is specified")
+		}
+		if *m.Modulo == 0 {
+			return field.Invalid(field.NewPath("modulo"), *m.Modulo, "cannot divide by zero")
+		}
+	case MathTransformTypeRound, MathTransformTypeFloor, MathTransformTypeCeil:
+	case MathTransformTypeConvertUnit:
+		if m.ConvertUnit == nil {
+			return field.Required(field.NewPath("convertUnit"), "must specify a value if a convert unit math transform is specified")
+		}
+		if err := m.ConvertUnit.Validate(); err != nil {
+			return verrors.WrapFieldError(err, field.NewPath("convertUnit"))
+		}
 	default:
 		return field.Invalid(field.NewPath("type"), m.Type, "unknown math transform type")
 	}
@@ -339,6 +505,11 @@ const (
 	StringTransformTypeTrimPrefix StringTransformType = "TrimPrefix"
 	StringTransformTypeTrimSuffix StringTransformType = "TrimSuffix"
 	StringTransformTypeRegexp     StringTransformType = "Regexp"
+	StringTransformTypeJoin       StringTransformType = "Join"
+	StringTransformTypeSplit      StringTransformType = "Split"
+	StringTransformTypeReplace    StringTransformType = "Replace"
+	StringTransformTypeSubstring  StringTransformType = "Substring"
+	StringTransformTypeTruncate   StringTransformType = "Truncate"
 )
 
 // StringConversionType converts a string.
@@ -359,9 +530,11 @@ const (
 // A StringTransform returns a string given the supplied input.
 type StringTransform struct {
 
-	// Type of the string transform to be run.
+	// Type of the string transform to be run. Split produces an array of
+	// strings, and Join requires an array input. All other types produce a
+	// string.
 	// +optional
-	// +kubebuilder:validation:Enum=Format;Convert;TrimPrefix;TrimSuffix;Regexp
+	// +kubebuilder:validation:Enum=Format;Convert;TrimPrefix;TrimSuffix;Regexp;Join;Split;Replace;Substring;Truncate
 	// +kubebuilder:default=Format
 	Type StringTransformType `json:"type,omitempty"`
 
@@ -387,6 +560,42 @@ type StringTransform struct {
 	// Extract a match from the input using a regular expression.
 	// +optional
 	Regexp *StringTransformRegexp `json:"regexp,omitempty"`
+
+	// Join the elements of an input array into a single string.
+	// +optional
+	Join *StringTransformJoin `json:"join,omitempty"`
+
+	// Split the input string into an array of strings.
+	// +optional
+	Split *StringTransformSplit `json:"split,omitempty"`
+
+	// Replace all occurrences of a string in the input.
+	// +optional
+	Replace *StringTransformReplace `json:"replace,omitempty"`
+
+	// Extract a substring from the input.
+	// +optional
+	Substring *StringTransformSubstring `json:"substring,omitempty"`
+
+	// Truncate the input to a maximum length.
+	// +optional
+	Truncate *StringTransformTruncate `json:"truncate,omitempty"`
+}
+
+// GetOutputType returns the output type of the string transform.
+func (s *StringTransform) GetOutputType() TransformIOType {
+	if s.Type == StringTransformTypeSplit {
+		return TransformIOTypeArray
+	}
+	return TransformIOTypeString
+}
+
+// GetInputType returns the input type the string transform requires.
+func (s *StringTransform) GetInputType() TransformIOType {
+	if s.Type == StringTransformTypeJoin {
+		return TransformIOTypeArray
+	}
+	return TransformIOTypeString
 }
 
 // Validate checks this StringTransform is valid.
@@ -416,6 +625,38 @@ func (s *StringTransform) Validate() *field.Error {
 		if _, err := regexp.Compile(s.Regexp.Match); err != nil {
 			return field.Invalid(field.NewPath("regexp", "match"), s.Regexp.Match, "invalid regexp")
 		}
+	case StringTransformTypeJoin:
+		if s.Join == nil {
+			return field.Required(field.NewPath("join"), "join transform requires a join configuration")
+		}
+	case StringTransformTypeSplit:
+		if s.Split == nil {
+			return field.Required(field.NewPath("split"), "split transform requires a split configuration")
+		}
+	case StringTransformTypeReplace:
+		if s.Replace == nil {
+			return field.Required(field.NewPath("replace"), "replace transform requires a replace configuration")
+		}
+		if s.Replace.Search == "" {
+			return field.Required(field.NewPath("replace", "search"), "replace transform requires a search string")
+		}
+	case StringTransformTypeSubstring:
+		if s.Substring == nil {
+			return field.Required(field.NewPath("substring"), "substring transform requires a substring configuration")
+		}
+		if s.Substring.Start < 0 {
+			return field.Invalid(field.NewPath("substring", "start"), s.Substring.Start, "start must not be negative")
+		}
+		if s.Substring.End != nil && *s.Substring.End < s.Substring.Start {
+			return field.Invalid(field.NewPath("substring", "end"), *s.Substring.End, "end must not be less than start")
+		}
+	case StringTransformTypeTruncate:
+		if s.Truncate == nil {
+			return field.Required(field.NewPath("truncate"), "truncate transform requires a truncate configuration")
+		}
+		if s.Truncate.Length < 0 {
+			return field.Invalid(field.NewPath("truncate", "length"), s.Truncate.Length, "length must not be negative")
+		}
 	default:
 		return field.Invalid(field.NewPath("type"), s.Type, "unknown string transform type")
 	}
@@ -435,6 +676,57 @@ type StringTransformRegexp struct {
 	Group *int `json:"group,omitempty"`
 }
 
+// A StringTransformJoin joins the elements of an array into a single string.
+type StringTransformJoin struct {
+	// Separator to place between elements. Elements that are not strings are
+	// formatted using their default Go format.
+	// +optional
+	Separator string `json:"separator,omitempty"`
+}
+
+// A StringTransformSplit splits a string into an array of strings.
+type StringTransformSplit struct {
+	// Separator to split the input around. An empty separator splits the
+	// input after each character.
+	// +optional
+	Separator string `json:"separator,omitempty"`
+}
+
+// A StringTransformReplace replaces all occurrences of a literal string.
+type StringTransformReplace struct {
+	// Search is the literal string to replace.
+	Search string `json:"search"`
+
+	// Replace is the string to replace it with. Omit it to remove all
+	// occurrences of the search string.
+	// +optional
+	Replace string `json:"replace,omitempty"`
+}
+
+// A StringTransformSubstring extracts a substring from the input. Positions are
+// counted in characters, not bytes.
+type StringTransformSubstring struct {
+	// Start is the position of the first character of the substring. Inputs
+	// shorter than this produce an empty string.
+	// +kubebuilder:validation:Minimum=0
+	Start int `json:"start"`
+
+	// End is the position after the last character of the substring. The
+	// substring extends to the end of the input if this is omitted or exceeds
+	// the length of the input.
+	// +optional
+	End *int `json:"end,omitempty"`
+}
+
+// A StringTransformTruncate truncates the input to a maximum length, for
+// example to fit a 63 character Kubernetes name limit.
+type StringTransformTruncate struct {
+	// Length is the maximum number of characters to keep. Inputs that are no
+	// longer than this are returned unchanged.
+	// +kubebuilder:validation:Minimum=0
+	Length int `json:"length"`
+}
+
 // TransformIOType defines the type of a ConvertTransform.
 type TransformIOType string
 
@@ -445,12 +737,15 @@ const (
 	TransformIOTypeInt     TransformIOType = "int"
 	TransformIOTypeInt64   TransformIOType = "int64"
 	TransformIOTypeFloat64 TransformIOType = "float64"
+
+	TransformIOTypeObject TransformIOType = "object"
+	TransformIOTypeArray  TransformIOType = "array"
 )
 
 // IsValid checks if the given TransformIOType is valid.
 func (c TransformIOType) IsValid() bool {
 	switch c {
-	case TransformIOTypeString, TransformIOTypeBool, TransformIOTypeInt, TransformIOTypeInt64, TransformIOTypeFloat64:
+	case TransformIOTypeString, TransformIOTypeBool, TransformIOTypeInt, TransformIOTypeInt64, TransformIOTypeFloat64, TransformIOTypeObject, TransformIOTypeArray:
 		return true
 	}
 	return false
@@ -464,12 +759,14 @@ type ConvertTransformFormat string
 const (
 	ConvertTransformFormatNone     ConvertTransformFormat = "none"
 	ConvertTransformFormatQuantity ConvertTransformFormat = "quantity"
+	ConvertTransformFormatJSON     ConvertTransformFormat = "json"
+	ConvertTransformFormatYAML     ConvertTransformFormat = "yaml"
 )
 
 // IsValid returns true if the format is valid.
 func (c ConvertTransformFormat) IsValid() bool {
 	switch c {
-	case ConvertTransformFormatNone, ConvertTransformFormatQuantity:
+	case ConvertTransformFormatNone, ConvertTransformFormatQuantity, ConvertTransformFormatJSON, ConvertTransformFormatYAML:
 		return true
 	}
 	return false
@@ -478,17 +775,23 @@ func (c ConvertTransformFormat) IsValid() bool {
 // A ConvertTransform converts the input into a new object whose type is supplied.
 type ConvertTransform struct {
 	// ToType is the type of the output of this transform.
-	// +kubebuilder:validation:Enum=string;int;int64;bool;float64
+	// +kubebuilder:validation:Enum=string;int;int64;bool;float64;object;array
 	ToType TransformIOType `json:"toType"`
 
 	// The expected input format.
 	//
 	// * `quantity` - parses the input as a K8s [`resource.Quantity`](https://pkg.go.dev/k8s.io/apimachinery/pkg/api/resource#Quantity).
 	// Only used during `string -> float64` conversions.
+	// * `json` - parses the input as a JSON string, or serializes the input
+	// to a JSON string. Only used during `string -> object`, `string ->
+	// array`, `object -> string`, and `array -> string` conversions.
+	// * `yaml` - parses the input as a YAML string, or serializes the input
+	// to a YAML string. Only used during `string -> object`, `string ->
+	// array`, `object -> string`, and `array -> string` conversions.
 	//
 	// If this property is null, the default conversion is applied.
 	//
-	// +kubebuilder:validation:Enum=none;quantity
+	// +kubebuilder:validation:Enum=none;quantity;json;yaml
 	// +kubebuilder:validation:Default=none
 	Format *ConvertTransformFormat `json:"format,omitempty"`
 }
@@ -503,3 +806,23 @@ func (t ConvertTransform) Validate() *field.Error {
 	}
 	return nil
 }
+
+// A CELTransform transforms the input by evaluating a Common Expression
+// Language (CEL) expression. See https://github.com/google/cel-spec.
+type CELTransform struct {
+	// Expression is the CEL expression to evaluate. The input value is
+	// available to the expression as `self`. The composite resource and the
+	// environment are available as `xr` and `environment` respectively. The
+	// expression's result is used as the output of the transform.
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+}
+
+// Validate returns an error if the CELTransform is invalid. It does not check
+// whether the expression compiles.
+func (t *CELTransform) Validate() *field.Error {
+	if t.Expression == "" {
+		return field.Required(field.NewPath("expression"), "expression must be set")
+	}
+	return nil
+}
@@ -327,6 +327,44 @@ func TestTransformValidate(t *testing.T) {
 				},
 			},
 		},
+		"InvalidCELMissingConfig": {
+			reason: "CEL transform without CEL configuration should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCEL,
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "cel",
+				},
+			},
+		},
+		"InvalidCELEmptyExpression": {
+			reason: "CEL transform with an empty expression should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCEL,
+					CEL:  &CELTransform{},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "cel.expression",
+				},
+			},
+		},
+		"ValidCEL": {
+			reason: "CEL transform with an expression should be valid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeCEL,
+					CEL:  &CELTransform{Expression: "self + 1"},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -362,6 +400,41 @@ func TestTransformGetOutputType(t *testing.T) {
 				output: &[]TransformIOType{TransformIOTypeFloat64}[0],
 			},
 		},
+		"MathTransformRound": {
+			reason: "Output of Math round transform should be int64",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMath,
+					Math: &MathTransform{Type: MathTransformTypeRound},
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeInt64}[0],
+			},
+		},
+		"StringTransform": {
+			reason: "Output of String transform should be string",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeString,
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeString}[0],
+			},
+		},
+		"StringTransformSplit": {
+			reason: "Output of String split transform should be array",
+			args: args{
+				transform: &Transform{
+					Type:   TransformTypeString,
+					String: &StringTransform{Type: StringTransformTypeSplit},
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeArray}[0],
+			},
+		},
 		"ConvertTransform": {
 			reason: "Output of Convert transform, no validation, should be the type specified",
 			args: args{
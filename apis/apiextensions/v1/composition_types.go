@@ -60,6 +60,38 @@ type CompositionSpec struct {
 	// +optional
 	Functions []Function `json:"functions,omitempty"`
 
+	// Mode controls what type or "mode" of Composition will be used.
+	//
+	// "Resources" (the default) indicates that a Composition uses what is
+	// commonly referred to as "Patch & Transform" or P&T composition. This mode
+	// of Composition uses an array of resources, each a template for a composed
+	// resource. It may optionally be followed by an array of functions.
+	//
+	// "Pipeline" indicates that a Composition specifies a pipeline
+	// of Composition Functions, each of which is responsible for producing
+	// composed resources that Crossplane should create or update. THE PIPELINE
+	// MODE IS AN ALPHA FEATURE. It is not honored if the relevant Crossplane
+	// feature flag is disabled.
+	// +optional
+	// +kubebuilder:validation:Enum=Resources;Pipeline
+	// +kubebuilder:default=Resources
+	Mode *CompositionMode `json:"mode,omitempty"`
+
+	// Pipeline is a list of composition function steps that will be used when a
+	// composite resource referring to this composition is created. One of
+	// resources and pipeline must be specified - you cannot specify both.
+	//
+	// The Pipeline is only used by the "Pipeline" mode of Composition. It is
+	// ignored by other modes.
+	//
+	// THIS IS AN ALPHA FIELD. Do not use it in production. It is not honored
+	// unless the relevant Crossplane feature flag is enabled, and may be
+	// changed or removed without notice.
+	// +optional
+	// +listType=map
+	// +listMapKey=step
+	Pipeline []PipelineStep `json:"pipeline,omitempty"`
+
 	// WriteConnectionSecretsToNamespace specifies the namespace in which the
 	// connection secrets of composite resource dynamically provisioned using
 	// this composition will be created.
@@ -83,6 +115,14 @@ type CompositionSpec struct {
 	PublishConnectionDetailsWithStoreConfigRef *StoreConfigReference `json:"publishConnectionDetailsWithStoreConfigRef,omitempty"`
 }
 
+// GetMode returns the mode of Composition, defaulting to Resources.
+func (cs *CompositionSpec) GetMode() CompositionMode {
+	if cs.Mode == nil {
+		return CompositionModeResources
+	}
+	return *cs.Mode
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
 // +genclient
@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -28,6 +31,8 @@ import (
 func (c *Composition) Validate() (warns []string, errs field.ErrorList) {
 	type validationFunc func() field.ErrorList
 	validations := []validationFunc{
+		c.validateMode,
+		c.validatePipeline,
 		c.validatePatchSets,
 		c.validateResources,
 		c.validateFunctions,
@@ -39,6 +44,39 @@ func (c *Composition) Validate() (warns []string, errs field.ErrorList) {
 	return nil, errs
 }
 
+func (c *Composition) validateMode() (errs field.ErrorList) {
+	if c.Spec.GetMode() != CompositionModePipeline {
+		return nil
+	}
+	if len(c.Spec.Pipeline) == 0 {
+		errs = append(errs, field.Required(field.NewPath("spec", "pipeline"), "an array of pipeline steps is required in Pipeline mode"))
+	}
+	if len(c.Spec.Resources) != 0 {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "resources"), "an array of resources is not supported in Pipeline mode"))
+	}
+	if len(c.Spec.Functions) != 0 {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "functions"), "an array of functions is not supported in Pipeline mode"))
+	}
+	return errs
+}
+
+func (c *Composition) validatePipeline() (errs field.ErrorList) {
+	seen := map[string]bool{}
+	for i, s := range c.Spec.Pipeline {
+		if s.Step == "" {
+			errs = append(errs, field.Required(field.NewPath("spec", "pipeline").Index(i).Child("step"), "cannot be empty"))
+		}
+		if seen[s.Step] {
+			errs = append(errs, field.Duplicate(field.NewPath("spec", "pipeline").Index(i).Child("step"), s.Step))
+		}
+		seen[s.Step] = true
+		if s.FunctionRef.Name == "" {
+			errs = append(errs, field.Required(field.NewPath("spec", "pipeline").Index(i).Child("functionRef", "name"), "cannot be empty"))
+		}
+	}
+	return errs
+}
+
 func (c *Composition) validateFunctions() (errs field.ErrorList) {
 	seen := map[string]bool{}
 	for i, f := range c.Spec.Functions {
@@ -83,11 +121,148 @@ func (c *Composition) validateResources() (errs field.ErrorList) {
 				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("readinessChecks").Index(j)))
 			}
 		}
-		// TODO(phisco): we should validate also ConnectionDetails, but would need a major refactoring
+		if res.Condition != nil {
+			if err := res.Condition.Validate(); err != nil {
+				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("condition")))
+			}
+		}
+		if err := validateRepetition(res); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i)))
+		}
+		if err := c.validateComposedPatches(res); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i)))
+		}
+		if err := c.validateDependencies(res); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i)))
+		}
+		if err := c.validateConnectionDetailResources(res); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i)))
+		}
+		for j, cd := range res.ConnectionDetails {
+			if err := cd.Validate(); err != nil {
+				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("connectionDetails").Index(j)))
+			}
+		}
 	}
 	return errs
 }
 
+// validateRepetition checks that a resource that specifies a count or a
+// for-each is named and specifies only one of them, and that only such
+// resources use FromEachFieldPath patches.
+func validateRepetition(res ComposedTemplate) *field.Error {
+	if !res.IsRepeated() {
+		for j, p := range res.Patches {
+			if p.Type == PatchTypeFromEachFieldPath {
+				return field.Forbidden(field.NewPath("patches").Index(j).Child("type"), fmt.Sprintf("patch type %s requires the resource to specify a count or forEach", p.Type))
+			}
+		}
+		return nil
+	}
+	if res.Count != nil && res.ForEach != nil {
+		return field.Forbidden(field.NewPath("forEach"), "cannot specify both count and forEach")
+	}
+	if res.GetName() == "" {
+		return field.Required(field.NewPath("name"), "resources that specify a count or forEach must be named")
+	}
+	if res.Count != nil && res.Count.FromFieldPath == "" {
+		return field.Required(field.NewPath("count", "fromFieldPath"), "cannot be empty")
+	}
+	if res.ForEach != nil && res.ForEach.FromFieldPath == "" {
+		return field.Required(field.NewPath("forEach", "fromFieldPath"), "cannot be empty")
+	}
+	if res.ForEach != nil && res.ForEach.KeyFieldPath != nil && *res.ForEach.KeyFieldPath == "" {
+		return field.Required(field.NewPath("forEach", "keyFieldPath"), "cannot be empty")
+	}
+	return nil
+}
+
+// validateComposedPatches checks that a resource that uses FromComposedFieldPath
+// patches is named, and that it patches from other resources of the
+// Composition. A resource may patch from a copy of a resource that specifies
+// a count or for-each, e.g. "nodepool-0".
+func (c *Composition) validateComposedPatches(res ComposedTemplate) *field.Error {
+	for j, p := range res.Patches {
+		if p.Type != PatchTypeFromComposedFieldPath {
+			continue
+		}
+		if res.GetName() == "" {
+			return field.Required(field.NewPath("name"), fmt.Sprintf("resources that use patch type %s must be named", p.Type))
+		}
+		from := p.GetFromResourceName()
+		if from == res.GetName() {
+			return field.Invalid(field.NewPath("patches").Index(j).Child("fromResourceName"), from, "cannot patch from the resource itself")
+		}
+		if !c.composes(from) {
+			return field.Invalid(field.NewPath("patches").Index(j).Child("fromResourceName"), from, "must be the name of a resource in spec.resources")
+		}
+	}
+	return nil
+}
+
+// validateDependencies checks that a resource that depends on other resources
+// is named, and that it depends on other resources of the Composition. Cycles
+// between resources are detected when they're composed.
+func (c *Composition) validateDependencies(res ComposedTemplate) *field.Error {
+	if len(res.DependsOn) == 0 {
+		return nil
+	}
+	if res.GetName() == "" {
+		return field.Required(field.NewPath("name"), "resources that specify dependsOn must be named")
+	}
+	for j, d := range res.DependsOn {
+		if d == res.GetName() {
+			return field.Invalid(field.NewPath("dependsOn").Index(j), d, "cannot depend on the resource itself")
+		}
+		if !c.composes(d) {
+			return field.Invalid(field.NewPath("dependsOn").Index(j), d, "must be the name of a resource in spec.resources")
+		}
+	}
+	return nil
+}
+
+// validateConnectionDetailResources checks that a resource whose combined
+// connection details read variables from other resources reads them from other
+// resources of the Composition.
+func (c *Composition) validateConnectionDetailResources(res ComposedTemplate) *field.Error {
+	for j, cd := range res.ConnectionDetails {
+		if cd.Combine == nil {
+			continue
+		}
+		for k, v := range cd.Combine.Variables {
+			if v.FromResourceName == nil {
+				continue
+			}
+			from := *v.FromResourceName
+			path := field.NewPath("connectionDetails").Index(j).Child("combine", "variables").Index(k).Child("fromResourceName")
+			if res.GetName() == "" {
+				return field.Required(field.NewPath("name"), "resources whose connection details read from other resources must be named")
+			}
+			if from == res.GetName() {
+				return field.Invalid(path, from, "cannot read from the resource itself")
+			}
+			if !c.composes(from) {
+				return field.Invalid(path, from, "must be the name of a resource in spec.resources")
+			}
+		}
+	}
+	return nil
+}
+
+// composes returns true if the Composition composes a resource with the
+// supplied name.
+func (c *Composition) composes(name string) bool {
+	for _, res := range c.Spec.Resources {
+		if res.GetName() == name {
+			return true
+		}
+		if res.IsRepeated() && strings.HasPrefix(name, res.GetName()+"-") {
+			return true
+		}
+	}
+	return false
+}
+
 // validateResourceNames checks that:
 //  1. Either all resources have a name or they are all anonymous: because if some but not all templates are named it's
 //     safest to refuse to operate. We don't have enough information to use the named composer, but using the anonymous
@@ -429,6 +429,163 @@ func TestCompositionValidateFunctions(t *testing.T) {
 	}
 }
 
+func TestCompositionValidateMode(t *testing.T) {
+	pipeline := CompositionModePipeline
+	type args struct {
+		comp *Composition
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidResourcesMode": {
+			reason: "Resources mode should not be subject to Pipeline mode validation",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{{Name: pointer.String("foo")}},
+					},
+				},
+			},
+		},
+		"ValidPipelineMode": {
+			reason: "Pipeline mode with only a pipeline should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Mode: &pipeline,
+						Pipeline: []PipelineStep{
+							{
+								Step:        "foo",
+								FunctionRef: FunctionReference{Name: "function-foo"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidPipelineMode": {
+			reason: "Pipeline mode requires a pipeline, and doesn't support resources or functions",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Mode:      &pipeline,
+						Resources: []ComposedTemplate{{Name: pointer.String("foo")}},
+						Functions: []Function{{Name: "foo", Type: FunctionTypeContainer, Container: &ContainerFunction{Image: "foo"}}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.pipeline",
+					},
+					{
+						Type:  field.ErrorTypeForbidden,
+						Field: "spec.resources",
+					},
+					{
+						Type:  field.ErrorTypeForbidden,
+						Field: "spec.functions",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotErrs := tc.args.comp.validateMode()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateMode(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionValidatePipeline(t *testing.T) {
+	type args struct {
+		comp *Composition
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidPipeline": {
+			reason: "A pipeline of uniquely named steps that each reference a function should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Pipeline: []PipelineStep{
+							{
+								Step:        "foo",
+								FunctionRef: FunctionReference{Name: "function-foo"},
+							},
+							{
+								Step:        "bar",
+								FunctionRef: FunctionReference{Name: "function-foo"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidPipeline": {
+			reason: "Steps must have unique names and must reference a function",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Pipeline: []PipelineStep{
+							{
+								Step:        "foo",
+								FunctionRef: FunctionReference{Name: "function-foo"},
+							},
+							{
+								Step: "foo",
+							},
+							{
+								FunctionRef: FunctionReference{Name: "function-foo"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeDuplicate,
+						Field: "spec.pipeline[1].step",
+					},
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.pipeline[1].functionRef.name",
+					},
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.pipeline[2].step",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotErrs := tc.args.comp.validatePipeline()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidatePipeline(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositionValidateResources(t *testing.T) {
 	type args struct {
 		comp *Composition
@@ -591,6 +748,354 @@ func TestCompositionValidateResources(t *testing.T) {
 				},
 			},
 		},
+		"ValidRepeatedResource": {
+			reason: "a named resource with a count and FromEachFieldPath patches should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name:  pointer.String("nodepool"),
+								Count: &TemplateCount{FromFieldPath: "spec.nodePools"},
+								Patches: []Patch{
+									{
+										Type:          PatchTypeFromEachFieldPath,
+										FromFieldPath: pointer.String("key"),
+										ToFieldPath:   pointer.String("spec.forProvider.suffix"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidRepeatedResourceDueToCountAndForEach": {
+			reason: "a resource with both a count and a forEach should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name:    pointer.String("bucket"),
+								Count:   &TemplateCount{FromFieldPath: "spec.count"},
+								ForEach: &TemplateForEach{FromFieldPath: "spec.regions"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeForbidden,
+						Field: "spec.resources[0].forEach",
+					},
+				},
+			},
+		},
+		"InvalidRepeatedResourceDueToEmptyKeyFieldPath": {
+			reason: "a resource with a forEach with an empty key field path should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name:    pointer.String("bucket"),
+								ForEach: &TemplateForEach{FromFieldPath: "spec.pools", KeyFieldPath: pointer.String("")},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].forEach.keyFieldPath",
+					},
+				},
+			},
+		},
+		"InvalidRepeatedResourceDueToMissingName": {
+			reason: "an anonymous resource with a forEach should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								ForEach: &TemplateForEach{FromFieldPath: "spec.regions"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].name",
+					},
+				},
+			},
+		},
+		"InvalidFromEachPatchDueToMissingRepetition": {
+			reason: "a resource without a count or forEach should not use FromEachFieldPath patches",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: pointer.String("bucket"),
+								Patches: []Patch{
+									{
+										Type:          PatchTypeFromEachFieldPath,
+										FromFieldPath: pointer.String("key"),
+										ToFieldPath:   pointer.String("spec.forProvider.region"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeForbidden,
+						Field: "spec.resources[0].patches[0].type",
+					},
+				},
+			},
+		},
+		"ValidComposedPatches": {
+			reason: "resources that patch from other resources, including copies of repeated resources, should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name:  pointer.String("nodepool"),
+								Count: &TemplateCount{FromFieldPath: "spec.nodePools"},
+							},
+							{
+								Name: pointer.String("vpc"),
+							},
+							{
+								Name: pointer.String("cluster"),
+								Patches: []Patch{
+									{
+										Type:             PatchTypeFromComposedFieldPath,
+										FromResourceName: pointer.String("vpc"),
+										FromFieldPath:    pointer.String("status.atProvider.id"),
+									},
+									{
+										Type:             PatchTypeFromComposedFieldPath,
+										FromResourceName: pointer.String("nodepool-0"),
+										FromFieldPath:    pointer.String("status.atProvider.id"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidComposedPatchDueToSelf": {
+			reason: "a resource that patches from itself should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: pointer.String("vpc"),
+								Patches: []Patch{
+									{
+										Type:             PatchTypeFromComposedFieldPath,
+										FromResourceName: pointer.String("vpc"),
+										FromFieldPath:    pointer.String("status.atProvider.id"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[0].fromResourceName",
+					},
+				},
+			},
+		},
+		"InvalidComposedPatchDueToUnknownResource": {
+			reason: "a resource that patches from a resource that isn't composed should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: pointer.String("cluster"),
+								Patches: []Patch{
+									{
+										Type:             PatchTypeFromComposedFieldPath,
+										FromResourceName: pointer.String("vpc"),
+										FromFieldPath:    pointer.String("status.atProvider.id"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[0].fromResourceName",
+					},
+				},
+			},
+		},
+		"ValidDependencies": {
+			reason: "a resource may depend on other resources, including repeated ones",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{Name: pointer.String("vpc")},
+							{Name: pointer.String("nodepool"), Count: &TemplateCount{FromFieldPath: "spec.nodePools"}},
+							{Name: pointer.String("cluster"), DependsOn: []string{"vpc", "nodepool", "nodepool-0"}},
+						},
+					},
+				},
+			},
+			want: want{},
+		},
+		"InvalidDependencyDueToSelf": {
+			reason: "a resource that depends on itself should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{Name: pointer.String("vpc"), DependsOn: []string{"vpc"}},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].dependsOn[0]",
+					},
+				},
+			},
+		},
+		"InvalidDependencyDueToUnknownResource": {
+			reason: "a resource that depends on a resource that isn't composed should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{Name: pointer.String("vpc")},
+							{Name: pointer.String("cluster"), DependsOn: []string{"vpc", "subnet"}},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[1].dependsOn[1]",
+					},
+				},
+			},
+		},
+		"ValidConnectionDetailFromResourceName": {
+			reason: "a combined connection detail that reads from another composed resource should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{Name: pointer.String("db")},
+							{
+								Name: pointer.String("user"),
+								ConnectionDetails: []ConnectionDetail{{
+									Name: pointer.String("url"),
+									Combine: &ConnectionDetailCombine{
+										Variables: []ConnectionDetailVariable{{FromResourceName: pointer.String("db"), FromConnectionSecretKey: pointer.String("host")}},
+										Format:    "%s",
+									},
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidConnectionDetailFromResourceNameDueToSelf": {
+			reason: "a combined connection detail that reads from its own resource by name should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: pointer.String("db"),
+								ConnectionDetails: []ConnectionDetail{{
+									Name: pointer.String("url"),
+									Combine: &ConnectionDetailCombine{
+										Variables: []ConnectionDetailVariable{{FromResourceName: pointer.String("db"), FromConnectionSecretKey: pointer.String("host")}},
+										Format:    "%s",
+									},
+								}},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].connectionDetails[0].combine.variables[0].fromResourceName",
+					},
+				},
+			},
+		},
+		"InvalidConnectionDetailFromResourceNameDueToUnknownResource": {
+			reason: "a combined connection detail that reads from a resource that isn't composed should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{Name: pointer.String("db")},
+							{
+								Name: pointer.String("user"),
+								ConnectionDetails: []ConnectionDetail{{
+									Name: pointer.String("url"),
+									Combine: &ConnectionDetailCombine{
+										Variables: []ConnectionDetailVariable{{FromResourceName: pointer.String("cache"), FromConnectionSecretKey: pointer.String("host")}},
+										Format:    "%s",
+									},
+								}},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[1].connectionDetails[0].combine.variables[0].fromResourceName",
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
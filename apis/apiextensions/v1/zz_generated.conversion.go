@@ -41,6 +41,20 @@ func (c *GeneratedRevisionSpecConverter) FromRevisionSpec(source CompositionRevi
 		}
 	}
 	v1CompositionSpec.Functions = v1FunctionList
+	var pV1CompositionMode *CompositionMode
+	if source.Mode != nil {
+		v1CompositionMode := CompositionMode(*source.Mode)
+		pV1CompositionMode = &v1CompositionMode
+	}
+	v1CompositionSpec.Mode = pV1CompositionMode
+	var v1PipelineStepList []PipelineStep
+	if source.Pipeline != nil {
+		v1PipelineStepList = make([]PipelineStep, len(source.Pipeline))
+		for l := 0; l < len(source.Pipeline); l++ {
+			v1PipelineStepList[l] = c.v1PipelineStepToV1PipelineStep(source.Pipeline[l])
+		}
+	}
+	v1CompositionSpec.Pipeline = v1PipelineStepList
 	var pString *string
 	if source.WriteConnectionSecretsToNamespace != nil {
 		xstring := *source.WriteConnectionSecretsToNamespace
@@ -78,6 +92,20 @@ func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec)
 		}
 	}
 	v1CompositionRevisionSpec.Functions = v1FunctionList
+	var pV1CompositionMode *CompositionMode
+	if source.Mode != nil {
+		v1CompositionMode := CompositionMode(*source.Mode)
+		pV1CompositionMode = &v1CompositionMode
+	}
+	v1CompositionRevisionSpec.Mode = pV1CompositionMode
+	var v1PipelineStepList []PipelineStep
+	if source.Pipeline != nil {
+		v1PipelineStepList = make([]PipelineStep, len(source.Pipeline))
+		for l := 0; l < len(source.Pipeline); l++ {
+			v1PipelineStepList[l] = c.v1PipelineStepToV1PipelineStep(source.Pipeline[l])
+		}
+	}
+	v1CompositionRevisionSpec.Pipeline = v1PipelineStepList
 	var pString *string
 	if source.WriteConnectionSecretsToNamespace != nil {
 		xstring := *source.WriteConnectionSecretsToNamespace
@@ -95,6 +123,24 @@ func (c *GeneratedRevisionSpecConverter) pRuntimeRawExtensionToPRuntimeRawExtens
 	}
 	return pRuntimeRawExtension
 }
+func (c *GeneratedRevisionSpecConverter) pV1CELReadinessCheckToPV1CELReadinessCheck(source *CELReadinessCheck) *CELReadinessCheck {
+	var pV1CELReadinessCheck *CELReadinessCheck
+	if source != nil {
+		var v1CELReadinessCheck CELReadinessCheck
+		v1CELReadinessCheck.Expression = (*source).Expression
+		pV1CELReadinessCheck = &v1CELReadinessCheck
+	}
+	return pV1CELReadinessCheck
+}
+func (c *GeneratedRevisionSpecConverter) pV1CELTransformToPV1CELTransform(source *CELTransform) *CELTransform {
+	var pV1CELTransform *CELTransform
+	if source != nil {
+		var v1CELTransform CELTransform
+		v1CELTransform.Expression = (*source).Expression
+		pV1CELTransform = &v1CELTransform
+	}
+	return pV1CELTransform
+}
 func (c *GeneratedRevisionSpecConverter) pV1CombineToPV1Combine(source *Combine) *Combine {
 	var pV1Combine *Combine
 	if source != nil {
@@ -109,10 +155,53 @@ func (c *GeneratedRevisionSpecConverter) pV1CombineToPV1Combine(source *Combine)
 		v1Combine.Variables = v1CombineVariableList
 		v1Combine.Strategy = CombineStrategy((*source).Strategy)
 		v1Combine.String = c.pV1StringCombineToPV1StringCombine((*source).String)
+		v1Combine.Math = c.pV1MathCombineToPV1MathCombine((*source).Math)
 		pV1Combine = &v1Combine
 	}
 	return pV1Combine
 }
+func (c *GeneratedRevisionSpecConverter) pV1ConditionToPV1Condition(source *Condition) *Condition {
+	var pV1Condition *Condition
+	if source != nil {
+		var v1Condition Condition
+		v1Condition.Source = ConditionSource((*source).Source)
+		v1Condition.FieldPath = (*source).FieldPath
+		v1Condition.Operator = ConditionOperator((*source).Operator)
+		var pV1JSON *v12.JSON
+		if (*source).Value != nil {
+			v1JSON := c.v1JSONToV1JSON(*(*source).Value)
+			pV1JSON = &v1JSON
+		}
+		v1Condition.Value = pV1JSON
+		var v1JSONList []v12.JSON
+		if (*source).Values != nil {
+			v1JSONList = make([]v12.JSON, len((*source).Values))
+			for i := 0; i < len((*source).Values); i++ {
+				v1JSONList[i] = c.v1JSONToV1JSON((*source).Values[i])
+			}
+		}
+		v1Condition.Values = v1JSONList
+		pV1Condition = &v1Condition
+	}
+	return pV1Condition
+}
+func (c *GeneratedRevisionSpecConverter) pV1ConnectionDetailCombineToPV1ConnectionDetailCombine(source *ConnectionDetailCombine) *ConnectionDetailCombine {
+	var pV1ConnectionDetailCombine *ConnectionDetailCombine
+	if source != nil {
+		var v1ConnectionDetailCombine ConnectionDetailCombine
+		var v1ConnectionDetailVariableList []ConnectionDetailVariable
+		if (*source).Variables != nil {
+			v1ConnectionDetailVariableList = make([]ConnectionDetailVariable, len((*source).Variables))
+			for i := 0; i < len((*source).Variables); i++ {
+				v1ConnectionDetailVariableList[i] = c.v1ConnectionDetailVariableToV1ConnectionDetailVariable((*source).Variables[i])
+			}
+		}
+		v1ConnectionDetailCombine.Variables = v1ConnectionDetailVariableList
+		v1ConnectionDetailCombine.Format = (*source).Format
+		pV1ConnectionDetailCombine = &v1ConnectionDetailCombine
+	}
+	return pV1ConnectionDetailCombine
+}
 func (c *GeneratedRevisionSpecConverter) pV1ContainerFunctionNetworkToPV1ContainerFunctionNetwork(source *ContainerFunctionNetwork) *ContainerFunctionNetwork {
 	var pV1ContainerFunctionNetwork *ContainerFunctionNetwork
 	if source != nil {
@@ -202,6 +291,16 @@ func (c *GeneratedRevisionSpecConverter) pV1ConvertTransformToPV1ConvertTransfor
 	}
 	return pV1ConvertTransform
 }
+func (c *GeneratedRevisionSpecConverter) pV1ConvertUnitTransformToPV1ConvertUnitTransform(source *ConvertUnitTransform) *ConvertUnitTransform {
+	var pV1ConvertUnitTransform *ConvertUnitTransform
+	if source != nil {
+		var v1ConvertUnitTransform ConvertUnitTransform
+		v1ConvertUnitTransform.From = Unit((*source).From)
+		v1ConvertUnitTransform.To = Unit((*source).To)
+		pV1ConvertUnitTransform = &v1ConvertUnitTransform
+	}
+	return pV1ConvertUnitTransform
+}
 func (c *GeneratedRevisionSpecConverter) pV1DurationToPV1Duration(source *v11.Duration) *v11.Duration {
 	var pV1Duration *v11.Duration
 	if source != nil {
@@ -232,10 +331,49 @@ func (c *GeneratedRevisionSpecConverter) pV1EnvironmentConfigurationToPV1Environ
 		}
 		v1EnvironmentConfiguration.Patches = v1EnvironmentPatchList
 		v1EnvironmentConfiguration.Policy = c.pV1PolicyToPV1Policy((*source).Policy)
+		var pBool *bool
+		if (*source).AllowDebug != nil {
+			xbool := *(*source).AllowDebug
+			pBool = &xbool
+		}
+		v1EnvironmentConfiguration.AllowDebug = pBool
 		pV1EnvironmentConfiguration = &v1EnvironmentConfiguration
 	}
 	return pV1EnvironmentConfiguration
 }
+func (c *GeneratedRevisionSpecConverter) pV1EnvironmentSourceFromToPV1EnvironmentSourceFrom(source *EnvironmentSourceFrom) *EnvironmentSourceFrom {
+	var pV1EnvironmentSourceFrom *EnvironmentSourceFrom
+	if source != nil {
+		var v1EnvironmentSourceFrom EnvironmentSourceFrom
+		v1EnvironmentSourceFrom.Type = EnvironmentSourceFromType((*source).Type)
+		var pString *string
+		if (*source).APIVersion != nil {
+			xstring := *(*source).APIVersion
+			pString = &xstring
+		}
+		v1EnvironmentSourceFrom.APIVersion = pString
+		var pString2 *string
+		if (*source).Kind != nil {
+			xstring2 := *(*source).Kind
+			pString2 = &xstring2
+		}
+		v1EnvironmentSourceFrom.Kind = pString2
+		var pString3 *string
+		if (*source).Namespace != nil {
+			xstring3 := *(*source).Namespace
+			pString3 = &xstring3
+		}
+		v1EnvironmentSourceFrom.Namespace = pString3
+		var pString4 *string
+		if (*source).FieldPath != nil {
+			xstring4 := *(*source).FieldPath
+			pString4 = &xstring4
+		}
+		v1EnvironmentSourceFrom.FieldPath = pString4
+		pV1EnvironmentSourceFrom = &v1EnvironmentSourceFrom
+	}
+	return pV1EnvironmentSourceFrom
+}
 func (c *GeneratedRevisionSpecConverter) pV1EnvironmentSourceReferenceToPV1EnvironmentSourceReference(source *EnvironmentSourceReference) *EnvironmentSourceReference {
 	var pV1EnvironmentSourceReference *EnvironmentSourceReference
 	if source != nil {
@@ -265,6 +403,14 @@ func (c *GeneratedRevisionSpecConverter) pV1EnvironmentSourceSelectorToPV1Enviro
 			}
 		}
 		v1EnvironmentSourceSelector.MatchLabels = v1EnvironmentSourceSelectorLabelMatcherList
+		var v1LabelSelectorRequirementList []v11.LabelSelectorRequirement
+		if (*source).MatchExpressions != nil {
+			v1LabelSelectorRequirementList = make([]v11.LabelSelectorRequirement, len((*source).MatchExpressions))
+			for j := 0; j < len((*source).MatchExpressions); j++ {
+				v1LabelSelectorRequirementList[j] = c.v1LabelSelectorRequirementToV1LabelSelectorRequirement((*source).MatchExpressions[j])
+			}
+		}
+		v1EnvironmentSourceSelector.MatchExpressions = v1LabelSelectorRequirementList
 		pV1EnvironmentSourceSelector = &v1EnvironmentSourceSelector
 	}
 	return pV1EnvironmentSourceSelector
@@ -310,6 +456,15 @@ func (c *GeneratedRevisionSpecConverter) pV1MatchTransformToPV1MatchTransform(so
 	}
 	return pV1MatchTransform
 }
+func (c *GeneratedRevisionSpecConverter) pV1MathCombineToPV1MathCombine(source *MathCombine) *MathCombine {
+	var pV1MathCombine *MathCombine
+	if source != nil {
+		var v1MathCombine MathCombine
+		v1MathCombine.Type = MathCombineType((*source).Type)
+		pV1MathCombine = &v1MathCombine
+	}
+	return pV1MathCombine
+}
 func (c *GeneratedRevisionSpecConverter) pV1MathTransformToPV1MathTransform(source *MathTransform) *MathTransform {
 	var pV1MathTransform *MathTransform
 	if source != nil {
@@ -333,6 +488,31 @@ func (c *GeneratedRevisionSpecConverter) pV1MathTransformToPV1MathTransform(sour
 			pInt643 = &xint643
 		}
 		v1MathTransform.ClampMax = pInt643
+		var pInt644 *int64
+		if (*source).Add != nil {
+			xint644 := *(*source).Add
+			pInt644 = &xint644
+		}
+		v1MathTransform.Add = pInt644
+		var pInt645 *int64
+		if (*source).Subtract != nil {
+			xint645 := *(*source).Subtract
+			pInt645 = &xint645
+		}
+		v1MathTransform.Subtract = pInt645
+		var pInt646 *int64
+		if (*source).Divide != nil {
+			xint646 := *(*source).Divide
+			pInt646 = &xint646
+		}
+		v1MathTransform.Divide = pInt646
+		var pInt647 *int64
+		if (*source).Modulo != nil {
+			xint647 := *(*source).Modulo
+			pInt647 = &xint647
+		}
+		v1MathTransform.Modulo = pInt647
+		v1MathTransform.ConvertUnit = c.pV1ConvertUnitTransformToPV1ConvertUnitTransform((*source).ConvertUnit)
 		pV1MathTransform = &v1MathTransform
 	}
 	return pV1MathTransform
@@ -410,6 +590,15 @@ func (c *GeneratedRevisionSpecConverter) pV1StringCombineToPV1StringCombine(sour
 	}
 	return pV1StringCombine
 }
+func (c *GeneratedRevisionSpecConverter) pV1StringTransformJoinToPV1StringTransformJoin(source *StringTransformJoin) *StringTransformJoin {
+	var pV1StringTransformJoin *StringTransformJoin
+	if source != nil {
+		var v1StringTransformJoin StringTransformJoin
+		v1StringTransformJoin.Separator = (*source).Separator
+		pV1StringTransformJoin = &v1StringTransformJoin
+	}
+	return pV1StringTransformJoin
+}
 func (c *GeneratedRevisionSpecConverter) pV1StringTransformRegexpToPV1StringTransformRegexp(source *StringTransformRegexp) *StringTransformRegexp {
 	var pV1StringTransformRegexp *StringTransformRegexp
 	if source != nil {
@@ -425,6 +614,40 @@ func (c *GeneratedRevisionSpecConverter) pV1StringTransformRegexpToPV1StringTran
 	}
 	return pV1StringTransformRegexp
 }
+func (c *GeneratedRevisionSpecConverter) pV1StringTransformReplaceToPV1StringTransformReplace(source *StringTransformReplace) *StringTransformReplace {
+	var pV1StringTransformReplace *StringTransformReplace
+	if source != nil {
+		var v1StringTransformReplace StringTransformReplace
+		v1StringTransformReplace.Search = (*source).Search
+		v1StringTransformReplace.Replace = (*source).Replace
+		pV1StringTransformReplace = &v1StringTransformReplace
+	}
+	return pV1StringTransformReplace
+}
+func (c *GeneratedRevisionSpecConverter) pV1StringTransformSplitToPV1StringTransformSplit(source *StringTransformSplit) *StringTransformSplit {
+	var pV1StringTransformSplit *StringTransformSplit
+	if source != nil {
+		var v1StringTransformSplit StringTransformSplit
+		v1StringTransformSplit.Separator = (*source).Separator
+		pV1StringTransformSplit = &v1StringTransformSplit
+	}
+	return pV1StringTransformSplit
+}
+func (c *GeneratedRevisionSpecConverter) pV1StringTransformSubstringToPV1StringTransformSubstring(source *StringTransformSubstring) *StringTransformSubstring {
+	var pV1StringTransformSubstring *StringTransformSubstring
+	if source != nil {
+		var v1StringTransformSubstring StringTransformSubstring
+		v1StringTransformSubstring.Start = (*source).Start
+		var pInt *int
+		if (*source).End != nil {
+			xint := *(*source).End
+			pInt = &xint
+		}
+		v1StringTransformSubstring.End = pInt
+		pV1StringTransformSubstring = &v1StringTransformSubstring
+	}
+	return pV1StringTransformSubstring
+}
 func (c *GeneratedRevisionSpecConverter) pV1StringTransformToPV1StringTransform(source *StringTransform) *StringTransform {
 	var pV1StringTransform *StringTransform
 	if source != nil {
@@ -449,10 +672,48 @@ func (c *GeneratedRevisionSpecConverter) pV1StringTransformToPV1StringTransform(
 		}
 		v1StringTransform.Trim = pString2
 		v1StringTransform.Regexp = c.pV1StringTransformRegexpToPV1StringTransformRegexp((*source).Regexp)
+		v1StringTransform.Join = c.pV1StringTransformJoinToPV1StringTransformJoin((*source).Join)
+		v1StringTransform.Split = c.pV1StringTransformSplitToPV1StringTransformSplit((*source).Split)
+		v1StringTransform.Replace = c.pV1StringTransformReplaceToPV1StringTransformReplace((*source).Replace)
+		v1StringTransform.Substring = c.pV1StringTransformSubstringToPV1StringTransformSubstring((*source).Substring)
+		v1StringTransform.Truncate = c.pV1StringTransformTruncateToPV1StringTransformTruncate((*source).Truncate)
 		pV1StringTransform = &v1StringTransform
 	}
 	return pV1StringTransform
 }
+func (c *GeneratedRevisionSpecConverter) pV1StringTransformTruncateToPV1StringTransformTruncate(source *StringTransformTruncate) *StringTransformTruncate {
+	var pV1StringTransformTruncate *StringTransformTruncate
+	if source != nil {
+		var v1StringTransformTruncate StringTransformTruncate
+		v1StringTransformTruncate.Length = (*source).Length
+		pV1StringTransformTruncate = &v1StringTransformTruncate
+	}
+	return pV1StringTransformTruncate
+}
+func (c *GeneratedRevisionSpecConverter) pV1TemplateCountToPV1TemplateCount(source *TemplateCount) *TemplateCount {
+	var pV1TemplateCount *TemplateCount
+	if source != nil {
+		var v1TemplateCount TemplateCount
+		v1TemplateCount.FromFieldPath = (*source).FromFieldPath
+		pV1TemplateCount = &v1TemplateCount
+	}
+	return pV1TemplateCount
+}
+func (c *GeneratedRevisionSpecConverter) pV1TemplateForEachToPV1TemplateForEach(source *TemplateForEach) *TemplateForEach {
+	var pV1TemplateForEach *TemplateForEach
+	if source != nil {
+		var v1TemplateForEach TemplateForEach
+		v1TemplateForEach.FromFieldPath = (*source).FromFieldPath
+		var pString *string
+		if (*source).KeyFieldPath != nil {
+			xstring := *(*source).KeyFieldPath
+			pString = &xstring
+		}
+		v1TemplateForEach.KeyFieldPath = pString
+		pV1TemplateForEach = &v1TemplateForEach
+	}
+	return pV1TemplateForEach
+}
 func (c *GeneratedRevisionSpecConverter) v1CombineVariableToV1CombineVariable(source CombineVariable) CombineVariable {
 	var v1CombineVariable CombineVariable
 	v1CombineVariable.FromFieldPath = source.FromFieldPath
@@ -491,6 +752,17 @@ func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(
 		}
 	}
 	v1ComposedTemplate.ReadinessChecks = v1ReadinessCheckList
+	v1ComposedTemplate.Condition = c.pV1ConditionToPV1Condition(source.Condition)
+	v1ComposedTemplate.Count = c.pV1TemplateCountToPV1TemplateCount(source.Count)
+	v1ComposedTemplate.ForEach = c.pV1TemplateForEachToPV1TemplateForEach(source.ForEach)
+	var stringList []string
+	if source.DependsOn != nil {
+		stringList = make([]string, len(source.DependsOn))
+		for l := 0; l < len(source.DependsOn); l++ {
+			stringList[l] = source.DependsOn[l]
+		}
+	}
+	v1ComposedTemplate.DependsOn = stringList
 	return v1ComposedTemplate
 }
 func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(source ConnectionDetail) ConnectionDetail {
@@ -525,8 +797,39 @@ func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(
 		pString4 = &xstring4
 	}
 	v1ConnectionDetail.Value = pString4
+	v1ConnectionDetail.Combine = c.pV1ConnectionDetailCombineToPV1ConnectionDetailCombine(source.Combine)
+	var v1TransformList []Transform
+	if source.Transforms != nil {
+		v1TransformList = make([]Transform, len(source.Transforms))
+		for i := 0; i < len(source.Transforms); i++ {
+			v1TransformList[i] = c.v1TransformToV1Transform(source.Transforms[i])
+		}
+	}
+	v1ConnectionDetail.Transforms = v1TransformList
 	return v1ConnectionDetail
 }
+func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailVariableToV1ConnectionDetailVariable(source ConnectionDetailVariable) ConnectionDetailVariable {
+	var v1ConnectionDetailVariable ConnectionDetailVariable
+	var pString *string
+	if source.FromConnectionSecretKey != nil {
+		xstring := *source.FromConnectionSecretKey
+		pString = &xstring
+	}
+	v1ConnectionDetailVariable.FromConnectionSecretKey = pString
+	var pString2 *string
+	if source.FromFieldPath != nil {
+		xstring2 := *source.FromFieldPath
+		pString2 = &xstring2
+	}
+	v1ConnectionDetailVariable.FromFieldPath = pString2
+	var pString3 *string
+	if source.FromResourceName != nil {
+		xstring3 := *source.FromResourceName
+		pString3 = &xstring3
+	}
+	v1ConnectionDetailVariable.FromResourceName = pString3
+	return v1ConnectionDetailVariable
+}
 func (c *GeneratedRevisionSpecConverter) v1EnvironmentPatchToV1EnvironmentPatch(source EnvironmentPatch) EnvironmentPatch {
 	var v1EnvironmentPatch EnvironmentPatch
 	v1EnvironmentPatch.Type = PatchType(source.Type)
@@ -570,6 +873,12 @@ func (c *GeneratedRevisionSpecConverter) v1EnvironmentSourceSelectorLabelMatcher
 		pString2 = &xstring2
 	}
 	v1EnvironmentSourceSelectorLabelMatcher.Value = pString2
+	var pString3 *string
+	if source.NamespaceLabelKey != nil {
+		xstring3 := *source.NamespaceLabelKey
+		pString3 = &xstring3
+	}
+	v1EnvironmentSourceSelectorLabelMatcher.NamespaceLabelKey = pString3
 	return v1EnvironmentSourceSelectorLabelMatcher
 }
 func (c *GeneratedRevisionSpecConverter) v1EnvironmentSourceToV1EnvironmentSource(source EnvironmentSource) EnvironmentSource {
@@ -577,8 +886,14 @@ func (c *GeneratedRevisionSpecConverter) v1EnvironmentSourceToV1EnvironmentSourc
 	v1EnvironmentSource.Type = EnvironmentSourceType(source.Type)
 	v1EnvironmentSource.Ref = c.pV1EnvironmentSourceReferenceToPV1EnvironmentSourceReference(source.Ref)
 	v1EnvironmentSource.Selector = c.pV1EnvironmentSourceSelectorToPV1EnvironmentSourceSelector(source.Selector)
+	v1EnvironmentSource.From = c.pV1EnvironmentSourceFromToPV1EnvironmentSourceFrom(source.From)
 	return v1EnvironmentSource
 }
+func (c *GeneratedRevisionSpecConverter) v1FunctionReferenceToV1FunctionReference(source FunctionReference) FunctionReference {
+	var v1FunctionReference FunctionReference
+	v1FunctionReference.Name = source.Name
+	return v1FunctionReference
+}
 func (c *GeneratedRevisionSpecConverter) v1FunctionToV1Function(source Function) Function {
 	var v1Function Function
 	v1Function.Name = source.Name
@@ -599,6 +914,20 @@ func (c *GeneratedRevisionSpecConverter) v1JSONToV1JSON(source v12.JSON) v12.JSO
 	v1JSON.Raw = byteList
 	return v1JSON
 }
+func (c *GeneratedRevisionSpecConverter) v1LabelSelectorRequirementToV1LabelSelectorRequirement(source v11.LabelSelectorRequirement) v11.LabelSelectorRequirement {
+	var v1LabelSelectorRequirement v11.LabelSelectorRequirement
+	v1LabelSelectorRequirement.Key = source.Key
+	v1LabelSelectorRequirement.Operator = v11.LabelSelectorOperator(source.Operator)
+	var stringList []string
+	if source.Values != nil {
+		stringList = make([]string, len(source.Values))
+		for i := 0; i < len(source.Values); i++ {
+			stringList[i] = source.Values[i]
+		}
+	}
+	v1LabelSelectorRequirement.Values = stringList
+	return v1LabelSelectorRequirement
+}
 func (c *GeneratedRevisionSpecConverter) v1LocalObjectReferenceToV1LocalObjectReference(source v1.LocalObjectReference) v1.LocalObjectReference {
 	var v1LocalObjectReference v1.LocalObjectReference
 	v1LocalObjectReference.Name = source.Name
@@ -657,6 +986,12 @@ func (c *GeneratedRevisionSpecConverter) v1PatchToV1Patch(source Patch) Patch {
 		pString3 = &xstring3
 	}
 	v1Patch.PatchSetName = pString3
+	var pString4 *string
+	if source.FromResourceName != nil {
+		xstring4 := *source.FromResourceName
+		pString4 = &xstring4
+	}
+	v1Patch.FromResourceName = pString4
 	var v1TransformList []Transform
 	if source.Transforms != nil {
 		v1TransformList = make([]Transform, len(source.Transforms))
@@ -666,8 +1001,16 @@ func (c *GeneratedRevisionSpecConverter) v1PatchToV1Patch(source Patch) Patch {
 	}
 	v1Patch.Transforms = v1TransformList
 	v1Patch.Policy = c.pV1PatchPolicyToPV1PatchPolicy(source.Policy)
+	v1Patch.Condition = c.pV1ConditionToPV1Condition(source.Condition)
 	return v1Patch
 }
+func (c *GeneratedRevisionSpecConverter) v1PipelineStepToV1PipelineStep(source PipelineStep) PipelineStep {
+	var v1PipelineStep PipelineStep
+	v1PipelineStep.Step = source.Step
+	v1PipelineStep.FunctionRef = c.v1FunctionReferenceToV1FunctionReference(source.FunctionRef)
+	v1PipelineStep.Input = c.pRuntimeRawExtensionToPRuntimeRawExtension(source.Input)
+	return v1PipelineStep
+}
 func (c *GeneratedRevisionSpecConverter) v1ReadinessCheckToV1ReadinessCheck(source ReadinessCheck) ReadinessCheck {
 	var v1ReadinessCheck ReadinessCheck
 	v1ReadinessCheck.Type = ReadinessCheckType(source.Type)
@@ -675,6 +1018,7 @@ func (c *GeneratedRevisionSpecConverter) v1ReadinessCheckToV1ReadinessCheck(sour
 	v1ReadinessCheck.MatchString = source.MatchString
 	v1ReadinessCheck.MatchInteger = source.MatchInteger
 	v1ReadinessCheck.MatchCondition = c.pV1MatchConditionReadinessCheckToPV1MatchConditionReadinessCheck(source.MatchCondition)
+	v1ReadinessCheck.CEL = c.pV1CELReadinessCheckToPV1CELReadinessCheck(source.CEL)
 	return v1ReadinessCheck
 }
 func (c *GeneratedRevisionSpecConverter) v1TransformToV1Transform(source Transform) Transform {
@@ -685,6 +1029,7 @@ func (c *GeneratedRevisionSpecConverter) v1TransformToV1Transform(source Transfo
 	v1Transform.Match = c.pV1MatchTransformToPV1MatchTransform(source.Match)
 	v1Transform.String = c.pV1StringTransformToPV1StringTransform(source.String)
 	v1Transform.Convert = c.pV1ConvertTransformToPV1ConvertTransform(source.Convert)
+	v1Transform.CEL = c.pV1CELTransformToPV1CELTransform(source.CEL)
 	return v1Transform
 }
 func (c *GeneratedRevisionSpecConverter) v1TypeReferenceToV1TypeReference(source TypeReference) TypeReference {
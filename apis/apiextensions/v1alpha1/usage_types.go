@@ -0,0 +1,122 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A ResourceRef is a reference to a resource.
+type ResourceRef struct {
+	// Name of the referent.
+	Name string `json:"name"`
+}
+
+// A ResourceSelector selects a resource.
+type ResourceSelector struct {
+	// MatchLabels ensures an object with matching labels is selected.
+	// +optional
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+
+	// MatchControllerRef ensures an object with the same controller reference
+	// as the selecting object is selected.
+	// +optional
+	MatchControllerRef *bool `json:"matchControllerRef,omitempty"`
+}
+
+// A Resource is a cluster scoped managed or composite resource.
+type Resource struct {
+	// APIVersion of the referent.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referent.
+	Kind string `json:"kind"`
+
+	// Reference to the resource. Set when the selector is resolved if it
+	// isn't specified.
+	// +optional
+	ResourceRef *ResourceRef `json:"resourceRef,omitempty"`
+
+	// Selector to select the resource. It's resolved only once, when no
+	// reference is specified.
+	// +optional
+	ResourceSelector *ResourceSelector `json:"resourceSelector,omitempty"`
+}
+
+// UsageSpec defines the desired state of a Usage.
+type UsageSpec struct {
+	// Of is the resource that is being used. It can't be deleted while this
+	// Usage exists.
+	Of Resource `json:"of"`
+
+	// By is the resource that is using the other resource. This Usage is
+	// deleted once the using resource is gone. A Usage without a using
+	// resource protects the used resource from deletion, and must specify a
+	// reason.
+	// +optional
+	By *Resource `json:"by,omitempty"`
+
+	// Reason is the reason for blocking deletion of the used resource.
+	// +optional
+	Reason *string `json:"reason,omitempty"`
+}
+
+// UsageStatus defines the observed state of a Usage.
+type UsageStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +genclient
+// +genclient:nonNamespaced
+
+// A Usage defines a deletion blocking relationship between two resources. The
+// used resource can't be deleted while the Usage exists.
+// +kubebuilder:printcolumn:name="DETAILS",type="string",JSONPath=".metadata.annotations.crossplane\\.io/usage-details"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories=crossplane
+// +kubebuilder:subresource:status
+type Usage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UsageSpec   `json:"spec"`
+	Status UsageStatus `json:"status,omitempty"`
+}
+
+// GetCondition of this Usage.
+func (u *Usage) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return u.Status.GetCondition(ct)
+}
+
+// SetConditions of this Usage.
+func (u *Usage) SetConditions(c ...xpv1.Condition) {
+	u.Status.SetConditions(c...)
+}
+
+// +kubebuilder:object:root=true
+
+// UsageList contains a list of Usages.
+type UsageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Usage `json:"items"`
+}
@@ -19,7 +19,10 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -97,6 +100,37 @@ type ComposedTemplate struct {
 	// +optional
 	// +kubebuilder:default={{type:"MatchCondition",matchCondition:{type:"Ready",status:"True"}}}
 	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+
+	// Condition determines whether this resource is composed. When the
+	// condition is not met the resource is not composed, and any existing
+	// resource previously composed from this template is deleted. Resources
+	// without a condition are always composed.
+	// +optional
+	Condition *Condition `json:"condition,omitempty"`
+
+	// Count composes a number of copies of this resource read from an integer
+	// field of the composite resource. Each copy is named after this entry
+	// and its index, e.g. "nodepool-0". Requires the entry to be named. Can't
+	// be used with ForEach.
+	// +optional
+	Count *TemplateCount `json:"count,omitempty"`
+
+	// ForEach composes a copy of this resource for each element of an array
+	// field or each key of an object field of the composite resource. Copies
+	// are named after this entry and the element's key, e.g. "bucket-us-east-1".
+	// Array elements that aren't strings must be objects with a key field.
+	// Requires the entry to be named. Can't be used with Count.
+	// +optional
+	ForEach *TemplateForEach `json:"forEach,omitempty"`
+
+	// DependsOn lists the names of other entries in the resources array that
+	// this resource depends on. The resource isn't created until all of the
+	// resources it depends on are ready, and it's deleted before them when
+	// the composite resource is deleted. An entry that specifies a count or
+	// forEach may be depended on by name, in which case this resource depends
+	// on all of its copies. Requires the entry to be named.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // GetName returns the name of the composed template or an empty string if it is nil.
@@ -107,6 +141,131 @@ func (ct *ComposedTemplate) GetName() string {
 	return ""
 }
 
+// IsRepeated returns true if the composed template composes a copy of its
+// resource per element, i.e. if it specifies a count or a for-each.
+func (ct *ComposedTemplate) IsRepeated() bool {
+	return ct.Count != nil || ct.ForEach != nil
+}
+
+// A TemplateCount composes a number of copies of a composed template. Patches
+// of type FromEachFieldPath may read the "index" and "key" of each copy. The
+// key of a copy is its index formatted as a string.
+type TemplateCount struct {
+	// FromFieldPath is the path of an integer field of the composite
+	// resource. No copies are composed if the field doesn't exist. At most
+	// 100 copies may be composed.
+	FromFieldPath string `json:"fromFieldPath"`
+}
+
+// A TemplateForEach composes a copy of a composed template per element of an
+// array or object. Patches of type FromEachFieldPath may read the "index",
+// "key", and "value" of each element. The key of an array element is read
+// from KeyFieldPath if it is specified, or is the element itself otherwise.
+// The key of an object element is its key. Object elements are indexed in
+// order of their keys.
+type TemplateForEach struct {
+	// FromFieldPath is the path of an array or object field of the composite
+	// resource. No copies are composed if the field doesn't exist. The field
+	// may have at most 100 elements.
+	FromFieldPath string `json:"fromFieldPath"`
+
+	// KeyFieldPath is the path of a string field of each element of an array
+	// of objects. Copies are named after this field, which keeps their names
+	// stable as elements are added, removed, or reordered. Required when
+	// iterating an array of anything but strings.
+	// +optional
+	KeyFieldPath *string `json:"keyFieldPath,omitempty"`
+}
+
+// A ConditionSource is the resource a Condition's field path is evaluated
+// against.
+type ConditionSource string
+
+// Condition sources.
+const (
+	ConditionSourceComposite   ConditionSource = "Composite"
+	ConditionSourceEnvironment ConditionSource = "Environment"
+)
+
+// A ConditionOperator determines how a Condition's field path is evaluated.
+type ConditionOperator string
+
+// Condition operators.
+const (
+	ConditionOperatorExists       ConditionOperator = "Exists"
+	ConditionOperatorDoesNotExist ConditionOperator = "DoesNotExist"
+	ConditionOperatorEquals       ConditionOperator = "Equals"
+	ConditionOperatorNotEquals    ConditionOperator = "NotEquals"
+	ConditionOperatorIn           ConditionOperator = "In"
+	ConditionOperatorNotIn        ConditionOperator = "NotIn"
+)
+
+// A Condition is a predicate on a field of the composite resource or the
+// environment. It is used to determine whether a composed resource template is
+// rendered or a patch is applied.
+type Condition struct {
+	// Source of the field path. Either the composite resource or the
+	// environment. Defaults to Composite.
+	// +optional
+	// +kubebuilder:validation:Enum=Composite;Environment
+	// +kubebuilder:default=Composite
+	Source ConditionSource `json:"source,omitempty"`
+
+	// FieldPath is the path of the field whose value is evaluated.
+	FieldPath string `json:"fieldPath"`
+
+	// Operator determines how the field is evaluated. Exists and DoesNotExist
+	// test whether the field is set. Equals and NotEquals compare the field to
+	// Value. In and NotIn test whether the field matches any of Values. A
+	// field that does not exist is never equal to or in any value.
+	// +kubebuilder:validation:Enum=Exists;DoesNotExist;Equals;NotEquals;In;NotIn
+	Operator ConditionOperator `json:"operator"`
+
+	// Value to compare the field to. Required when operator is Equals or
+	// NotEquals.
+	// +optional
+	Value *extv1.JSON `json:"value,omitempty"`
+
+	// Values to compare the field to. Required when operator is In or NotIn.
+	// +optional
+	Values []extv1.JSON `json:"values,omitempty"`
+}
+
+// GetSource returns the source of the Condition, defaulting to Composite if
+// not specified.
+func (c *Condition) GetSource() ConditionSource {
+	if c.Source == "" {
+		return ConditionSourceComposite
+	}
+	return c.Source
+}
+
+// Validate checks if the condition is logically valid.
+func (c *Condition) Validate() *field.Error {
+	switch c.GetSource() {
+	case ConditionSourceComposite, ConditionSourceEnvironment:
+	default:
+		return field.Invalid(field.NewPath("source"), c.Source, "unknown condition source")
+	}
+	if c.FieldPath == "" {
+		return field.Required(field.NewPath("fieldPath"), "cannot be empty")
+	}
+	switch c.Operator {
+	case ConditionOperatorExists, ConditionOperatorDoesNotExist:
+	case ConditionOperatorEquals, ConditionOperatorNotEquals:
+		if c.Value == nil {
+			return field.Required(field.NewPath("value"), fmt.Sprintf("value must be set for operator %s", c.Operator))
+		}
+	case ConditionOperatorIn, ConditionOperatorNotIn:
+		if len(c.Values) == 0 {
+			return field.Required(field.NewPath("values"), fmt.Sprintf("values must be set for operator %s", c.Operator))
+		}
+	default:
+		return field.Invalid(field.NewPath("operator"), c.Operator, "unknown condition operator")
+	}
+	return nil
+}
+
 // ReadinessCheckType is used for readiness check types.
 type ReadinessCheckType string
 
@@ -118,13 +277,14 @@ const (
 	ReadinessCheckTypeMatchTrue      ReadinessCheckType = "MatchTrue"
 	ReadinessCheckTypeMatchFalse     ReadinessCheckType = "MatchFalse"
 	ReadinessCheckTypeMatchCondition ReadinessCheckType = "MatchCondition"
+	ReadinessCheckTypeCEL            ReadinessCheckType = "CEL"
 	ReadinessCheckTypeNone           ReadinessCheckType = "None"
 )
 
 // IsValid returns nil if the readiness check type is valid, or an error otherwise.
 func (t *ReadinessCheckType) IsValid() bool {
 	switch *t {
-	case ReadinessCheckTypeNonEmpty, ReadinessCheckTypeMatchString, ReadinessCheckTypeMatchInteger, ReadinessCheckTypeMatchTrue, ReadinessCheckTypeMatchFalse, ReadinessCheckTypeMatchCondition, ReadinessCheckTypeNone:
+	case ReadinessCheckTypeNonEmpty, ReadinessCheckTypeMatchString, ReadinessCheckTypeMatchInteger, ReadinessCheckTypeMatchTrue, ReadinessCheckTypeMatchFalse, ReadinessCheckTypeMatchCondition, ReadinessCheckTypeCEL, ReadinessCheckTypeNone:
 		return true
 	}
 	return false
@@ -138,7 +298,7 @@ type ReadinessCheck struct {
 	// or 0?
 
 	// Type indicates the type of probe you'd like to use.
-	// +kubebuilder:validation:Enum="MatchString";"MatchInteger";"NonEmpty";"MatchCondition";"MatchTrue";"MatchFalse";"None"
+	// +kubebuilder:validation:Enum="MatchString";"MatchInteger";"NonEmpty";"MatchCondition";"MatchTrue";"MatchFalse";"CEL";"None"
 	Type ReadinessCheckType `json:"type"`
 
 	// FieldPath shows the path of the field whose value will be used.
@@ -156,6 +316,10 @@ type ReadinessCheck struct {
 	// MatchCondition specifies the condition you'd like to match if you're using "MatchCondition" type.
 	// +optional
 	MatchCondition *MatchConditionReadinessCheck `json:"matchCondition,omitempty"`
+
+	// CEL specifies the expression you'd like to evaluate if you're using "CEL" type.
+	// +optional
+	CEL *CELReadinessCheck `json:"cel,omitempty"`
 }
 
 // MatchConditionReadinessCheck is used to indicate how to tell whether a resource is ready
@@ -170,6 +334,25 @@ type MatchConditionReadinessCheck struct {
 	Status corev1.ConditionStatus `json:"status"`
 }
 
+// CELReadinessCheck is used to tell whether a resource is ready for consumption
+// by evaluating a Common Expression Language (CEL) expression.
+type CELReadinessCheck struct {
+	// Expression is the CEL expression to evaluate. The composed resource is
+	// available to the expression as `self`. The expression must return a
+	// boolean, which is true if the composed resource is ready.
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+}
+
+// Validate checks if the CEL readiness check is logically valid. It does not
+// check whether the expression compiles.
+func (c *CELReadinessCheck) Validate() *field.Error {
+	if c == nil || c.Expression == "" {
+		return field.Required(field.NewPath("expression"), "cannot be empty for type CEL")
+	}
+	return nil
+}
+
 // Validate checks if the match condition is logically valid.
 func (m *MatchConditionReadinessCheck) Validate() *field.Error {
 	if m == nil {
@@ -207,6 +390,11 @@ func (r *ReadinessCheck) Validate() *field.Error { //nolint:gocyclo // This func
 			return errors.WrapFieldError(err, field.NewPath("matchCondition"))
 		}
 		return nil
+	case ReadinessCheckTypeCEL:
+		if err := r.CEL.Validate(); err != nil {
+			return errors.WrapFieldError(err, field.NewPath("cel"))
+		}
+		return nil
 	case ReadinessCheckTypeNonEmpty, ReadinessCheckTypeMatchFalse, ReadinessCheckTypeMatchTrue:
 		// No specific validation required.
 	}
@@ -226,6 +414,7 @@ const (
 	ConnectionDetailTypeFromConnectionSecretKey ConnectionDetailType = "FromConnectionSecretKey"
 	ConnectionDetailTypeFromFieldPath           ConnectionDetailType = "FromFieldPath"
 	ConnectionDetailTypeFromValue               ConnectionDetailType = "FromValue"
+	ConnectionDetailTypeCombine                 ConnectionDetailType = "Combine"
 )
 
 // ConnectionDetail includes the information about the propagation of the connection
@@ -245,8 +434,9 @@ type ConnectionDetail struct {
 	// 1. FromValue
 	// 2. FromConnectionSecretKey
 	// 3. FromFieldPath
+	// 4. Combine
 	// +optional
-	// +kubebuilder:validation:Enum=FromConnectionSecretKey;FromFieldPath;FromValue
+	// +kubebuilder:validation:Enum=FromConnectionSecretKey;FromFieldPath;FromValue;Combine
 	Type *ConnectionDetailType `json:"type,omitempty"`
 
 	// FromConnectionSecretKey is the key that will be used to fetch the value
@@ -265,6 +455,194 @@ type ConnectionDetail struct {
 	// value, for example a well-known port.
 	// +optional
 	Value *string `json:"value,omitempty"`
+
+	// Combine the values of several of the composed resource's connection
+	// secret keys and fields into a single value. Name must be specified if
+	// the type is Combine.
+	// +optional
+	Combine *ConnectionDetailCombine `json:"combine,omitempty"`
+
+	// Transforms are the list of functions that are used as a FIFO pipe for
+	// the connection detail's value before it is propagated.
+	// +optional
+	Transforms []Transform `json:"transforms,omitempty"`
+}
+
+// Validate the ConnectionDetail.
+func (d *ConnectionDetail) Validate() *field.Error { //nolint:gocyclo // This function is not that complex, just a switch
+	t := d.GetType()
+	switch t {
+	case ConnectionDetailTypeFromValue:
+		if d.Value == nil {
+			return field.Required(field.NewPath("value"), fmt.Sprintf("value must be set for type %s", t))
+		}
+		if d.Name == nil || *d.Name == "" {
+			return field.Required(field.NewPath("name"), fmt.Sprintf("name must be set for type %s", t))
+		}
+	case ConnectionDetailTypeFromConnectionSecretKey:
+		if d.FromConnectionSecretKey == nil || *d.FromConnectionSecretKey == "" {
+			return field.Required(field.NewPath("fromConnectionSecretKey"), fmt.Sprintf("fromConnectionSecretKey must be set for type %s", t))
+		}
+	case ConnectionDetailTypeFromFieldPath:
+		if d.FromFieldPath == nil || *d.FromFieldPath == "" {
+			return field.Required(field.NewPath("fromFieldPath"), fmt.Sprintf("fromFieldPath must be set for type %s", t))
+		}
+		if d.Name == nil || *d.Name == "" {
+			return field.Required(field.NewPath("name"), fmt.Sprintf("name must be set for type %s", t))
+		}
+	case ConnectionDetailTypeCombine:
+		if d.Combine == nil {
+			return field.Required(field.NewPath("combine"), fmt.Sprintf("combine must be set for type %s", t))
+		}
+		if err := d.Combine.Validate(); err != nil {
+			return errors.WrapFieldError(err, field.NewPath("combine"))
+		}
+		if d.Name == nil || *d.Name == "" {
+			return field.Required(field.NewPath("name"), fmt.Sprintf("name must be set for type %s", t))
+		}
+	default:
+		return field.Invalid(field.NewPath("type"), t, "unknown connection detail type")
+	}
+	for i, tr := range d.Transforms {
+		if err := tr.Validate(); err != nil {
+			return errors.WrapFieldError(err, field.NewPath("transforms").Index(i))
+		}
+	}
+	return nil
+}
+
+// GetType returns the type of the ConnectionDetail. Originally there was no
+// type field, so the type is inferred from the other fields when it is
+// omitted.
+func (d *ConnectionDetail) GetType() ConnectionDetailType {
+	if d.Type != nil {
+		return *d.Type
+	}
+	switch {
+	case d.Value != nil:
+		return ConnectionDetailTypeFromValue
+	case d.FromConnectionSecretKey != nil:
+		return ConnectionDetailTypeFromConnectionSecretKey
+	case d.FromFieldPath != nil:
+		return ConnectionDetailTypeFromFieldPath
+	case d.Combine != nil:
+		return ConnectionDetailTypeCombine
+	default:
+		// If nothing was specified, assume FromConnectionSecretKey, which was
+		// the only value we originally supported.
+		return ConnectionDetailTypeFromConnectionSecretKey
+	}
+}
+
+// A ConnectionDetailCombine combines the values of several of a composed
+// resource's connection secret keys and fields into a single string.
+type ConnectionDetailCombine struct {
+	// Variables are the list of variables whose values will be retrieved and
+	// combined.
+	// +kubebuilder:validation:MinItems=1
+	Variables []ConnectionDetailVariable `json:"variables"`
+
+	// Format the input using a Go format string. See
+	// https://golang.org/pkg/fmt/ for details. Variables are supplied to the
+	// format string in order.
+	Format string `json:"fmt"`
+}
+
+// Validate the ConnectionDetailCombine.
+func (c *ConnectionDetailCombine) Validate() *field.Error {
+	if len(c.Variables) == 0 {
+		return field.Required(field.NewPath("variables"), "at least one variable must be specified")
+	}
+	for i, v := range c.Variables {
+		if err := v.Validate(); err != nil {
+			return errors.WrapFieldError(err, field.NewPath("variables").Index(i))
+		}
+	}
+	if c.Format == "" {
+		return field.Required(field.NewPath("fmt"), "format must be specified")
+	}
+	return nil
+}
+
+// A ConnectionDetailVariable is a value to be combined. Exactly one of its
+// fields must be set.
+type ConnectionDetailVariable struct {
+	// FromConnectionSecretKey is the key that will be used to fetch the value
+	// from the composed resource's connection secret.
+	// +optional
+	FromConnectionSecretKey *string `json:"fromConnectionSecretKey,omitempty"`
+
+	// FromFieldPath is the path of the field on the composed resource whose
+	// value is to be used as input.
+	// +optional
+	FromFieldPath *string `json:"fromFieldPath,omitempty"`
+
+	// FromResourceName is the name of another entry in the resources array.
+	// If it's set the variable is read from the connection secret or fields
+	// of that entry's composed resource, rather than this one's. Like a
+	// variable read from this resource, the combined value isn't produced
+	// until the variable exists.
+	// +optional
+	FromResourceName *string `json:"fromResourceName,omitempty"`
+}
+
+// Validate the ConnectionDetailVariable.
+func (v *ConnectionDetailVariable) Validate() *field.Error {
+	switch {
+	case v.FromResourceName != nil && *v.FromResourceName == "":
+		return field.Required(field.NewPath("fromResourceName"), "cannot be empty")
+	case v.FromConnectionSecretKey != nil && v.FromFieldPath != nil:
+		return field.Invalid(field.NewPath("fromFieldPath"), *v.FromFieldPath, "only one of fromConnectionSecretKey and fromFieldPath may be set")
+	case v.FromConnectionSecretKey != nil && *v.FromConnectionSecretKey == "":
+		return field.Required(field.NewPath("fromConnectionSecretKey"), "cannot be empty")
+	case v.FromFieldPath != nil && *v.FromFieldPath == "":
+		return field.Required(field.NewPath("fromFieldPath"), "cannot be empty")
+	case v.FromConnectionSecretKey == nil && v.FromFieldPath == nil:
+		return field.Required(field.NewPath("fromConnectionSecretKey"), "one of fromConnectionSecretKey or fromFieldPath must be set")
+	}
+	return nil
+}
+
+// A CompositionMode determines what mode of Composition is used.
+type CompositionMode string
+
+const (
+	// CompositionModeResources indicates that a Composition uses what is
+	// commonly referred to as "Patch & Transform" or P&T composition. This
+	// mode of Composition uses an array of resources, each a template for a
+	// composed resource. It may optionally be followed by an array of
+	// functions.
+	CompositionModeResources CompositionMode = "Resources"
+
+	// CompositionModePipeline indicates that a Composition specifies a
+	// pipeline of Composition Functions, each of which is responsible for
+	// producing composed resources that Crossplane should create or update.
+	CompositionModePipeline CompositionMode = "Pipeline"
+)
+
+// A PipelineStep in a Composition Function pipeline.
+type PipelineStep struct {
+	// Step name. Must be unique within its Pipeline.
+	Step string `json:"step"`
+
+	// FunctionRef is a reference to the Composition Function this step should
+	// execute.
+	FunctionRef FunctionReference `json:"functionRef"`
+
+	// Input is an optional, arbitrary Kubernetes resource (i.e. a resource
+	// with an apiVersion and kind) that will be passed to the Composition
+	// Function as the 'input' of its RunFunctionRequest.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:EmbeddedResource
+	Input *runtime.RawExtension `json:"input,omitempty"`
+}
+
+// A FunctionReference references a Composition Function that may be used in a
+// Composition pipeline.
+type FunctionReference struct {
+	// Name of the referenced Function.
+	Name string `json:"name"`
 }
 
 // A Function represents a Composition Function.
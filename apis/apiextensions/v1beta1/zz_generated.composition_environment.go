@@ -19,7 +19,10 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -52,6 +55,13 @@ type EnvironmentConfiguration struct {
 	// all EnvironmentSourceReferences in EnvironmentConfigs list.
 	// +optional
 	Policy *xpv1.Policy `json:"policy,omitempty"`
+
+	// AllowDebug allows composite resources to record the merged environment,
+	// and the source of each of its top-level keys, in their status.
+	// Composite resources opt in using the crossplane.io/debug-environment
+	// annotation. Values read from Secrets are redacted.
+	// +optional
+	AllowDebug *bool `json:"allowDebug,omitempty"`
 }
 
 // Validate the EnvironmentConfiguration.
@@ -87,6 +97,12 @@ func (e *EnvironmentConfiguration) ShouldResolve(currentRefs []corev1.ObjectRefe
 	return e.Policy.IsResolvePolicyAlways()
 }
 
+// IsDebugAllowed specifies whether composite resources may record a debug view
+// of their environment in their status.
+func (e *EnvironmentConfiguration) IsDebugAllowed() bool {
+	return e != nil && e.AllowDebug != nil && *e.AllowDebug
+}
+
 // IsRequired specifies whether EnvironmentConfiguration is required or not.
 func (e *EnvironmentConfiguration) IsRequired() bool {
 	if e == nil {
@@ -122,6 +138,11 @@ type EnvironmentSource struct {
 	// Selector selects EnvironmentConfig(s) via labels.
 	// +optional
 	Selector *EnvironmentSourceSelector `json:"selector,omitempty"`
+
+	// From specifies the kind of object environment data is read from. Data
+	// is read from EnvironmentConfigs if From is not specified.
+	// +optional
+	From *EnvironmentSourceFrom `json:"from,omitempty"`
 }
 
 // Validate the EnvironmentSource.
@@ -139,8 +160,8 @@ func (e *EnvironmentSource) Validate() *field.Error {
 		if e.Selector == nil {
 			return field.Required(field.NewPath("selector"), "selector is required")
 		}
-		if len(e.Selector.MatchLabels) == 0 {
-			return field.Required(field.NewPath("selector", "matchLabels"), "selector must have at least one match label")
+		if len(e.Selector.MatchLabels) == 0 && len(e.Selector.MatchExpressions) == 0 {
+			return field.Required(field.NewPath("selector", "matchLabels"), "selector must have at least one match label or match expression")
 		}
 
 		if err := e.Selector.Validate(); err != nil {
@@ -149,6 +170,92 @@ func (e *EnvironmentSource) Validate() *field.Error {
 	default:
 		return field.Invalid(field.NewPath("type"), e.Type, "invalid type")
 	}
+	if e.From != nil {
+		if err := e.From.Validate(); err != nil {
+			return errors.WrapFieldError(err, field.NewPath("from"))
+		}
+	}
+	return nil
+}
+
+// EnvironmentSourceFromType specifies the kind of object environment data is
+// read from.
+type EnvironmentSourceFromType string
+
+const (
+	// EnvironmentSourceFromTypeEnvironmentConfig reads the data of an
+	// EnvironmentConfig.
+	EnvironmentSourceFromTypeEnvironmentConfig EnvironmentSourceFromType = "EnvironmentConfig"
+	// EnvironmentSourceFromTypeConfigMap reads the data of a ConfigMap.
+	EnvironmentSourceFromTypeConfigMap EnvironmentSourceFromType = "ConfigMap"
+	// EnvironmentSourceFromTypeSecret reads the data of a Secret.
+	EnvironmentSourceFromTypeSecret EnvironmentSourceFromType = "Secret"
+	// EnvironmentSourceFromTypeObject reads the value of a field of an
+	// arbitrary object.
+	EnvironmentSourceFromTypeObject EnvironmentSourceFromType = "Object"
+)
+
+// EnvironmentSourceFrom specifies the kind of object environment data is read
+// from.
+type EnvironmentSourceFrom struct {
+	// Type of object environment data is read from. ConfigMap and Secret
+	// data is read as string values. Secrets are only read if support for
+	// them is enabled, and their values are redacted from events. Object
+	// data is read from the field at FieldPath, which must be an object.
+	// +optional
+	// +kubebuilder:validation:Enum=EnvironmentConfig;ConfigMap;Secret;Object
+	// +kubebuilder:default=EnvironmentConfig
+	Type EnvironmentSourceFromType `json:"type,omitempty"`
+
+	// APIVersion of the object. Required when type is Object.
+	// +optional
+	APIVersion *string `json:"apiVersion,omitempty"`
+
+	// Kind of the object. Required when type is Object.
+	// +optional
+	Kind *string `json:"kind,omitempty"`
+
+	// Namespace of the object. Required when type is ConfigMap or Secret,
+	// and when type is Object and the object is namespaced.
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// FieldPath of the object field environment data is read from. Required
+	// when type is Object.
+	// +optional
+	FieldPath *string `json:"fieldPath,omitempty"`
+}
+
+// GetType returns the type of the source, returning the default if not set.
+func (e *EnvironmentSourceFrom) GetType() EnvironmentSourceFromType {
+	if e == nil || e.Type == "" {
+		return EnvironmentSourceFromTypeEnvironmentConfig
+	}
+	return e.Type
+}
+
+// Validate logically validates the EnvironmentSourceFrom.
+func (e *EnvironmentSourceFrom) Validate() *field.Error {
+	switch e.GetType() {
+	case EnvironmentSourceFromTypeEnvironmentConfig:
+		return nil
+	case EnvironmentSourceFromTypeConfigMap, EnvironmentSourceFromTypeSecret:
+		if e.Namespace == nil || *e.Namespace == "" {
+			return field.Required(field.NewPath("namespace"), fmt.Sprintf("namespace is required by type %s", e.GetType()))
+		}
+	case EnvironmentSourceFromTypeObject:
+		if e.APIVersion == nil || *e.APIVersion == "" {
+			return field.Required(field.NewPath("apiVersion"), "apiVersion is required by type Object")
+		}
+		if e.Kind == nil || *e.Kind == "" {
+			return field.Required(field.NewPath("kind"), "kind is required by type Object")
+		}
+		if e.FieldPath == nil || *e.FieldPath == "" {
+			return field.Required(field.NewPath("fieldPath"), "fieldPath is required by type Object")
+		}
+	default:
+		return field.Invalid(field.NewPath("type"), e.Type, "invalid type")
+	}
 	return nil
 }
 
@@ -195,6 +302,11 @@ type EnvironmentSourceSelector struct {
 
 	// MatchLabels ensures an object with matching labels is selected.
 	MatchLabels []EnvironmentSourceSelectorLabelMatcher `json:"matchLabels,omitempty"`
+
+	// MatchExpressions ensures an object whose labels satisfy all of the
+	// label selector requirements is selected.
+	// +optional
+	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty"`
 }
 
 // Validate logically validates the EnvironmentSourceSelector.
@@ -210,6 +322,12 @@ func (e *EnvironmentSourceSelector) Validate() *field.Error {
 		}
 	}
 
+	for i, r := range e.MatchExpressions {
+		if _, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{r}}); err != nil {
+			return field.Invalid(field.NewPath("matchExpressions").Index(i), r, err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -224,6 +342,13 @@ const (
 	// EnvironmentSourceSelectorLabelMatcherTypeValue uses a literal as label
 	// value.
 	EnvironmentSourceSelectorLabelMatcherTypeValue EnvironmentSourceSelectorLabelMatcherType = "Value"
+	// EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespace uses the
+	// namespace of the composite resource's claim as label value.
+	EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespace EnvironmentSourceSelectorLabelMatcherType = "FromClaimNamespace"
+	// EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespaceLabel uses
+	// the value of a label of the composite resource's claim's namespace as
+	// label value.
+	EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespaceLabel EnvironmentSourceSelectorLabelMatcherType = "FromClaimNamespaceLabel"
 )
 
 // An EnvironmentSourceSelectorLabelMatcher acts like a k8s label selector but
@@ -231,7 +356,7 @@ const (
 type EnvironmentSourceSelectorLabelMatcher struct {
 	// Type specifies where the value for a label comes from.
 	// +optional
-	// +kubebuilder:validation:Enum=FromCompositeFieldPath;Value
+	// +kubebuilder:validation:Enum=FromCompositeFieldPath;Value;FromClaimNamespace;FromClaimNamespaceLabel
 	// +kubebuilder:default=FromCompositeFieldPath
 	Type EnvironmentSourceSelectorLabelMatcherType `json:"type,omitempty"`
 
@@ -243,6 +368,11 @@ type EnvironmentSourceSelectorLabelMatcher struct {
 
 	// Value specifies a literal label value.
 	Value *string `json:"value,omitempty"`
+
+	// NamespaceLabelKey specifies the key of the claim namespace's label
+	// whose value to use. Required when type is FromClaimNamespaceLabel.
+	// +optional
+	NamespaceLabelKey *string `json:"namespaceLabelKey,omitempty"`
 }
 
 // GetType returns the type of the label matcher, returning the default if not set.
@@ -273,6 +403,15 @@ func (e *EnvironmentSourceSelectorLabelMatcher) Validate() *field.Error {
 		if *e.Value == "" {
 			return field.Required(field.NewPath("value"), "value must not be empty")
 		}
+	case EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespace:
+		// The claim's namespace is the value; there's nothing to configure.
+	case EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespaceLabel:
+		if e.NamespaceLabelKey == nil {
+			return field.Required(field.NewPath("namespaceLabelKey"), "namespaceLabelKey is required")
+		}
+		if *e.NamespaceLabelKey == "" {
+			return field.Required(field.NewPath("namespaceLabelKey"), "namespaceLabelKey must not be empty")
+		}
 	default:
 		return field.Invalid(field.NewPath("type"), e.Type, "invalid type")
 	}
@@ -290,7 +429,8 @@ type EnvironmentPatch struct {
 
 	// FromFieldPath is the path of the field on the resource whose value is
 	// to be used as input. Required when type is FromCompositeFieldPath or
-	// ToCompositeFieldPath.
+	// ToCompositeFieldPath. Use [*] wildcards to patch each element of an
+	// array individually, applying any transforms to each element.
 	// +optional
 	FromFieldPath *string `json:"fromFieldPath,omitempty"`
 
@@ -301,7 +441,12 @@ type EnvironmentPatch struct {
 
 	// ToFieldPath is the path of the field on the resource whose value will
 	// be changed with the result of transforms. Leave empty if you'd like to
-	// propagate to the same path as fromFieldPath.
+	// propagate to the same path as fromFieldPath. When fromFieldPath contains
+	// wildcards toFieldPath may contain either no wildcards, in which case the
+	// transformed elements are patched as an array, or exactly one wildcard to
+	// match a single wildcard in fromFieldPath, in which case each transformed
+	// element is patched to the element at the same index and the destination
+	// array is truncated to the length of the source array.
 	// +optional
 	ToFieldPath *string `json:"toFieldPath,omitempty"`
 
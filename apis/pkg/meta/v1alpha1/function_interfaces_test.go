@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+func TestFunctionGetDependencies(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		f      *Function
+		want   []v1.Dependency
+	}{
+		"NoDependencies": {
+			reason: "A Function without dependencies should return none.",
+			f:      &Function{},
+		},
+		"Dependencies": {
+			reason: "A Function's Provider, Configuration, and Function dependencies should all be returned.",
+			f: &Function{Spec: FunctionSpec{MetaSpec: MetaSpec{DependsOn: []Dependency{
+				{Provider: pointer.String("xpkg.upbound.io/cool/provider"), Version: ">=v1.0.0"},
+				{Configuration: pointer.String("xpkg.upbound.io/cool/configuration"), Version: ">=v0.1.0"},
+				{Function: pointer.String("xpkg.upbound.io/cool/function"), Version: ">=v0.2.0"},
+			}}}},
+			want: []v1.Dependency{
+				{Provider: pointer.String("xpkg.upbound.io/cool/provider"), Version: ">=v1.0.0"},
+				{Configuration: pointer.String("xpkg.upbound.io/cool/configuration"), Version: ">=v0.1.0"},
+				{Function: pointer.String("xpkg.upbound.io/cool/function"), Version: ">=v0.2.0"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.f.GetDependencies()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetDependencies(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
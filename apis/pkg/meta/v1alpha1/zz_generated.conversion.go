@@ -78,6 +78,12 @@ func (c *GeneratedFromHubConverter) v1DependencyToV1alpha1Dependency(source v1.D
 		pString2 = &xstring2
 	}
 	v1alpha1Dependency.Configuration = pString2
+	var pString3 *string
+	if source.Function != nil {
+		xstring3 := *source.Function
+		pString3 = &xstring3
+	}
+	v1alpha1Dependency.Function = pString3
 	v1alpha1Dependency.Version = source.Version
 	return v1alpha1Dependency
 }
@@ -271,6 +277,12 @@ func (c *GeneratedToHubConverter) v1alpha1DependencyToV1Dependency(source Depend
 		pString2 = &xstring2
 	}
 	v1Dependency.Configuration = pString2
+	var pString3 *string
+	if source.Function != nil {
+		xstring3 := *source.Function
+		pString3 = &xstring3
+	}
+	v1Dependency.Function = pString3
 	v1Dependency.Version = source.Version
 	return v1Dependency
 }
@@ -33,7 +33,8 @@ type CrossplaneConstraints struct {
 	Version string `json:"version"`
 }
 
-// Dependency is a dependency on another package. One of Provider or Configuration may be supplied.
+// Dependency is a dependency on another package. One of Provider,
+// Configuration or Function may be supplied.
 type Dependency struct {
 	// Provider is the name of a Provider package image.
 	Provider *string `json:"provider,omitempty"`
@@ -41,6 +42,9 @@ type Dependency struct {
 	// Configuration is the name of a Configuration package image.
 	Configuration *string `json:"configuration,omitempty"`
 
+	// Function is the name of a Function package image.
+	Function *string `json:"function,omitempty"`
+
 	// Version is the semantic version constraints of the dependency image.
 	Version string `json:"version"`
 }
@@ -49,6 +49,14 @@ var (
 )
 
 // Function type metadata.
+var (
+	FunctionKind             = reflect.TypeOf(Function{}).Name()
+	FunctionGroupKind        = schema.GroupKind{Group: Group, Kind: FunctionKind}.String()
+	FunctionKindAPIVersion   = FunctionKind + "." + SchemeGroupVersion.String()
+	FunctionGroupVersionKind = SchemeGroupVersion.WithKind(FunctionKind)
+)
+
+// FunctionRevision type metadata.
 var (
 	FunctionRevisionKind             = reflect.TypeOf(FunctionRevision{}).Name()
 	FunctionRevisionGroupKind        = schema.GroupKind{Group: Group, Kind: FunctionRevisionKind}.String()
@@ -58,4 +66,6 @@ var (
 
 func init() {
 	SchemeBuilder.Register(&ControllerConfig{}, &ControllerConfigList{})
+	SchemeBuilder.Register(&Function{}, &FunctionList{})
+	SchemeBuilder.Register(&FunctionRevision{}, &FunctionRevisionList{})
 }
@@ -334,6 +334,22 @@ func (in *FunctionRevisionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionRevisionStatus) DeepCopyInto(out *FunctionRevisionStatus) {
+	*out = *in
+	in.PackageRevisionStatus.DeepCopyInto(&out.PackageRevisionStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionRevisionStatus.
+func (in *FunctionRevisionStatus) DeepCopy() *FunctionRevisionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionRevisionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionSpec) DeepCopyInto(out *FunctionSpec) {
 	*out = *in
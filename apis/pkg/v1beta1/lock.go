@@ -32,6 +32,7 @@ type PackageType string
 const (
 	ConfigurationPackageType PackageType = "Configuration"
 	ProviderPackageType      PackageType = "Provider"
+	FunctionPackageType      PackageType = "Function"
 )
 
 // LockPackage is a package that is in the lock.
@@ -39,7 +40,8 @@ type LockPackage struct {
 	// Name corresponds to the name of the package revision for this package.
 	Name string `json:"name"`
 
-	// Type is the type of package. Can be either Configuration or Provider.
+	// Type is the type of package. Can be Configuration, Provider or
+	// Function.
 	Type PackageType `json:"type"`
 
 	// Source is the OCI image name without a tag or digest.
@@ -90,7 +92,8 @@ type Dependency struct {
 	// Package is the OCI image name without a tag or digest.
 	Package string `json:"package"`
 
-	// Type is the type of package. Can be either Configuration or Provider.
+	// Type is the type of package. Can be Configuration, Provider or
+	// Function.
 	Type PackageType `json:"type"`
 
 	// Constraints is a valid semver range, which will be used to select a valid
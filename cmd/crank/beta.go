@@ -0,0 +1,24 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+// betaCmd contains commands that are in beta. Beta commands may change in
+// backward incompatible ways between releases.
+type betaCmd struct {
+	Render renderCmd `cmd:"" help:"Render a composite resource (XR) using a Composition."`
+	Trace  traceCmd  `cmd:"" help:"Trace a claim or composite resource (XR) through to its composed resources."`
+}
@@ -19,7 +19,10 @@ package main
 import (
 	"context"
 	"path/filepath"
+	"strings"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/spf13/afero"
 
@@ -35,12 +38,17 @@ const (
 	errBuildPackage    = "failed to build package"
 	errImageDigest     = "failed to get package digest"
 	errCreatePackage   = "failed to create package file"
+
+	errLoadRuntimeTarball = "failed to load runtime image tarball"
+	errLoadRuntimeLayout  = "failed to load runtime image OCI layout"
+	errNoRuntimeImages    = "OCI layout contains no runtime images"
 )
 
 // buildCmd builds a package.
 type buildCmd struct {
 	Configuration buildConfigCmd   `cmd:"" help:"Build a Configuration package."`
 	Provider      buildProviderCmd `cmd:"" help:"Build a Provider package."`
+	Function      buildFunctionCmd `cmd:"" help:"Build a Function package."`
 
 	PackageRoot string   `short:"f" help:"Path to package directory." default:"."`
 	Ignore      []string `help:"Paths, specified relative to --package-root, to exclude from the package."`
@@ -65,45 +73,162 @@ func (c *buildCmd) Run(child *buildChild, logger logging.Logger) error {
 		return errors.New("cannot build object scheme for package parser")
 	}
 	logger.Debug("Successfully built Object scheme for package parser")
-	img, err := xpkg.Build(context.Background(),
-		parser.NewFsBackend(child.fs, parser.FsDir(root), parser.FsFilters(buildFilters(root, c.Ignore)...)),
-		parser.New(metaScheme, objScheme),
-		child.linter)
-	if err != nil {
-		logger.Debug(errBuildPackage, "error", err)
-		return errors.Wrap(err, errBuildPackage)
+
+	// Packages without a runtime image are built from scratch. Otherwise we
+	// build one package per runtime image (i.e. per platform).
+	runtimes := child.runtimes
+	if len(runtimes) == 0 {
+		runtimes = []runtimeImage{{}}
 	}
-	logger.Debug("Successfully built package")
 
-	hash, err := img.Digest()
-	if err != nil {
-		logger.Debug(errImageDigest, "error", err)
-		return errors.Wrap(err, errImageDigest)
-	}
-	logger.Debug("Successfully found package digest")
-	pkgName := child.name
-	if pkgName == "" {
-		metaPath := filepath.Join(root, xpkg.MetaFile)
-		pkgName, err = xpkg.ParseNameFromMeta(child.fs, metaPath)
+	for _, rt := range runtimes {
+		opts := make([]xpkg.BuildOption, 0, 1)
+		if rt.image != nil {
+			opts = append(opts, xpkg.WithBase(rt.image))
+		}
+		img, err := xpkg.Build(context.Background(),
+			parser.NewFsBackend(child.fs, parser.FsDir(root), parser.FsFilters(buildFilters(root, c.Ignore)...)),
+			parser.New(metaScheme, objScheme),
+			child.linter,
+			opts...)
 		if err != nil {
-			logger.Debug(errGetNameFromMeta, "error", err)
-			return errors.Wrap(err, errGetNameFromMeta)
+			logger.Debug(errBuildPackage, "error", err)
+			return errors.Wrap(err, errBuildPackage)
+		}
+		logger.Debug("Successfully built package")
+
+		hash, err := img.Digest()
+		if err != nil {
+			logger.Debug(errImageDigest, "error", err)
+			return errors.Wrap(err, errImageDigest)
+		}
+		logger.Debug("Successfully found package digest")
+		pkgName := child.name
+		if pkgName == "" {
+			metaPath := filepath.Join(root, xpkg.MetaFile)
+			pkgName, err = xpkg.ParseNameFromMeta(child.fs, metaPath)
+			if err != nil {
+				logger.Debug(errGetNameFromMeta, "error", err)
+				return errors.Wrap(err, errGetNameFromMeta)
+			}
+			pkgName = xpkg.FriendlyID(pkgName, hash.Hex)
+		}
+
+		// Distinguish the packages built for each platform.
+		if len(runtimes) > 1 {
+			pkgName = pkgName + "-" + platformSuffix(rt.platform)
+		}
+
+		if err := writePackage(child.fs, xpkg.BuildPath(root, pkgName, xpkg.XpkgExtension), img); err != nil {
+			logger.Debug("Failed to write package image", "error", err)
+			return err
 		}
-		pkgName = xpkg.FriendlyID(pkgName, hash.Hex)
+		logger.Debug("Successfully wrote package image file", "path", xpkg.BuildPath(root, pkgName, xpkg.XpkgExtension))
 	}
+	return nil
+}
 
-	f, err := child.fs.Create(xpkg.BuildPath(root, pkgName, xpkg.XpkgExtension))
+func writePackage(fs afero.Fs, path string, img v1.Image) error {
+	f, err := fs.Create(path)
 	if err != nil {
-		logger.Debug(errCreatePackage, "error", err)
 		return errors.Wrap(err, errCreatePackage)
 	}
-	logger.Debug("Successfully created package image file")
 	defer func() { _ = f.Close() }()
-	if err := tarball.Write(nil, img, f); err != nil {
-		logger.Debug("Failed to write package image", "error", err)
-		return err
+	return tarball.Write(nil, img, f)
+}
+
+// platformSuffix returns a suffix that identifies the supplied platform, e.g.
+// linux-arm64-v8.
+func platformSuffix(p *v1.Platform) string {
+	if p == nil {
+		return "unknown"
 	}
-	return nil
+	parts := []string{p.OS, p.Architecture}
+	if p.Variant != "" {
+		parts = append(parts, p.Variant)
+	}
+	return strings.Join(parts, "-")
+}
+
+// A runtimeImage is an image that contains the runtime of a package, for
+// example a Function.
+type runtimeImage struct {
+	platform *v1.Platform
+	image    v1.Image
+}
+
+// loadRuntimeImageTarball loads a runtime image from a tarball, such as one
+// produced by docker save.
+func loadRuntimeImageTarball(path string) ([]runtimeImage, error) {
+	img, err := tarball.ImageFromPath(path, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadRuntimeTarball)
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadRuntimeTarball)
+	}
+	return []runtimeImage{{platform: cfg.Platform(), image: img}}, nil
+}
+
+// loadRuntimeImageLayout loads all runtime images from an OCI image layout
+// directory, such as one produced by docker buildx build --output type=oci.
+// The layout may contain images for several platforms.
+func loadRuntimeImageLayout(path string) ([]runtimeImage, error) {
+	idx, err := layout.ImageIndexFromPath(path)
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadRuntimeLayout)
+	}
+	out, err := runtimeImagesFromIndex(idx)
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadRuntimeLayout)
+	}
+	if len(out) == 0 {
+		return nil, errors.New(errNoRuntimeImages)
+	}
+	return out, nil
+}
+
+func runtimeImagesFromIndex(idx v1.ImageIndex) ([]runtimeImage, error) {
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]runtimeImage, 0, len(m.Manifests))
+	for _, d := range m.Manifests {
+		switch {
+		case d.MediaType.IsIndex():
+			nested, err := idx.ImageIndex(d.Digest)
+			if err != nil {
+				return nil, err
+			}
+			imgs, err := runtimeImagesFromIndex(nested)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, imgs...)
+		case d.MediaType.IsImage():
+			// Skip attestations and other artifacts that don't target a
+			// platform.
+			if d.Platform != nil && d.Platform.OS == "unknown" {
+				continue
+			}
+			img, err := idx.Image(d.Digest)
+			if err != nil {
+				return nil, err
+			}
+			p := d.Platform
+			if p == nil {
+				cfg, err := img.ConfigFile()
+				if err != nil {
+					return nil, err
+				}
+				p = cfg.Platform()
+			}
+			out = append(out, runtimeImage{platform: p, image: img})
+		}
+	}
+	return out, nil
 }
 
 // default build filters skip directories, empty files, and files without YAML
@@ -123,9 +248,10 @@ func buildFilters(root string, skips []string) []parser.FilterFn {
 }
 
 type buildChild struct {
-	name   string
-	linter parser.Linter
-	fs     afero.Fs
+	name     string
+	linter   parser.Linter
+	fs       afero.Fs
+	runtimes []runtimeImage
 }
 
 // buildConfigCmd builds a Configuration.
@@ -151,3 +277,27 @@ func (c buildProviderCmd) AfterApply(b *buildChild) error { //nolint:unparam //
 	b.linter = xpkg.NewProviderLinter()
 	return nil
 }
+
+// buildFunctionCmd builds a Function.
+type buildFunctionCmd struct {
+	Name string `optional:"" help:"Name of the package to be built. Uses name in crossplane.yaml if not specified. Does not correspond to package tag."`
+
+	EmbedRuntimeImageTarball string `type:"path" placeholder:"PATH" xor:"runtime" help:"An OCI image tarball (e.g. produced by docker save) containing the Function's runtime. It will be embedded in the package."`
+	EmbedRuntimeImageLayout  string `type:"path" placeholder:"PATH" xor:"runtime" help:"An OCI image layout directory containing the Function's runtime. One package will be built for each platform in the layout."`
+}
+
+// AfterApply sets the name, linter, and runtime images for the parent build
+// command.
+func (c buildFunctionCmd) AfterApply(b *buildChild) error {
+	b.name = c.Name
+	b.linter = xpkg.NewFunctionLinter()
+
+	var err error
+	switch {
+	case c.EmbedRuntimeImageTarball != "":
+		b.runtimes, err = loadRuntimeImageTarball(c.EmbedRuntimeImageTarball)
+	case c.EmbedRuntimeImageLayout != "":
+		b.runtimes, err = loadRuntimeImageLayout(c.EmbedRuntimeImageLayout)
+	}
+	return err
+}
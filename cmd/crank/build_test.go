@@ -17,10 +17,17 @@ limitations under the License.
 package main
 
 import (
+	"io"
 	"os"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/spf13/afero"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -87,3 +94,80 @@ func TestBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildRuntimes(t *testing.T) {
+	amd64, _ := random.Image(1024, 1)
+	arm64, _ := random.Image(1024, 1)
+
+	fs := afero.NewMemMapFs()
+	child := &buildChild{
+		name:   "test",
+		linter: parser.NewPackageLinter(nil, nil, nil),
+		fs:     fs,
+		runtimes: []runtimeImage{
+			{platform: &v1.Platform{OS: "linux", Architecture: "amd64"}, image: amd64},
+			{platform: &v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}, image: arm64},
+		},
+	}
+
+	b := buildCmd{PackageRoot: "/"}
+	if err := b.Run(child, logging.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+
+	// We should build one package per runtime image, each with the runtime
+	// image's layer plus the package layer.
+	for _, path := range []string{"/test-linux-amd64.xpkg", "/test-linux-arm64-v8.xpkg"} {
+		path := path
+		img, err := tarball.Image(func() (io.ReadCloser, error) { return fs.Open(path) }, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l, err := img.Layers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(2, len(l)); diff != "" {
+			t.Errorf("%s: -want layers, +got layers:\n%s", path, diff)
+		}
+	}
+}
+
+func TestLoadRuntimeImageLayout(t *testing.T) {
+	amd64, _ := random.Image(1024, 1)
+	arm64, _ := random.Image(1024, 1)
+
+	// Layouts produced by docker buildx contain an index that points to a
+	// multi-platform index.
+	idx := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: amd64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}}},
+		mutate.IndexAddendum{Add: arm64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "arm64"}}},
+	)
+
+	dir := t.TempDir()
+	p, err := layout.Write(dir, empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AppendIndex(idx); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadRuntimeImageLayout(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"linux/amd64", "linux/arm64"}
+	platforms := make([]string, 0, len(got))
+	for _, rt := range got {
+		platforms = append(platforms, rt.platform.String())
+	}
+	if diff := cmp.Diff(want, platforms); diff != "" {
+		t.Errorf("loadRuntimeImageLayout(...): -want platforms, +got platforms:\n%s", diff)
+	}
+
+	if _, err := loadRuntimeImageLayout(t.TempDir()); err == nil {
+		t.Errorf("loadRuntimeImageLayout(...): expected an error loading an empty directory")
+	}
+}
@@ -36,7 +36,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	typedclient "github.com/crossplane/crossplane/internal/client/clientset/versioned/typed/pkg/v1"
+	fnclient "github.com/crossplane/crossplane/internal/client/clientset/versioned/typed/pkg/v1alpha1"
 	"github.com/crossplane/crossplane/internal/version"
 	"github.com/crossplane/crossplane/internal/xpkg"
 
@@ -49,8 +51,9 @@ const (
 	errKubeConfig    = "failed to get kubeconfig"
 	errKubeClient    = "failed to create kube client"
 
-	errFmtPkgNotReadyTimeout = "%s is not ready in timeout duration"
-	errFmtWatchPkg           = "Failed to watch for %s object"
+	errFmtPkgNotReadyTimeout       = "%s is not ready in timeout duration"
+	errFmtPkgNotReadyTimeoutReason = "%s is not ready in timeout duration: %s"
+	errFmtWatchPkg                 = "Failed to watch for %s object"
 )
 
 const (
@@ -61,12 +64,17 @@ const (
 	msgProviderReady    = "Provider is ready"
 	msgProviderNotReady = "Provider is not ready"
 	msgProviderWaiting  = "Waiting for the Provider to be ready"
+
+	msgFunctionReady    = "Function is ready"
+	msgFunctionNotReady = "Function is not ready"
+	msgFunctionWaiting  = "Waiting for the Function to be ready"
 )
 
 // installCmd installs a package.
 type installCmd struct {
 	Configuration installConfigCmd   `cmd:"" help:"Install a Configuration package."`
 	Provider      installProviderCmd `cmd:"" help:"Install a Provider package."`
+	Function      installFunctionCmd `cmd:"" help:"Install a Function package."`
 }
 
 // Run runs the install cmd.
@@ -264,6 +272,155 @@ func (c *installProviderCmd) Run(k *kong.Context, logger logging.Logger) error {
 	return err
 }
 
+// installFunctionCmd installs a Function.
+type installFunctionCmd struct {
+	Package string `arg:"" help:"Image containing Function package."`
+
+	Name                 string        `arg:"" optional:"" help:"Name of Function."`
+	Wait                 time.Duration `short:"w" help:"Wait for installation of package."`
+	RevisionHistoryLimit int64         `short:"r" help:"Revision history limit."`
+	ManualActivation     bool          `short:"m" help:"Enable manual revision activation policy."`
+	PackagePullSecrets   []string      `help:"List of secrets used to pull package."`
+}
+
+// Run runs the Function install cmd.
+func (c *installFunctionCmd) Run(k *kong.Context, logger logging.Logger) error {
+	rap := v1.AutomaticActivation
+	if c.ManualActivation {
+		rap = v1.ManualActivation
+	}
+	pkgName := c.Name
+	if pkgName == "" {
+		ref, err := name.ParseReference(c.Package)
+		if err != nil {
+			logger.Debug(errPkgIdentifier, "error", err)
+			return errors.Wrap(err, errPkgIdentifier)
+		}
+		pkgName = xpkg.ToDNSLabel(ref.Context().RepositoryStr())
+	}
+	logger = logger.WithValues("functionName", pkgName)
+	packagePullSecrets := make([]corev1.LocalObjectReference, len(c.PackagePullSecrets))
+	for i, s := range c.PackagePullSecrets {
+		packagePullSecrets[i] = corev1.LocalObjectReference{
+			Name: s,
+		}
+	}
+	fn := &v1alpha1.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: pkgName,
+		},
+		Spec: v1alpha1.FunctionSpec{
+			PackageSpec: v1.PackageSpec{
+				Package:                  c.Package,
+				RevisionActivationPolicy: &rap,
+				RevisionHistoryLimit:     &c.RevisionHistoryLimit,
+				PackagePullSecrets:       packagePullSecrets,
+			},
+		},
+	}
+	kubeConfig, err := ctrl.GetConfig()
+	if err != nil {
+		logger.Debug(errKubeConfig, "error", err)
+		return errors.Wrap(err, errKubeConfig)
+	}
+	logger.Debug("Found kubeconfig")
+	kube, err := fnclient.NewForConfig(kubeConfig)
+	if err != nil {
+		logger.Debug(errKubeClient, "error", err)
+		return errors.Wrap(err, errKubeClient)
+	}
+	logger.Debug("Created kubernetes client")
+	res, err := kube.Functions().Create(context.Background(), fn, metav1.CreateOptions{})
+	if err != nil {
+		logger.Debug("Failed to create function", "error", warnIfNotFound(err))
+		return errors.Wrap(warnIfNotFound(err), "cannot create function")
+	}
+	if c.Wait != 0 {
+		logger.Debug(msgFunctionWaiting)
+		watchList := cache.NewListWatchFromClient(kube.RESTClient(), "functions", corev1.NamespaceAll, fields.Everything())
+		if err := waitForFunction(context.Background(), kube, watchList, pkgName, c.Package, c.Wait, logger); err != nil {
+			logger.Debug("Failed to wait for function", "error", err)
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(k.Stdout, "%s/%s created\n", strings.ToLower(v1alpha1.FunctionGroupKind), res.GetName())
+	return err
+}
+
+// waitForFunction watches the named Function until its current revision is of
+// the supplied package and healthy, or the supplied timeout expires. If the
+// Function's current revision is unhealthy when the timeout expires the
+// revision's failure reason is returned as part of the error.
+func waitForFunction(ctx context.Context, kube fnclient.PkgV1alpha1Interface, w cache.Watcher, name, pkg string, timeout time.Duration, logger logging.Logger) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	waitSeconds := int64(timeout.Seconds())
+	watcher, err := w.Watch(metav1.ListOptions{Watch: true, TimeoutSeconds: &waitSeconds})
+	if err != nil {
+		logger.Debug(fmt.Sprintf(errFmtWatchPkg, "Function"), "error", err)
+		return err
+	}
+	defer watcher.Stop()
+
+	reason := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return functionNotReady(reason)
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return functionNotReady(reason)
+			}
+			fn, ok := event.Object.(*v1alpha1.Function)
+			if !ok || fn.GetName() != name {
+				continue
+			}
+			// Until the Function moves to the supplied package its
+			// conditions describe a previous revision.
+			if fn.GetCurrentIdentifier() != pkg {
+				logger.Debug(msgFunctionNotReady, "currentIdentifier", fn.GetCurrentIdentifier())
+				continue
+			}
+			if fn.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue {
+				logger.Debug(msgFunctionReady)
+				return nil
+			}
+			reason = functionRevisionFailure(ctx, kube, fn)
+			logger.Debug(msgFunctionNotReady, "reason", reason)
+		}
+	}
+}
+
+// functionNotReady returns the error returned when a Function doesn't become
+// ready in time, including the supplied failure reason, if any.
+func functionNotReady(reason string) error {
+	if reason != "" {
+		return errors.Errorf(errFmtPkgNotReadyTimeoutReason, "Function", reason)
+	}
+	return errors.Errorf(errFmtPkgNotReadyTimeout, "Function")
+}
+
+// functionRevisionFailure returns the reason the supplied Function's current
+// revision is unhealthy, if it is.
+func functionRevisionFailure(ctx context.Context, kube fnclient.PkgV1alpha1Interface, fn *v1alpha1.Function) string {
+	if fn.GetCurrentRevision() == "" {
+		return ""
+	}
+	rev, err := kube.FunctionRevisions().Get(ctx, fn.GetCurrentRevision(), metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	c := rev.GetCondition(v1.TypeHealthy)
+	if c.Status != corev1.ConditionFalse {
+		return ""
+	}
+	if c.Message == "" {
+		return string(c.Reason)
+	}
+	return fmt.Sprintf("%s: %s", c.Reason, c.Message)
+}
+
 func warnIfNotFound(err error) error {
 	serr, ok := err.(*apierrors.StatusError) //nolint:errorlint // we need to be able to extract the underlying typed error
 	if !ok {
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/internal/client/clientset/versioned/fake"
+)
+
+func TestWaitForFunction(t *testing.T) {
+	unhealthy := xpv1.Condition{
+		Type:    v1.TypeHealthy,
+		Status:  corev1.ConditionFalse,
+		Reason:  v1.ReasonUnhealthy,
+		Message: "cannot pull image",
+	}
+
+	type args struct {
+		objs   []runtime.Object
+		events []runtime.Object
+		name   string
+		pkg    string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"Healthy": {
+			reason: "We should return successfully once the Function becomes healthy.",
+			args: args{
+				events: []runtime.Object{
+					&v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn"}},
+					func() runtime.Object {
+						fn := &v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn"}}
+						fn.SetCurrentIdentifier("xpkg.upbound.io/cool/fn:v0.2.0")
+						fn.SetConditions(v1.Healthy())
+						return fn
+					}(),
+				},
+				name: "cool-fn",
+				pkg:  "xpkg.upbound.io/cool/fn:v0.2.0",
+			},
+		},
+		"PreviousPackageHealthy": {
+			reason: "We should not consider the Function healthy until its current revision is of the supplied package.",
+			args: args{
+				events: []runtime.Object{
+					func() runtime.Object {
+						fn := &v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn"}}
+						fn.SetCurrentIdentifier("xpkg.upbound.io/cool/fn:v0.1.0")
+						fn.SetCurrentRevision("cool-fn-1234")
+						fn.SetConditions(v1.Healthy())
+						return fn
+					}(),
+					func() runtime.Object {
+						fn := &v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn"}}
+						fn.SetCurrentIdentifier("xpkg.upbound.io/cool/fn:v0.2.0")
+						fn.SetCurrentRevision("cool-fn-5678")
+						fn.SetConditions(v1.Unhealthy())
+						return fn
+					}(),
+				},
+				objs: []runtime.Object{
+					func() runtime.Object {
+						rev := &v1alpha1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn-5678"}}
+						rev.SetConditions(unhealthy)
+						return rev
+					}(),
+				},
+				name: "cool-fn",
+				pkg:  "xpkg.upbound.io/cool/fn:v0.2.0",
+			},
+			want: errors.Errorf(errFmtPkgNotReadyTimeoutReason, "Function", "UnhealthyPackageRevision: cannot pull image"),
+		},
+		"OtherFunctionHealthy": {
+			reason: "We should ignore other Functions becoming healthy.",
+			args: args{
+				events: []runtime.Object{
+					func() runtime.Object {
+						fn := &v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: "other-fn"}}
+						fn.SetCurrentIdentifier("xpkg.upbound.io/cool/fn:v0.2.0")
+						fn.SetConditions(v1.Healthy())
+						return fn
+					}(),
+				},
+				name: "cool-fn",
+				pkg:  "xpkg.upbound.io/cool/fn:v0.2.0",
+			},
+			want: errors.Errorf(errFmtPkgNotReadyTimeout, "Function"),
+		},
+		"TimeoutWithoutRevision": {
+			reason: "We should return a timeout error if the Function has no revision yet.",
+			args: args{
+				events: []runtime.Object{
+					&v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn"}},
+				},
+				name: "cool-fn",
+			},
+			want: errors.Errorf(errFmtPkgNotReadyTimeout, "Function"),
+		},
+		"TimeoutWithUnhealthyRevision": {
+			reason: "We should surface the failure reason of the Function's unhealthy current revision.",
+			args: args{
+				events: []runtime.Object{
+					func() runtime.Object {
+						fn := &v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn"}}
+						fn.SetConditions(v1.Unhealthy())
+						fn.SetCurrentIdentifier("xpkg.upbound.io/cool/fn:v0.2.0")
+						fn.SetCurrentRevision("cool-fn-1234")
+						return fn
+					}(),
+				},
+				objs: []runtime.Object{
+					func() runtime.Object {
+						rev := &v1alpha1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn-1234"}}
+						rev.SetConditions(unhealthy)
+						return rev
+					}(),
+				},
+				name: "cool-fn",
+				pkg:  "xpkg.upbound.io/cool/fn:v0.2.0",
+			},
+			want: errors.Errorf(errFmtPkgNotReadyTimeoutReason, "Function", "UnhealthyPackageRevision: cannot pull image"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := fake.NewSimpleClientset(tc.args.objs...).PkgV1alpha1()
+			w := &cache.ListWatch{WatchFunc: func(_ metav1.ListOptions) (watch.Interface, error) {
+				fw := watch.NewFakeWithChanSize(len(tc.args.events), false)
+				for _, o := range tc.args.events {
+					fw.Modify(o)
+				}
+				return fw, nil
+			}}
+			err := waitForFunction(context.Background(), kube, w, tc.args.name, tc.args.pkg, 50*time.Millisecond, logging.NewNopLogger())
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nwaitForFunction(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
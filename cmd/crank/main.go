@@ -62,6 +62,8 @@ var cli struct {
 	Install installCmd `cmd:"" help:"Install Crossplane packages."`
 	Update  updateCmd  `cmd:"" help:"Update Crossplane packages."`
 	Push    pushCmd    `cmd:"" help:"Push Crossplane packages."`
+
+	Beta betaCmd `cmd:"" help:"Beta commands."`
 }
 
 func main() {
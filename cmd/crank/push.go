@@ -21,8 +21,12 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/spf13/afero"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -34,14 +38,20 @@ import (
 const (
 	errGetwd           = "failed to get working directory while searching for package"
 	errFindPackageinWd = "failed to find a package in current working directory"
+	errBuildIndex      = "failed to build an index of packages"
+
+	errFmtLoadPackage       = "failed to load package %q"
+	errFmtNoPlatform        = "package %q does not specify a platform - was it built with an embedded runtime image?"
+	errFmtDuplicatePlatform = "packages %q and %q were both built for platform %s"
 )
 
 // pushCmd pushes a package.
 type pushCmd struct {
 	Configuration pushConfigCmd   `cmd:"" help:"Push a Configuration package."`
 	Provider      pushProviderCmd `cmd:"" help:"Push a Provider package."`
+	Function      pushFunctionCmd `cmd:"" help:"Push a Function package."`
 
-	Package string `short:"f" help:"Path to package. If not specified and only one package exists in current directory it will be used."`
+	Package []string `short:"f" help:"Path to package. Specify more than once to push an index of packages built for different platforms. If not specified and only one package exists in current directory it will be used."`
 }
 
 // Run runs the push cmd.
@@ -55,7 +65,7 @@ func (c *pushCmd) Run(child *pushChild, logger logging.Logger) error {
 
 	// If package is not defined, attempt to find single package in current
 	// directory.
-	if c.Package == "" {
+	if len(c.Package) == 0 {
 		logger.Debug("Trying to find package in current directory")
 		wd, err := os.Getwd()
 		if err != nil {
@@ -67,21 +77,62 @@ func (c *pushCmd) Run(child *pushChild, logger logging.Logger) error {
 			logger.Debug("Failed to find package in directory", "error", errors.Wrap(err, errFindPackageinWd))
 			return errors.Wrap(err, errFindPackageinWd)
 		}
-		c.Package = path
+		c.Package = []string{path}
 		logger.Debug("Found package in directory", "path", path)
 	}
-	img, err := tarball.ImageFromPath(c.Package, nil)
+
+	if len(c.Package) == 1 {
+		img, err := tarball.ImageFromPath(c.Package[0], nil)
+		if err != nil {
+			logger.Debug("Failed to create image from package tarball", "error", err)
+			return err
+		}
+		if err := remote.Write(tag, img, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+			logger.Debug("Failed to push created image to remote location", "error", err)
+			return err
+		}
+		return nil
+	}
+
+	idx, err := packageIndex(c.Package...)
 	if err != nil {
-		logger.Debug("Failed to create image from package tarball", "error", err)
-		return err
+		logger.Debug(errBuildIndex, "error", err)
+		return errors.Wrap(err, errBuildIndex)
 	}
-	if err := remote.Write(tag, img, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
-		logger.Debug("Failed to push created image to remote location", "error", err)
+	if err := remote.WriteIndex(tag, idx, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+		logger.Debug("Failed to push created image index to remote location", "error", err)
 		return err
 	}
 	return nil
 }
 
+// packageIndex builds an image index from the supplied package tarballs. Each
+// package must be built for a different platform.
+func packageIndex(paths ...string) (v1.ImageIndex, error) {
+	adds := make([]mutate.IndexAddendum, 0, len(paths))
+	seen := make(map[string]string, len(paths))
+	for _, path := range paths {
+		img, err := tarball.ImageFromPath(path, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtLoadPackage, path)
+		}
+		cfg, err := img.ConfigFile()
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtLoadPackage, path)
+		}
+		p := cfg.Platform()
+		if p == nil || p.OS == "" || p.Architecture == "" {
+			return nil, errors.Errorf(errFmtNoPlatform, path)
+		}
+		if other, ok := seen[p.String()]; ok {
+			return nil, errors.Errorf(errFmtDuplicatePlatform, other, path, p.String())
+		}
+		seen[p.String()] = path
+		adds = append(adds, mutate.IndexAddendum{Add: img, Descriptor: v1.Descriptor{Platform: p}})
+	}
+	return mutate.AppendManifests(mutate.IndexMediaType(empty.Index, types.OCIImageIndex), adds...), nil
+}
+
 type pushChild struct {
 	tag string
 	fs  afero.Fs
@@ -108,3 +159,14 @@ func (c pushProviderCmd) AfterApply(p *pushChild) error { //nolint:unparam // Af
 	p.tag = c.Tag
 	return nil
 }
+
+// pushFunctionCmd pushes a Function.
+type pushFunctionCmd struct {
+	Tag string `arg:"" help:"Tag of the package to be pushed. Must be a valid OCI image tag."`
+}
+
+// AfterApply sets the tag for the parent push command.
+func (c pushFunctionCmd) AfterApply(p *pushChild) error { //nolint:unparam // AfterApply requires this signature.
+	p.tag = c.Tag
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestPackageIndex(t *testing.T) {
+	dir := t.TempDir()
+
+	// writePackage writes a package built for the supplied platform.
+	writePackage := func(name string, p *v1.Platform) string {
+		img, err := random.Image(1024, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := img.ConfigFile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != nil {
+			cfg.OS, cfg.Architecture, cfg.Variant = p.OS, p.Architecture, p.Variant
+		}
+		img, err = mutate.ConfigFile(img, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := tarball.WriteToFile(path, nil, img); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	amd64 := writePackage("amd64.xpkg", &v1.Platform{OS: "linux", Architecture: "amd64"})
+	arm64 := writePackage("arm64.xpkg", &v1.Platform{OS: "linux", Architecture: "arm64"})
+	amd64Again := writePackage("amd64-again.xpkg", &v1.Platform{OS: "linux", Architecture: "amd64"})
+	noPlatform := writePackage("none.xpkg", nil)
+
+	type want struct {
+		platforms []string
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		paths  []string
+		want   want
+	}{
+		"Success": {
+			reason: "We should build an index containing each package, keyed by platform.",
+			paths:  []string{amd64, arm64},
+			want:   want{platforms: []string{"linux/amd64", "linux/arm64"}},
+		},
+		"DuplicatePlatform": {
+			reason: "We should return an error if two packages were built for the same platform.",
+			paths:  []string{amd64, amd64Again},
+			want:   want{err: errors.Errorf(errFmtDuplicatePlatform, amd64, amd64Again, "linux/amd64")},
+		},
+		"NoPlatform": {
+			reason: "We should return an error if a package doesn't specify a platform.",
+			paths:  []string{amd64, noPlatform},
+			want:   want{err: errors.Errorf(errFmtNoPlatform, noPlatform)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			idx, err := packageIndex(tc.paths...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\npackageIndex(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			m, err := idx.IndexManifest()
+			if err != nil {
+				t.Fatal(err)
+			}
+			platforms := make([]string, 0, len(m.Manifests))
+			for _, d := range m.Manifests {
+				platforms = append(platforms, d.Platform.String())
+			}
+			if diff := cmp.Diff(tc.want.platforms, platforms); diff != "" {
+				t.Errorf("\n%s\npackageIndex(...): -want platforms, +got platforms:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composition"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+const (
+	errReadFile             = "cannot read file"
+	errSplitYAML            = "cannot split YAML stream"
+	errLoadXR               = "cannot load composite resource"
+	errLoadComposition      = "cannot load Composition"
+	errLoadObserved         = "cannot load observed composed resources"
+	errLoadEnvironment      = "cannot load EnvironmentConfigs"
+	errBuildEnvironment     = "cannot build environment"
+	errBuildScheme          = "cannot build scheme"
+	errSelectEnvironment    = "cannot select environment"
+	errFetchEnvironment     = "cannot fetch environment"
+	errBuildFunctionState   = "cannot build observed state for the Composition Function pipeline"
+	errBuildPipelineSteps   = "cannot build Composition Function pipeline steps"
+	errRunPipeline          = "cannot run Composition Function pipeline"
+	errPatchAndTransform    = "cannot run Patch & Transform Composition"
+	errFunctionsUnsupported = "cannot render Compositions that use spec.functions - use a Pipeline mode Composition instead"
+	errMarshalResource      = "cannot marshal resource to YAML"
+	errWriteOutput          = "cannot write output"
+
+	errFmtUnmarshalDocument    = "cannot unmarshal YAML document %d"
+	errFmtNotComposition       = "expected a %s, got %s"
+	errFmtNoResourceName       = "observed resource %q of kind %s must have the %s annotation"
+	errFmtNotEnvironmentConfig = "expected EnvironmentConfig, got %s"
+)
+
+// renderCmd renders a composite resource (XR) using a Composition, without
+// talking to a Crossplane control plane.
+type renderCmd struct {
+	CompositeResource string `arg:"" type:"path" help:"A YAML file specifying the composite resource (XR) to render."`
+	Composition       string `arg:"" type:"path" help:"A YAML file specifying the Composition to use to render the XR."`
+
+	ObservedResources  string            `short:"o" type:"path" placeholder:"PATH" help:"A YAML stream of composed resources that already exist. Each must be annotated with the name of the Composition resource it corresponds to."`
+	EnvironmentConfigs string            `short:"e" type:"path" placeholder:"PATH" help:"A YAML stream of EnvironmentConfigs. They're selected and merged according to the Composition's spec.environment, as they would be by Crossplane."`
+	FunctionEndpoints  map[string]string `short:"f" placeholder:"NAME=ADDRESS" help:"The gRPC endpoint at which each Function used by a Pipeline mode Composition is served, e.g. function-example=localhost:9443."`
+	Timeout            time.Duration     `default:"1m" help:"How long to run before timing out."`
+}
+
+// Help prints out the help for the render command.
+func (c *renderCmd) Help() string {
+	return `
+Render a composite resource (XR) using a Composition, and print the desired
+state that would be produced as a YAML stream. The XR is printed first,
+followed by any desired composed resources.
+
+Patch & Transform Compositions are rendered entirely locally. Pipeline mode
+Compositions send requests to each of their Functions, which must already be
+running (for example using 'go run . --insecure') at the endpoints specified
+using --function-endpoints.
+
+Composed resources that don't exist yet won't be named; they'll only have a
+generate name.
+
+The environment is selected and merged from the EnvironmentConfigs specified
+using --environment-configs, as Crossplane would select it from those in the
+API server. Environment sources that read from other kinds of object, such as
+ConfigMaps, are treated as if the object doesn't exist. The API server's
+defaults aren't applied to the Composition, so fields such as an environment
+selector's mode and sortByFieldPath must be specified explicitly.
+`
+}
+
+// Run runs the render command.
+func (c *renderCmd) Run(k *kong.Context, logger logging.Logger) error {
+	fs := afero.NewOsFs()
+
+	xr, err := LoadCompositeResource(fs, c.CompositeResource)
+	if err != nil {
+		return errors.Wrap(err, errLoadXR)
+	}
+
+	comp, err := LoadComposition(fs, c.Composition)
+	if err != nil {
+		return errors.Wrap(err, errLoadComposition)
+	}
+
+	in := RenderInputs{
+		CompositeResource: xr,
+		Composition:       comp,
+		FunctionRunner:    composite.NewStaticFunctionRunner(c.FunctionEndpoints),
+	}
+
+	if c.ObservedResources != "" {
+		in.ObservedResources, err = LoadObservedResources(fs, c.ObservedResources)
+		if err != nil {
+			return errors.Wrap(err, errLoadObserved)
+		}
+	}
+
+	if c.EnvironmentConfigs != "" {
+		in.EnvironmentConfigs, err = LoadEnvironmentConfigs(fs, c.EnvironmentConfigs)
+		if err != nil {
+			return errors.Wrap(err, errLoadEnvironment)
+		}
+	}
+
+	logger.Debug("Rendering composite resource", "xr", xr.GetName(), "composition", comp.GetName(), "mode", comp.Spec.GetMode())
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	out, err := Render(ctx, in)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range out.Events {
+		fmt.Fprintf(k.Stderr, "%s %s: %s\n", e.Type, e.Reason, e.Message)
+	}
+
+	return errors.Wrap(WriteYAMLStream(k.Stdout, out.Resources()...), errWriteOutput)
+}
+
+// RenderInputs contains all inputs to the render process.
+type RenderInputs struct {
+	CompositeResource  *ucomposite.Unstructured
+	Composition        *v1.Composition
+	ObservedResources  []composed.Unstructured
+	EnvironmentConfigs []v1alpha1.EnvironmentConfig
+	FunctionRunner     composite.FunctionRunner
+}
+
+// RenderOutputs contains all outputs from the render process.
+type RenderOutputs struct {
+	CompositeResource resource.Composite
+	ComposedResources []resource.Composed
+	Events            []event.Event
+}
+
+// Resources returns the rendered XR followed by the rendered composed
+// resources.
+func (o RenderOutputs) Resources() []resource.Object {
+	out := make([]resource.Object, 0, len(o.ComposedResources)+1)
+	out = append(out, o.CompositeResource)
+	for _, cd := range o.ComposedResources {
+		out = append(out, cd)
+	}
+	return out
+}
+
+// Render the desired state of the supplied XR using the supplied Composition.
+// Render is roughly equivalent to a single reconcile of the XR, except that
+// nothing is read from or written to an API server.
+func Render(ctx context.Context, in RenderInputs) (RenderOutputs, error) { //nolint:gocyclo // Just a linear sequence of steps.
+	xr := in.CompositeResource
+
+	// The XR controller would name the XR's composed resources after the XR.
+	if xr.GetLabels()[xcrd.LabelKeyNamePrefixForComposed] == "" {
+		meta.AddLabels(xr, map[string]string{xcrd.LabelKeyNamePrefixForComposed: xr.GetName()})
+	}
+
+	rev := composition.NewCompositionRevision(in.Composition, 1)
+
+	env, err := buildEnvironment(ctx, xr, rev, in.EnvironmentConfigs)
+	if err != nil {
+		return RenderOutputs{}, errors.Wrap(err, errBuildEnvironment)
+	}
+
+	cds := composite.ComposedResourceStates{}
+	for i := range in.ObservedResources {
+		cd := &in.ObservedResources[i]
+		name := composite.GetCompositionResourceName(cd)
+		if name == "" {
+			return RenderOutputs{}, errors.Errorf(errFmtNoResourceName, cd.GetName(), cd.GetKind(), composite.AnnotationKeyCompositionResourceName)
+		}
+		cds[name] = composite.ComposedResourceState{
+			ComposedResource: composite.ComposedResource{ResourceName: name},
+			Resource:         cd,
+		}
+	}
+
+	s := &composite.PTFCompositionState{
+		Composite:         xr,
+		ConnectionDetails: managed.ConnectionDetails{},
+		ComposedResources: cds,
+		Events:            make([]event.Event, 0),
+	}
+
+	switch rev.Spec.GetMode() {
+	case v1.CompositionModePipeline:
+		o, err := composite.FunctionStateObserved(s)
+		if err != nil {
+			return RenderOutputs{}, errors.Wrap(err, errBuildFunctionState)
+		}
+
+		steps, err := composite.FunctionSteps(rev.Spec.Pipeline)
+		if err != nil {
+			return RenderOutputs{}, errors.Wrap(err, errBuildPipelineSteps)
+		}
+
+		if err := composite.NewPackagedFunctionPipeline(in.FunctionRunner).RunFunctionSteps(ctx, s, o, steps...); err != nil {
+			return RenderOutputs{}, errors.Wrap(err, errRunPipeline)
+		}
+	default:
+		// We can't run spec.functions, which are containers that would need
+		// to be pulled and run.
+		if len(rev.Spec.Functions) > 0 {
+			return RenderOutputs{}, errors.New(errFunctionsUnsupported)
+		}
+
+		pt := composite.NewXRCDPatchAndTransformer(composite.RendererFn(composite.RenderComposite), composite.RendererFn(composite.RenderComposed))
+		if err := pt.PatchAndTransform(ctx, composite.CompositionRequest{Revision: rev, Environment: env}, s); err != nil {
+			return RenderOutputs{}, errors.Wrap(err, errPatchAndTransform)
+		}
+	}
+
+	// Sort composed resources by name so our output is stable.
+	names := make([]string, 0, len(s.ComposedResources))
+	for name := range s.ComposedResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := RenderOutputs{CompositeResource: s.Composite, ComposedResources: make([]resource.Composed, 0, len(names)), Events: s.Events}
+	for _, name := range names {
+		cd := s.ComposedResources[name]
+
+		// Only include resources that are part of our desired state, and that
+		// rendered successfully. Failures to render emit a warning event.
+		desired := cd.Desired != nil || cd.Template != nil
+		if !desired || cd.TemplateRenderErr != nil {
+			continue
+		}
+		out.ComposedResources = append(out.ComposedResources, cd.Resource)
+	}
+
+	return out, nil
+}
+
+// buildEnvironment selects and fetches the XR's environment as the XR
+// controller would, using the supplied EnvironmentConfigs in place of those in
+// the API server. Selecting the environment updates the XR's references to it.
+func buildEnvironment(ctx context.Context, xr resource.Composite, rev *v1.CompositionRevision, ecs []v1alpha1.EnvironmentConfig) (*environment.Environment, error) {
+	s := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, errBuildScheme)
+	}
+	if err := corev1.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, errBuildScheme)
+	}
+
+	objs := make([]client.Object, len(ecs))
+	for i := range ecs {
+		objs[i] = &ecs[i]
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+
+	if err := environment.NewAPIEnvironmentSelector(kube).SelectEnvironment(ctx, xr, rev); err != nil {
+		return nil, errors.Wrap(err, errSelectEnvironment)
+	}
+	e, err := environment.NewAPIEnvironmentFetcher(kube).Fetch(ctx, xr, rev)
+	return e, errors.Wrap(err, errFetchEnvironment)
+}
+
+// LoadCompositeResource loads a composite resource from a YAML file.
+func LoadCompositeResource(fs afero.Fs, file string) (*ucomposite.Unstructured, error) {
+	y, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadFile)
+	}
+	xr := ucomposite.New()
+	return xr, errors.Wrapf(yaml.Unmarshal(y, xr), errFmtUnmarshalDocument, 0)
+}
+
+// LoadComposition loads a Composition from a YAML file.
+func LoadComposition(fs afero.Fs, file string) (*v1.Composition, error) {
+	y, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadFile)
+	}
+	comp := &v1.Composition{}
+	if err := yaml.Unmarshal(y, comp); err != nil {
+		return nil, errors.Wrapf(err, errFmtUnmarshalDocument, 0)
+	}
+	if gvk := comp.GroupVersionKind(); gvk != v1.CompositionGroupVersionKind {
+		return nil, errors.Errorf(errFmtNotComposition, v1.CompositionGroupVersionKind, gvk)
+	}
+	return comp, nil
+}
+
+// LoadObservedResources loads composed resources from a YAML stream.
+func LoadObservedResources(fs afero.Fs, file string) ([]composed.Unstructured, error) {
+	docs, err := LoadYAMLStream(fs, file)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]composed.Unstructured, 0, len(docs))
+	for i, y := range docs {
+		cd := composed.New()
+		if err := yaml.Unmarshal(y, cd); err != nil {
+			return nil, errors.Wrapf(err, errFmtUnmarshalDocument, i)
+		}
+		out = append(out, *cd)
+	}
+	return out, nil
+}
+
+// LoadEnvironmentConfigs loads EnvironmentConfigs from a YAML stream.
+func LoadEnvironmentConfigs(fs afero.Fs, file string) ([]v1alpha1.EnvironmentConfig, error) {
+	docs, err := LoadYAMLStream(fs, file)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]v1alpha1.EnvironmentConfig, 0, len(docs))
+	for i, y := range docs {
+		ec := v1alpha1.EnvironmentConfig{}
+		if err := yaml.Unmarshal(y, &ec); err != nil {
+			return nil, errors.Wrapf(err, errFmtUnmarshalDocument, i)
+		}
+		if gvk := ec.GroupVersionKind(); gvk != v1alpha1.EnvironmentConfigGroupVersionKind {
+			return nil, errors.Errorf(errFmtNotEnvironmentConfig, gvk)
+		}
+		out = append(out, ec)
+	}
+	return out, nil
+}
+
+// LoadYAMLStream loads a stream of YAML documents from a file. Empty documents
+// are omitted.
+func LoadYAMLStream(fs afero.Fs, file string) ([][]byte, error) {
+	f, err := fs.Open(file)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadFile)
+	}
+	defer f.Close() //nolint:errcheck // Only open for reading.
+
+	out := make([][]byte, 0)
+	yr := kyaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		y, err := yr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errSplitYAML)
+		}
+		if len(bytes.TrimSpace(y)) == 0 {
+			continue
+		}
+		out = append(out, y)
+	}
+	return out, nil
+}
+
+// WriteYAMLStream writes the supplied resources to the supplied writer as a
+// stream of YAML documents.
+func WriteYAMLStream(w io.Writer, objs ...resource.Object) error {
+	for _, o := range objs {
+		y, err := yaml.Marshal(o)
+		if err != nil {
+			return errors.Wrap(err, errMarshalResource)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,583 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/afero"
+	"google.golang.org/protobuf/types/known/structpb"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composition"
+)
+
+const (
+	xrYAML = `
+apiVersion: example.org/v1
+kind: XCoolResource
+metadata:
+  name: cool-xr
+spec:
+  coolField: cool-value
+`
+
+	xrWithEnvironmentRefsYAML = `
+apiVersion: example.org/v1
+kind: XCoolResource
+metadata:
+  name: cool-xr
+spec:
+  coolField: cool-value
+  environmentConfigRefs:
+  - name: cool-env
+`
+
+	compositionPTYAML = `
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: cool-composition
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XCoolResource
+  environment:
+    patches:
+    - type: ToCompositeFieldPath
+      fromFieldPath: region
+      toFieldPath: status.region
+  resources:
+  - name: cool-resource
+    base:
+      apiVersion: example.org/v1
+      kind: CoolComposed
+    patches:
+    - fromFieldPath: spec.coolField
+      toFieldPath: spec.forProvider.coolField
+    - type: FromEnvironmentFieldPath
+      fromFieldPath: region
+      toFieldPath: spec.forProvider.region
+    - type: ToCompositeFieldPath
+      fromFieldPath: status.atProvider.id
+      toFieldPath: status.id
+  - name: broken-resource
+    base:
+      apiVersion: example.org/v1
+      kind: CoolComposed
+    patches:
+    - type: FromCompositeFieldPath
+      fromFieldPath: spec.nonexistent
+      toFieldPath: spec.forProvider.nonexistent
+      policy:
+        fromFieldPath: Required
+`
+
+	compositionFunctionsYAML = `
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: cool-composition
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XCoolResource
+  functions:
+  - name: cool-fn
+    type: Container
+    container:
+      image: xpkg.upbound.io/example/cool-fn:v1
+`
+
+	compositionPipelineYAML = `
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: cool-composition
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XCoolResource
+  mode: Pipeline
+  pipeline:
+  - step: cool-step
+    functionRef:
+      name: cool-fn
+`
+
+	observedYAML = `
+---
+apiVersion: example.org/v1
+kind: CoolComposed
+metadata:
+  name: cool-xr-abcde
+  annotations:
+    crossplane.io/composition-resource-name: cool-resource
+status:
+  atProvider:
+    id: cool-id
+`
+
+	observedNoNameYAML = `
+apiVersion: example.org/v1
+kind: CoolComposed
+metadata:
+  name: cool-xr-abcde
+`
+
+	environmentConfigsYAML = `
+---
+apiVersion: apiextensions.crossplane.io/v1alpha1
+kind: EnvironmentConfig
+metadata:
+  name: cool-env
+data:
+  region: us-cool-1
+---
+apiVersion: apiextensions.crossplane.io/v1alpha1
+kind: EnvironmentConfig
+metadata:
+  name: other-env
+data:
+  region: us-other-1
+`
+)
+
+func TestRender(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type files struct {
+		xr           string
+		composition  string
+		observed     string
+		environment  string
+		functionsRun composite.FunctionRunner
+	}
+
+	type want struct {
+		out    string
+		events []event.Event
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		files  files
+		want   want
+	}{
+		"ObservedResourceMissingName": {
+			reason: "We should return an error if an observed resource isn't annotated with its Composition resource name.",
+			files: files{
+				xr:          xrYAML,
+				composition: compositionPTYAML,
+				observed:    observedNoNameYAML,
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoResourceName, "cool-xr-abcde", "CoolComposed", composite.AnnotationKeyCompositionResourceName),
+			},
+		},
+		"MissingEnvironmentConfig": {
+			reason: "We should return an error if the XR references an EnvironmentConfig that wasn't supplied.",
+			files: files{
+				xr:          xrWithEnvironmentRefsYAML,
+				composition: compositionPTYAML,
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errors.New(`failed to get config set from reference: environmentconfigs.apiextensions.crossplane.io "cool-env" not found`), errFetchEnvironment), errBuildEnvironment),
+			},
+		},
+		"FunctionsUnsupported": {
+			reason: "We should return an error if the Composition uses spec.functions.",
+			files: files{
+				xr:          xrYAML,
+				composition: compositionFunctionsYAML,
+			},
+			want: want{
+				err: errors.New(errFunctionsUnsupported),
+			},
+		},
+		"PatchAndTransform": {
+			reason: "We should render a Patch & Transform Composition, patching to and from the XR and environment.",
+			files: files{
+				xr:          xrWithEnvironmentRefsYAML,
+				composition: compositionPTYAML,
+				observed:    observedYAML,
+				environment: environmentConfigsYAML,
+			},
+			want: want{
+				out: `---
+apiVersion: example.org/v1
+kind: XCoolResource
+metadata:
+  labels:
+    crossplane.io/composite: cool-xr
+  name: cool-xr
+spec:
+  coolField: cool-value
+  environmentConfigRefs:
+  - name: cool-env
+status:
+  id: cool-id
+  region: us-cool-1
+---
+apiVersion: example.org/v1
+kind: CoolComposed
+metadata:
+  annotations:
+    crossplane.io/composition-resource-name: cool-resource
+  generateName: cool-xr-
+  labels:
+    crossplane.io/claim-name: ""
+    crossplane.io/claim-namespace: ""
+    crossplane.io/composite: cool-xr
+  name: cool-xr-abcde
+  ownerReferences:
+  - apiVersion: example.org/v1
+    blockOwnerDeletion: true
+    controller: true
+    kind: XCoolResource
+    name: cool-xr
+    uid: ""
+spec:
+  forProvider:
+    coolField: cool-value
+    region: us-cool-1
+`,
+				events: []event.Event{{Type: event.TypeWarning, Reason: "ComposeResources"}},
+			},
+		},
+		"PipelineError": {
+			reason: "We should return an error if the Composition Function pipeline fails.",
+			files: files{
+				xr:          xrYAML,
+				composition: compositionPipelineYAML,
+				functionsRun: composite.FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+					return nil, errBoom
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrapf(errBoom, "cannot run pipeline step %q", "cool-step"), errRunPipeline),
+			},
+		},
+		"Pipeline": {
+			reason: "We should render a Pipeline mode Composition by running its Functions.",
+			files: files{
+				xr:          xrYAML,
+				composition: compositionPipelineYAML,
+				functionsRun: composite.FunctionRunnerFn(func(_ context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+					if name != "cool-fn" {
+						return nil, errBoom
+					}
+					d := req.GetDesired()
+					d.Resources = map[string]*fnv1beta1.Resource{
+						"cool-resource": {
+							Resource: MustStruct(map[string]any{
+								"apiVersion": "example.org/v1",
+								"kind":       "CoolComposed",
+								"spec": map[string]any{
+									"forProvider": map[string]any{
+										"coolField": d.GetComposite().GetResource().AsMap()["spec"].(map[string]any)["coolField"],
+									},
+								},
+							}),
+						},
+					}
+					return &fnv1beta1.RunFunctionResponse{Desired: d}, nil
+				}),
+			},
+			want: want{
+				out: `---
+apiVersion: example.org/v1
+kind: XCoolResource
+metadata:
+  labels:
+    crossplane.io/composite: cool-xr
+  name: cool-xr
+spec:
+  coolField: cool-value
+---
+apiVersion: example.org/v1
+kind: CoolComposed
+metadata:
+  annotations:
+    crossplane.io/composition-resource-name: cool-resource
+  labels:
+    crossplane.io/claim-name: ""
+    crossplane.io/claim-namespace: ""
+    crossplane.io/composite: cool-xr
+  ownerReferences:
+  - apiVersion: example.org/v1
+    blockOwnerDeletion: true
+    controller: true
+    kind: XCoolResource
+    name: cool-xr
+    uid: ""
+spec:
+  forProvider:
+    coolField: cool-value
+`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			_ = afero.WriteFile(fs, "xr.yaml", []byte(tc.files.xr), 0o600)
+			_ = afero.WriteFile(fs, "composition.yaml", []byte(tc.files.composition), 0o600)
+			_ = afero.WriteFile(fs, "observed.yaml", []byte(tc.files.observed), 0o600)
+			_ = afero.WriteFile(fs, "environment.yaml", []byte(tc.files.environment), 0o600)
+
+			xr, err := LoadCompositeResource(fs, "xr.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			comp, err := LoadComposition(fs, "composition.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			observed, err := LoadObservedResources(fs, "observed.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ecs, err := LoadEnvironmentConfigs(fs, "environment.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			in := RenderInputs{
+				CompositeResource:  xr,
+				Composition:        comp,
+				ObservedResources:  observed,
+				EnvironmentConfigs: ecs,
+				FunctionRunner:     tc.files.functionsRun,
+			}
+
+			out, err := Render(context.Background(), in)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRender(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			b := &bytes.Buffer{}
+			if err := WriteYAMLStream(b, out.Resources()...); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.out, b.String()); diff != "" {
+				t.Errorf("\n%s\nRender(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			// We only compare event types and reasons; messages are verbose.
+			if diff := cmp.Diff(tc.want.events, out.Events, cmpopts.EquateEmpty(), cmpopts.IgnoreFields(event.Event{}, "Message", "Annotations")); diff != "" {
+				t.Errorf("\n%s\nRender(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildEnvironment(t *testing.T) {
+	compositionWithoutEnvironmentYAML := `
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: cool-composition
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XCoolResource
+`
+	compositionWithSelectorYAML := `
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: cool-composition
+spec:
+  compositeTypeRef:
+    apiVersion: example.org/v1
+    kind: XCoolResource
+  environment:
+    environmentConfigs:
+    - type: Selector
+      selector:
+        mode: Multiple
+        sortByFieldPath: metadata.name
+        matchLabels:
+        - key: cool
+          type: Value
+          value: "true"
+`
+	labelledEnvironmentConfigsYAML := `
+---
+apiVersion: apiextensions.crossplane.io/v1alpha1
+kind: EnvironmentConfig
+metadata:
+  name: cool-env
+  labels:
+    cool: "true"
+data:
+  region: us-cool-1
+---
+apiVersion: apiextensions.crossplane.io/v1alpha1
+kind: EnvironmentConfig
+metadata:
+  name: other-env
+data:
+  region: us-other-1
+`
+
+	type want struct {
+		refs   []corev1.ObjectReference
+		region any
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason      string
+		xr          string
+		composition string
+		environment string
+		want        want
+	}{
+		"NoSources": {
+			reason:      "We should not read any EnvironmentConfigs if neither the XR nor the Composition reference any.",
+			xr:          xrYAML,
+			composition: compositionWithoutEnvironmentYAML,
+			environment: labelledEnvironmentConfigsYAML,
+			want:        want{},
+		},
+		"Selector": {
+			reason:      "We should select EnvironmentConfigs using the Composition's selectors, and reference them from the XR.",
+			xr:          xrYAML,
+			composition: compositionWithSelectorYAML,
+			environment: labelledEnvironmentConfigsYAML,
+			want: want{
+				refs: []corev1.ObjectReference{{
+					APIVersion: v1alpha1.SchemeGroupVersion.String(),
+					Kind:       v1alpha1.EnvironmentConfigKind,
+					Name:       "cool-env",
+				}},
+				region: "us-cool-1",
+			},
+		},
+		"ExistingReferences": {
+			reason:      "We should read EnvironmentConfigs the XR already references.",
+			xr:          xrWithEnvironmentRefsYAML,
+			composition: compositionWithoutEnvironmentYAML,
+			environment: labelledEnvironmentConfigsYAML,
+			want: want{
+				refs:   []corev1.ObjectReference{{Name: "cool-env"}},
+				region: "us-cool-1",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			_ = afero.WriteFile(fs, "xr.yaml", []byte(tc.xr), 0o600)
+			_ = afero.WriteFile(fs, "composition.yaml", []byte(tc.composition), 0o600)
+			_ = afero.WriteFile(fs, "environment.yaml", []byte(tc.environment), 0o600)
+
+			xr, err := LoadCompositeResource(fs, "xr.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			comp, err := LoadComposition(fs, "composition.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ecs, err := LoadEnvironmentConfigs(fs, "environment.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			e, err := buildEnvironment(context.Background(), xr, composition.NewCompositionRevision(comp, 1), ecs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nbuildEnvironment(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.refs, xr.GetEnvironmentConfigReferences(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nbuildEnvironment(...): -want XR references, +got XR references:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.region, e.Object["region"]); diff != "" {
+				t.Errorf("\n%s\nbuildEnvironment(...): -want region, +got region:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLoadYAMLStream(t *testing.T) {
+	type want struct {
+		docs int
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		file   string
+		want   want
+	}{
+		"Empty": {
+			reason: "An empty file should produce no documents.",
+			file:   "",
+			want:   want{docs: 0},
+		},
+		"OmitEmptyDocuments": {
+			reason: "We should omit documents that are empty.",
+			file:   environmentConfigsYAML + "\n---\n",
+			want:   want{docs: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			_ = afero.WriteFile(fs, "stream.yaml", []byte(tc.file), 0o600)
+
+			docs, err := LoadYAMLStream(fs, "stream.yaml")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nLoadYAMLStream(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.docs, len(docs)); diff != "" {
+				t.Errorf("\n%s\nLoadYAMLStream(...): -want docs, +got docs:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func MustStruct(v map[string]any) *structpb.Struct {
+	s, err := structpb.NewStruct(v)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
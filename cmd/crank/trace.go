@@ -0,0 +1,356 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alecthomas/kong"
+	corev1 "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+const (
+	errGetMapping     = "cannot get REST mapping for resource"
+	errGetResource    = "cannot get requested resource"
+	errGetTree        = "cannot get resource tree"
+	errPrintTree      = "cannot print resource tree"
+	errMarshalTree    = "cannot marshal resource tree"
+	errMissingName    = "resource name is required"
+	errFmtResourceArg = "cannot parse %q - expected TYPE[.GROUP]/NAME or TYPE[.GROUP] NAME"
+	errFmtOutput      = "unsupported output format %q"
+)
+
+// Output formats supported by the trace command.
+const (
+	outputTree = "tree"
+	outputJSON = "json"
+	outputYAML = "yaml"
+	outputDot  = "dot"
+)
+
+// traceCmd traces a claim or composite resource (XR) through to the composed
+// resources it ultimately produces.
+type traceCmd struct {
+	Resource string `arg:"" help:"Kind of the claim or composite resource to trace, optionally with its name, e.g. 'postgresqlinstances.example.org/my-db'."`
+	Name     string `arg:"" optional:"" help:"Name of the claim or composite resource to trace."`
+
+	Namespace string `short:"n" default:"default" help:"Namespace of the claim to trace. Ignored for cluster scoped resources."`
+	Output    string `short:"o" default:"tree" enum:"tree,json,yaml,dot" help:"Output format. One of tree, json, yaml, or dot."`
+}
+
+// Help prints out the help for the trace command.
+func (c *traceCmd) Help() string {
+	return `
+Trace a claim or composite resource (XR) through to the composed resources it
+ultimately produces, including the composed resources of any nested XRs. The
+Synced and Ready conditions, status, and age of each resource are printed as a
+tree.
+
+Examples:
+  # Trace a claim in the default namespace.
+  kubectl crossplane beta trace postgresqlinstance my-db
+
+  # Trace an XR, printing the full resource tree as YAML.
+  kubectl crossplane beta trace xpostgresqlinstances.example.org/my-db-8x7bd -o yaml
+
+  # Render the resource tree as an image using Graphviz.
+  kubectl crossplane beta trace postgresqlinstance my-db -n prod -o dot | dot -Tpng > my-db.png
+`
+}
+
+// Run runs the trace command.
+func (c *traceCmd) Run(k *kong.Context, logger logging.Logger) error {
+	kind, name, err := parseResourceArg(c.Resource, c.Name)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+	logger.Debug("Found kubeconfig")
+
+	kube, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return errors.Wrap(err, errKubeClient)
+	}
+	logger.Debug("Created Kubernetes client")
+
+	ref, err := resolveReference(kube.RESTMapper(), kind, name, c.Namespace)
+	if err != nil {
+		return errors.Wrap(err, errGetMapping)
+	}
+	logger = logger.WithValues("apiVersion", ref.APIVersion, "kind", ref.Kind, "name", ref.Name, "namespace", ref.Namespace)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	root, err := NewResourceTreeClient(kube).GetResourceTree(ctx, ref)
+	if err != nil {
+		return errors.Wrap(err, errGetTree)
+	}
+	logger.Debug("Got resource tree")
+
+	return errors.Wrap(PrintResourceTree(k.Stdout, root, c.Output, time.Now()), errPrintTree)
+}
+
+// parseResourceArg parses TYPE[.GROUP]/NAME or TYPE[.GROUP] NAME.
+func parseResourceArg(resource, name string) (string, string, error) {
+	kind, n, found := strings.Cut(resource, "/")
+	if found && name != "" {
+		return "", "", errors.Errorf(errFmtResourceArg, resource+" "+name)
+	}
+	if found {
+		name = n
+	}
+	if name == "" {
+		return "", "", errors.New(errMissingName)
+	}
+	return kind, name, nil
+}
+
+// resolveReference uses the supplied RESTMapper to resolve a kubectl style
+// resource type (e.g. 'postgresqlinstances.example.org') to a reference to the
+// named resource. The namespace is only set for namespaced resources.
+func resolveReference(m kmeta.RESTMapper, kind, name, namespace string) (*corev1.ObjectReference, error) {
+	fullySpecified, gr := schema.ParseResourceArg(strings.ToLower(kind))
+	gvk := schema.GroupVersionKind{}
+	if fullySpecified != nil {
+		gvk, _ = m.KindFor(*fullySpecified)
+	}
+	if gvk.Empty() {
+		var err error
+		gvk, err = m.KindFor(gr.WithVersion(""))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mapping, err := m.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &corev1.ObjectReference{APIVersion: gvk.GroupVersion().String(), Kind: gvk.Kind, Name: name}
+	if mapping.Scope.Name() == kmeta.RESTScopeNameNamespace {
+		ref.Namespace = namespace
+	}
+	return ref, nil
+}
+
+// A Resource in a tree of claims, composite resources (XRs), and composed
+// resources. Error is set if the resource could not be fetched.
+type Resource struct {
+	Unstructured composed.Unstructured `json:"object"`
+	Error        string                `json:"error,omitempty"`
+	Children     []*Resource           `json:"children,omitempty"`
+}
+
+// A ResourceTreeClient fetches trees of claims, composite resources (XRs), and
+// composed resources.
+type ResourceTreeClient struct {
+	client client.Reader
+}
+
+// NewResourceTreeClient returns a client that fetches resource trees.
+func NewResourceTreeClient(c client.Reader) *ResourceTreeClient {
+	return &ResourceTreeClient{client: c}
+}
+
+// GetResourceTree returns the tree of resources rooted at the referenced
+// resource. A claim's child is its XR, and an XR's children are its composed
+// resources. The tree recurses through nested XRs. An error is returned only
+// if the root of the tree can't be fetched; errors fetching its descendants
+// are recorded in the tree.
+func (c *ResourceTreeClient) GetResourceTree(ctx context.Context, ref *corev1.ObjectReference) (*Resource, error) {
+	root := c.getResource(ctx, ref)
+	if root.Error != "" {
+		return nil, errors.New(root.Error)
+	}
+	return root, nil
+}
+
+func (c *ResourceTreeClient) getResource(ctx context.Context, ref *corev1.ObjectReference) *Resource {
+	u := composed.New(composed.FromReference(*ref))
+	r := &Resource{Unstructured: *u}
+	if err := c.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &r.Unstructured); err != nil {
+		r.Error = errors.Wrap(err, errGetResource).Error()
+		return r
+	}
+
+	for _, cref := range childReferences(&r.Unstructured) {
+		cref := cref
+		r.Children = append(r.Children, c.getResource(ctx, &cref))
+	}
+	return r
+}
+
+// childReferences returns references to the children of the supplied
+// resource. A claim references its XR. An XR references its composed
+// resources.
+func childReferences(u *composed.Unstructured) []corev1.ObjectReference {
+	if ref := (&claim.Unstructured{Unstructured: u.Unstructured}).GetResourceReference(); ref != nil && ref.Name != "" {
+		return []corev1.ObjectReference{*ref}
+	}
+	out := make([]corev1.ObjectReference, 0)
+	for _, ref := range (&ucomposite.Unstructured{Unstructured: u.Unstructured}).GetResourceReferences() {
+		// Composed resources that are pending creation have no name.
+		if ref.Name == "" {
+			continue
+		}
+		out = append(out, ref)
+	}
+	return out
+}
+
+// PrintResourceTree prints the supplied resource tree to the supplied writer
+// in the supplied output format.
+func PrintResourceTree(w io.Writer, root *Resource, format string, now time.Time) error {
+	switch format {
+	case outputTree:
+		return printTree(w, root, now)
+	case outputJSON:
+		j, err := json.MarshalIndent(root, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, errMarshalTree)
+		}
+		_, err = fmt.Fprintln(w, string(j))
+		return err
+	case outputYAML:
+		y, err := yaml.Marshal(root)
+		if err != nil {
+			return errors.Wrap(err, errMarshalTree)
+		}
+		_, err = w.Write(y)
+		return err
+	case outputDot:
+		return printDot(w, root)
+	}
+	return errors.Errorf(errFmtOutput, format)
+}
+
+func printTree(w io.Writer, root *Resource, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSYNCED\tREADY\tAGE\tSTATUS")
+
+	var walk func(r *Resource, prefix, childPrefix string)
+	walk = func(r *Resource, prefix, childPrefix string) {
+		synced := r.Unstructured.GetCondition(xpv1.TypeSynced)
+		ready := r.Unstructured.GetCondition(xpv1.TypeReady)
+		ss, rs := string(synced.Status), string(ready.Status)
+		if r.Error != "" {
+			// We don't know the status of a resource we couldn't get.
+			ss, rs = "-", "-"
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\n", prefix, resourceName(r), ss, rs, age(r, now), status(r, synced, ready))
+
+		for i, c := range r.Children {
+			if i == len(r.Children)-1 {
+				walk(c, childPrefix+"└─ ", childPrefix+"   ")
+				continue
+			}
+			walk(c, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+	walk(root, "", "")
+
+	return tw.Flush()
+}
+
+func printDot(w io.Writer, root *Resource) error {
+	b := &strings.Builder{}
+	b.WriteString("digraph {\n")
+	b.WriteString("  node [shape=box];\n")
+
+	var walk func(r *Resource)
+	walk = func(r *Resource) {
+		synced := r.Unstructured.GetCondition(xpv1.TypeSynced)
+		ready := r.Unstructured.GetCondition(xpv1.TypeReady)
+		color := "black"
+		if r.Error != "" || synced.Status == corev1.ConditionFalse || ready.Status == corev1.ConditionFalse {
+			color = "red"
+		}
+		fmt.Fprintf(b, "  %q [label=%q, color=%q];\n", resourceName(r), fmt.Sprintf("%s\nSynced: %s\nReady: %s", resourceName(r), synced.Status, ready.Status), color)
+		for _, c := range r.Children {
+			fmt.Fprintf(b, "  %q -> %q;\n", resourceName(r), resourceName(c))
+			walk(c)
+		}
+	}
+	walk(root)
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// resourceName returns a kubectl style name for the supplied resource, e.g.
+// PostgreSQLInstance/my-db (default).
+func resourceName(r *Resource) string {
+	n := r.Unstructured.GetKind() + "/" + r.Unstructured.GetName()
+	if ns := r.Unstructured.GetNamespace(); ns != "" {
+		n += " (" + ns + ")"
+	}
+	return n
+}
+
+func age(r *Resource, now time.Time) string {
+	ts := r.Unstructured.GetCreationTimestamp()
+	if ts.IsZero() {
+		return "-"
+	}
+	return duration.HumanDuration(now.Sub(ts.Time))
+}
+
+// status returns the most relevant status of the supplied resource. This is an
+// error if the resource couldn't be fetched, the reason and message of a Synced
+// or Ready condition that isn't true, or the reason of the Ready condition.
+func status(r *Resource, synced, ready xpv1.Condition) string {
+	if r.Error != "" {
+		return r.Error
+	}
+	for _, c := range []xpv1.Condition{synced, ready} {
+		if c.Status != corev1.ConditionFalse {
+			continue
+		}
+		if c.Message == "" {
+			return string(c.Reason)
+		}
+		return string(c.Reason) + ": " + c.Message
+	}
+	return string(ready.Reason)
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestParseResourceArg(t *testing.T) {
+	type args struct {
+		resource string
+		name     string
+	}
+	type want struct {
+		kind string
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Slash": {
+			reason: "We should support TYPE/NAME.",
+			args:   args{resource: "postgresqlinstances.example.org/my-db"},
+			want:   want{kind: "postgresqlinstances.example.org", name: "my-db"},
+		},
+		"Separate": {
+			reason: "We should support TYPE NAME.",
+			args:   args{resource: "postgresqlinstance", name: "my-db"},
+			want:   want{kind: "postgresqlinstance", name: "my-db"},
+		},
+		"Both": {
+			reason: "We should return an error if the name is specified twice.",
+			args:   args{resource: "postgresqlinstance/my-db", name: "my-db"},
+			want:   want{err: errors.Errorf(errFmtResourceArg, "postgresqlinstance/my-db my-db")},
+		},
+		"NoName": {
+			reason: "We should return an error if no name is specified.",
+			args:   args{resource: "postgresqlinstance"},
+			want:   want{err: errors.New(errMissingName)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kind, n, err := parseResourceArg(tc.args.resource, tc.args.name)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nparseResourceArg(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.kind, kind); diff != "" {
+				t.Errorf("\n%s\nparseResourceArg(...): -want kind, +got kind:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, n); diff != "" {
+				t.Errorf("\n%s\nparseResourceArg(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveReference(t *testing.T) {
+	claim := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "PostgreSQLInstance"}
+	xr := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XPostgreSQLInstance"}
+
+	m := kmeta.NewDefaultRESTMapper([]schema.GroupVersion{claim.GroupVersion()})
+	m.Add(claim, kmeta.RESTScopeNamespace)
+	m.Add(xr, kmeta.RESTScopeRoot)
+
+	type args struct {
+		kind      string
+		name      string
+		namespace string
+	}
+	type want struct {
+		ref *corev1.ObjectReference
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Namespaced": {
+			reason: "We should resolve a claim's singular resource name, and include its namespace.",
+			args:   args{kind: "PostgreSQLInstance", name: "my-db", namespace: "prod"},
+			want: want{ref: &corev1.ObjectReference{
+				APIVersion: "example.org/v1",
+				Kind:       "PostgreSQLInstance",
+				Name:       "my-db",
+				Namespace:  "prod",
+			}},
+		},
+		"ClusterScoped": {
+			reason: "We should resolve an XR's plural, group qualified resource name, and omit its namespace.",
+			args:   args{kind: "xpostgresqlinstances.example.org", name: "my-db-8x7bd", namespace: "prod"},
+			want: want{ref: &corev1.ObjectReference{
+				APIVersion: "example.org/v1",
+				Kind:       "XPostgreSQLInstance",
+				Name:       "my-db-8x7bd",
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ref, err := resolveReference(m, tc.args.kind, tc.args.name, tc.args.namespace)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nresolveReference(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ref, ref); diff != "" {
+				t.Errorf("\n%s\nresolveReference(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// A traceObject to be returned by our mock client.
+type traceObject struct {
+	kind       string
+	name       string
+	namespace  string
+	conditions []xpv1.Condition
+	resRef     *corev1.ObjectReference
+	resRefs    []corev1.ObjectReference
+}
+
+func (o traceObject) resource() *Resource {
+	cd := composed.New(composed.WithConditions(o.conditions...))
+	cd.SetAPIVersion("example.org/v1")
+	cd.SetKind(o.kind)
+	cd.SetName(o.name)
+	cd.SetNamespace(o.namespace)
+	cd.SetCreationTimestamp(metav1.NewTime(time.Unix(0, 0)))
+	p := fieldpath.Pave(cd.Object)
+	if o.resRef != nil {
+		_ = p.SetValue("spec.resourceRef", o.resRef)
+	}
+	if o.resRefs != nil {
+		_ = p.SetValue("spec.resourceRefs", o.resRefs)
+	}
+	return &Resource{Unstructured: *cd}
+}
+
+func TestGetResourceTree(t *testing.T) {
+	claim := traceObject{
+		kind:       "PostgreSQLInstance",
+		name:       "my-db",
+		namespace:  "default",
+		conditions: []xpv1.Condition{xpv1.ReconcileSuccess(), xpv1.Available()},
+		resRef:     &corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "XPostgreSQLInstance", Name: "my-db-8x7bd"},
+	}
+	xr := traceObject{
+		kind:       "XPostgreSQLInstance",
+		name:       "my-db-8x7bd",
+		conditions: []xpv1.Condition{xpv1.ReconcileSuccess(), xpv1.Creating()},
+		resRefs: []corev1.ObjectReference{
+			{APIVersion: "example.org/v1", Kind: "XNetwork", Name: "my-db-8x7bd-net"},
+			{APIVersion: "example.org/v1", Kind: "RDSInstance", Name: "my-db-8x7bd-rds"},
+			{APIVersion: "example.org/v1", Kind: "RDSInstance"},
+		},
+	}
+	network := traceObject{
+		kind:       "XNetwork",
+		name:       "my-db-8x7bd-net",
+		conditions: []xpv1.Condition{xpv1.ReconcileSuccess(), xpv1.Available()},
+		resRefs: []corev1.ObjectReference{
+			{APIVersion: "example.org/v1", Kind: "VPC", Name: "my-db-8x7bd-vpc"},
+		},
+	}
+	vpc := traceObject{
+		kind:       "VPC",
+		name:       "my-db-8x7bd-vpc",
+		conditions: []xpv1.Condition{xpv1.ReconcileSuccess(), xpv1.Available()},
+	}
+
+	objects := map[string]traceObject{}
+	for _, o := range []traceObject{claim, xr, network, vpc} {
+		objects[o.name] = o
+	}
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			o, ok := objects[key.Name]
+			if !ok {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "rdsinstances"}, key.Name)
+			}
+			o.resource().Unstructured.DeepCopyInto(&obj.(*composed.Unstructured).Unstructured)
+			return nil
+		},
+	}
+
+	type want struct {
+		tree string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		ref    *corev1.ObjectReference
+		want   want
+	}{
+		"RootNotFound": {
+			reason: "We should return an error if we can't get the root of the tree.",
+			ref:    &corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "PostgreSQLInstance", Name: "nope", Namespace: "default"},
+			want: want{
+				err: errors.New(errors.Wrap(kerrors.NewNotFound(schema.GroupResource{Resource: "rdsinstances"}, "nope"), errGetResource).Error()),
+			},
+		},
+		"Claim": {
+			reason: "We should walk from a claim through its XR to its composed resources, recursing through nested XRs.",
+			ref:    &corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "PostgreSQLInstance", Name: "my-db", Namespace: "default"},
+			want: want{
+				tree: `NAME                                SYNCED  READY  AGE  STATUS
+PostgreSQLInstance/my-db (default)  True    True   60s  Available
+└─ XPostgreSQLInstance/my-db-8x7bd  True    False  60s  Creating
+   ├─ XNetwork/my-db-8x7bd-net      True    True   60s  Available
+   │  └─ VPC/my-db-8x7bd-vpc        True    True   60s  Available
+   └─ RDSInstance/my-db-8x7bd-rds   -       -      -    cannot get requested resource: rdsinstances "my-db-8x7bd-rds" not found
+`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			root, err := NewResourceTreeClient(kube).GetResourceTree(context.Background(), tc.ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetResourceTree(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			b := &bytes.Buffer{}
+			if err := PrintResourceTree(b, root, outputTree, time.Unix(60, 0)); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.tree, b.String()); diff != "" {
+				t.Errorf("\n%s\nGetResourceTree(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPrintResourceTree(t *testing.T) {
+	root := traceObject{
+		kind:       "XNetwork",
+		name:       "net",
+		conditions: []xpv1.Condition{xpv1.ReconcileError(errors.New("boom")), xpv1.Available()},
+	}.resource()
+	root.Children = []*Resource{traceObject{kind: "VPC", name: "vpc"}.resource()}
+
+	type want struct {
+		out string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		format string
+		want   want
+	}{
+		"Tree": {
+			reason: "We should print the reason and message of conditions that aren't true.",
+			format: outputTree,
+			want: want{
+				out: "NAME          SYNCED   READY    AGE  STATUS\n" +
+					"XNetwork/net  False    True     60s  ReconcileError: boom\n" +
+					"└─ VPC/vpc    Unknown  Unknown  60s  \n",
+			},
+		},
+		"Dot": {
+			reason: "We should print a Graphviz graph, highlighting unhealthy resources.",
+			format: outputDot,
+			want: want{
+				out: `digraph {
+  node [shape=box];
+  "XNetwork/net" [label="XNetwork/net\nSynced: False\nReady: True", color="red"];
+  "XNetwork/net" -> "VPC/vpc";
+  "VPC/vpc" [label="VPC/vpc\nSynced: Unknown\nReady: Unknown", color="black"];
+}
+`,
+			},
+		},
+		"Unsupported": {
+			reason: "We should return an error for unsupported output formats.",
+			format: "wat",
+			want: want{
+				err: errors.Errorf(errFmtOutput, "wat"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			err := PrintResourceTree(b, root, tc.format, time.Unix(60, 0))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPrintResourceTree(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.out, b.String()); diff != "" {
+				t.Errorf("\n%s\nPrintResourceTree(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -5,18 +5,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	typedclient "github.com/crossplane/crossplane/internal/client/clientset/versioned/typed/pkg/v1"
+	fnclient "github.com/crossplane/crossplane/internal/client/clientset/versioned/typed/pkg/v1alpha1"
 
 	// Load all the auth plugins for the cloud providers.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -26,6 +32,7 @@ import (
 type updateCmd struct {
 	Configuration updateConfigCmd   `cmd:"" help:"Update a Configuration package."`
 	Provider      updateProviderCmd `cmd:"" help:"Update a Provider package."`
+	Function      updateFunctionCmd `cmd:"" help:"Update a Function package."`
 }
 
 // Run runs the update cmd.
@@ -147,3 +154,67 @@ func (c *updateProviderCmd) Run(k *kong.Context, logger logging.Logger) error {
 	_, err = fmt.Fprintf(k.Stdout, "%s/%s updated\n", strings.ToLower(v1.ProviderGroupKind), res.GetName())
 	return err
 }
+
+// updateFunctionCmd updates a Function.
+type updateFunctionCmd struct {
+	Name string        `arg:"" help:"Name of Function."`
+	Tag  string        `arg:"" help:"Updated tag for Function package."`
+	Wait time.Duration `short:"w" help:"Wait for the updated package to become healthy."`
+}
+
+// Run runs the Function update cmd.
+func (c *updateFunctionCmd) Run(k *kong.Context, logger logging.Logger) error {
+	logger = logger.WithValues("Name", c.Name)
+	kubeConfig, err := ctrl.GetConfig()
+	if err != nil {
+		logger.Debug(errKubeConfig, "error", err)
+		return errors.Wrap(err, errKubeConfig)
+	}
+	logger.Debug("Found kubeconfig")
+	kube, err := fnclient.NewForConfig(kubeConfig)
+	if err != nil {
+		logger.Debug(errKubeClient, "error", err)
+		return errors.Wrap(err, errKubeClient)
+	}
+	logger.Debug("Created kubernetes client")
+	prevFn, err := kube.Functions().Get(context.Background(), c.Name, metav1.GetOptions{})
+	if err != nil {
+		err = warnIfNotFound(err)
+		logger.Debug("Failed to update function", "error", err)
+		return errors.Wrap(err, "cannot update function")
+	}
+	logger.Debug("Found previous function object")
+	pkgReference, err := name.ParseReference(prevFn.Spec.Package, name.WithDefaultRegistry(""))
+	if err != nil {
+		logger.Debug("Failed to update function", "error", err)
+		return errors.Wrap(err, "cannot update function")
+	}
+	newPkg := ""
+	if strings.HasPrefix(c.Tag, "sha256") {
+		newPkg = pkgReference.Context().Digest(c.Tag).Name()
+	} else {
+		newPkg = pkgReference.Context().Tag(c.Tag).Name()
+	}
+	prevFn.Spec.Package = newPkg
+	req, err := json.Marshal(prevFn)
+	if err != nil {
+		logger.Debug("Failed to update function", "error", err)
+		return errors.Wrap(err, "cannot update function")
+	}
+	res, err := kube.Functions().Patch(context.Background(), c.Name, types.MergePatchType, req, metav1.PatchOptions{})
+	if err != nil {
+		err = warnIfNotFound(err)
+		logger.Debug("Failed to update function", "error", err)
+		return errors.Wrap(err, "cannot update function")
+	}
+	if c.Wait != 0 {
+		logger.Debug(msgFunctionWaiting)
+		watchList := cache.NewListWatchFromClient(kube.RESTClient(), "functions", corev1.NamespaceAll, fields.Everything())
+		if err := waitForFunction(context.Background(), kube, watchList, c.Name, newPkg, c.Wait, logger); err != nil {
+			logger.Debug("Failed to wait for function", "error", err)
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(k.Stdout, "%s/%s updated\n", strings.ToLower(v1alpha1.FunctionGroupKind), res.GetName())
+	return err
+}
@@ -31,6 +31,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/crossplane/crossplane-runtime/pkg/certificates"
@@ -42,6 +43,7 @@ import (
 
 	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/pkg"
 	pkgcontroller "github.com/crossplane/crossplane/internal/controller/pkg/controller"
@@ -49,6 +51,7 @@ import (
 	"github.com/crossplane/crossplane/internal/initializer"
 	"github.com/crossplane/crossplane/internal/oci"
 	"github.com/crossplane/crossplane/internal/transport"
+	"github.com/crossplane/crossplane/internal/usage"
 	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/composition"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
@@ -94,10 +97,14 @@ type startCommand struct {
 	ESSTLSSecretName string        `help:"The name of the TLS Secret that will be used by Crossplane and providers as clients of External Secret Store plugins." env:"ESS_TLS_SECRET_NAME"`
 	ESSTLSCertsDir   string        `help:"The path of the folder which will store TLS certificates to be used by Crossplane and providers for communicating with External Secret Store plugins." env:"ESS_TLS_CERTS_DIR"`
 
+	MaxFunctionResponseCacheSize int `help:"The maximum number of Composition Function responses to cache. Only responses that specify a TTL are cached. Set to 0 for an unbounded cache." default:"1024"`
+
 	EnableEnvironmentConfigs                 bool `group:"Alpha Features:" help:"Enable support for EnvironmentConfigs."`
+	EnableEnvironmentSecrets                 bool `group:"Alpha Features:" help:"Enable reading composition environment data from Secrets. Requires EnvironmentConfigs to be enabled."`
 	EnableExternalSecretStores               bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
 	EnableCompositionFunctions               bool `group:"Alpha Features:" help:"Enable support for Composition Functions."`
 	EnableCompositionWebhookSchemaValidation bool `group:"Alpha Features:" help:"Enable support for Composition validation using schemas."`
+	EnableUsages                             bool `group:"Alpha Features:" help:"Enable support for deletion ordering and resource protection with Usages."`
 
 	// These are GA features that previously had alpha or beta feature flags.
 	// You can't turn off a GA feature. We maintain the flags to avoid breaking
@@ -175,6 +182,10 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		feats.Enable(features.EnableAlphaEnvironmentConfigs)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaEnvironmentConfigs)
 	}
+	if c.EnableEnvironmentSecrets {
+		feats.Enable(features.EnableAlphaEnvironmentSecrets)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaEnvironmentSecrets)
+	}
 	if c.EnableCompositionFunctions {
 		feats.Enable(features.EnableAlphaCompositionFunctions)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaCompositionFunctions)
@@ -183,6 +194,10 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		feats.Enable(features.EnableAlphaCompositionWebhookSchemaValidation)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaCompositionWebhookSchemaValidation)
 	}
+	if c.EnableUsages {
+		feats.Enable(features.EnableAlphaUsages)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaUsages)
+	}
 	if !c.EnableCompositionRevisions {
 		log.Info("CompositionRevisions feature is GA and cannot be disabled. The --enable-composition-revisions flag will be removed in a future release.")
 	}
@@ -218,6 +233,21 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		Registry:       c.Registry,
 	}
 
+	if c.EnableCompositionFunctions {
+		m := composite.NewPrometheusFunctionCacheMetrics()
+		metrics.Registry.MustRegister(m)
+
+		pm := composite.NewPrometheusFunctionPipelineMetrics()
+		metrics.Registry.MustRegister(pm)
+		ao.FunctionMetrics = pm
+
+		ao.FunctionRunner = composite.NewCachingFunctionRunner(
+			composite.NewPackagedFunctionRunner(mgr.GetClient()),
+			composite.WithMaxCacheSize(c.MaxFunctionResponseCacheSize),
+			composite.WithFunctionCacheMetrics(m),
+		)
+	}
+
 	if err := apiextensions.Setup(mgr, ao); err != nil {
 		return errors.Wrap(err, "Cannot setup API extension controllers")
 	}
@@ -257,6 +287,11 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		if err := composition.SetupWebhookWithManager(mgr, o); err != nil {
 			return errors.Wrap(err, "cannot setup webhook for compositions")
 		}
+		if o.Features.Enabled(features.EnableAlphaUsages) {
+			if err := usage.SetupWebhookWithManager(mgr, o); err != nil {
+				return errors.Wrap(err, "cannot setup webhook for usages")
+			}
+		}
 	}
 
 	return errors.Wrap(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
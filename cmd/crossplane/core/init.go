@@ -29,6 +29,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	"github.com/crossplane/crossplane/internal/initializer"
+	"github.com/crossplane/crossplane/internal/usage"
 )
 
 // initCommand configuration for the initialization of core Crossplane controllers.
@@ -43,6 +44,8 @@ type initCommand struct {
 	WebhookServicePort      int32  `help:"The port of the Service that the webhook service will be run." env:"WEBHOOK_SERVICE_PORT"`
 	ESSTLSClientSecretName  string `help:"The name of the Secret that the initializer will fill with ESS TLS client certificate." env:"ESS_TLS_CLIENT_SECRET_NAME"`
 	ESSTLSServerSecretName  string `help:"The name of the Secret that the initializer will fill with ESS TLS server certificate." env:"ESS_TLS_SERVER_SECRET_NAME"`
+
+	EnableUsages bool `group:"Alpha Features:" help:"Install the webhook configuration used by Usages. Must match the flag passed to start."`
 }
 
 // Run starts the initialization process.
@@ -73,7 +76,7 @@ func (c *initCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 			initializer.NewCoreCRDsMigrator("compositionrevisions.apiextensions.crossplane.io", "v1alpha1"),
 			initializer.NewCoreCRDsMigrator("locks.pkg.crossplane.io", "v1alpha1"),
 			initializer.NewCoreCRDs("/crds", s, initializer.WithWebhookTLSSecretRef(nn)),
-			initializer.NewWebhookConfigurations("/webhookconfigurations", s, nn, svc))
+			initializer.NewWebhookConfigurations("/webhookconfigurations", s, nn, svc, c.webhookConfigurationsOptions()...))
 	} else {
 		steps = append(steps,
 			initializer.NewCoreCRDsMigrator("compositionrevisions.apiextensions.crossplane.io", "v1alpha1"),
@@ -97,3 +100,14 @@ func (c *initCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 	log.Info("Initialization has been completed")
 	return nil
 }
+
+// webhookConfigurationsOptions returns options that skip the webhook
+// configurations of disabled alpha features. A webhook configuration with a
+// failure policy of Fail blocks requests when nothing serves its webhook.
+func (c *initCommand) webhookConfigurationsOptions() []initializer.WebhookConfigurationsOption {
+	var skip []string
+	if !c.EnableUsages {
+		skip = append(skip, usage.WebhookConfigurationName)
+	}
+	return []initializer.WebhookConfigurationsOption{initializer.WithoutWebhookConfigurations(skip...)}
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/definition"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/offered"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/usage"
+	"github.com/crossplane/crossplane/internal/features"
 )
 
 // Setup API extensions controllers.
@@ -36,5 +38,11 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		return err
 	}
 
+	if o.Features.Enabled(features.EnableAlphaUsages) {
+		if err := usage.Setup(mgr, o); err != nil {
+			return err
+		}
+	}
+
 	return offered.Setup(mgr, o)
 }
@@ -0,0 +1,289 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+// Error strings.
+const (
+	errCELEnv       = "cannot create CEL environment"
+	errCELCompile   = "cannot compile CEL expression"
+	errCELProgram   = "cannot create CEL program"
+	errCELEval      = "cannot evaluate CEL expression"
+	errCELComposite = "cannot convert composite resource to CEL variable"
+
+	errFmtCELOutputType  = "CEL expression must return %s, got %s"
+	errFmtCELCost        = "CEL expression's estimated minimum cost %d exceeds the limit of %d"
+	errFmtCELOutputValue = "cannot convert CEL value of type %s"
+	errFmtCELMapKey      = "CEL map keys must be strings, got %s"
+)
+
+// The variables available to CEL expressions.
+const (
+	// CELVarSelf is the input of a CEL transform, or the composed resource
+	// in the case of a CEL readiness check.
+	CELVarSelf = "self"
+
+	// CELVarComposite is the composite resource.
+	CELVarComposite = "xr"
+
+	// CELVarEnvironment is the environment.
+	CELVarEnvironment = "environment"
+)
+
+// CELCostLimit is the maximum cost a CEL expression may incur when it is
+// evaluated. Cost is roughly proportional to the number of operations an
+// expression performs. Evaluation is aborted with an error once the limit is
+// exceeded, so that an expensive expression can't stall a reconcile. This is
+// the same per-expression limit Kubernetes uses for CRD validation rules.
+const CELCostLimit = 1000000
+
+// celProgramCacheSize is the maximum number of compiled CEL programs that are
+// cached. Compositions typically use a small, fixed set of expressions, so
+// this is generous.
+const celProgramCacheSize = 1000
+
+// celPrograms caches compiled CEL programs, so that each expression is only
+// compiled once rather than on every reconcile.
+var celPrograms = newCELProgramCache(celProgramCacheSize)
+
+// TransformVariables are made available to transforms that evaluate
+// expressions, alongside their input.
+type TransformVariables struct {
+	// Composite is the composite resource being reconciled.
+	Composite resource.Composite
+
+	// Environment is the composite resource's environment, if any.
+	Environment *env.Environment
+}
+
+// CompileCELTransform compiles the supplied CEL transform expression. The
+// expression may refer to the self, xr, and environment variables.
+func CompileCELTransform(expression string) (cel.Program, error) {
+	return compileCEL(expression, nil, CELVarSelf, CELVarComposite, CELVarEnvironment)
+}
+
+// CompileCELReadinessCheck compiles the supplied CEL readiness check
+// expression. The expression may refer to the self variable, and must return
+// a boolean.
+func CompileCELReadinessCheck(expression string) (cel.Program, error) {
+	return compileCEL(expression, cel.BoolType, CELVarSelf)
+}
+
+// compileCEL compiles the supplied expression, making the supplied variables
+// available to it. If a type is supplied the expression must return that type,
+// or a dynamic type that may resolve to it at runtime. Expressions that would
+// exceed CELCostLimit regardless of their input are rejected. Compiled programs
+// are cached.
+func compileCEL(expression string, out *cel.Type, vars ...string) (cel.Program, error) {
+	key := strings.Join(vars, ",") + "|" + expression
+	if out != nil {
+		key = out.String() + "|" + key
+	}
+	return celPrograms.Get(key, func() (cel.Program, error) {
+		opts := []cel.EnvOption{ext.Strings()}
+		for _, v := range vars {
+			opts = append(opts, cel.Variable(v, cel.DynType))
+		}
+		e, err := cel.NewEnv(opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, errCELEnv)
+		}
+		ast, iss := e.Compile(expression)
+		if iss.Err() != nil {
+			return nil, errors.Wrap(iss.Err(), errCELCompile)
+		}
+		if out != nil && ast.OutputType() != cel.DynType && !out.IsAssignableType(ast.OutputType()) {
+			return nil, errors.Errorf(errFmtCELOutputType, out, ast.OutputType())
+		}
+		// We can't know how large our (dynamically typed) inputs will be, so
+		// we only reject expressions that would exceed the cost limit even if
+		// their inputs were empty. These would fail every time they were
+		// evaluated.
+		est, err := e.EstimateCost(ast, celCostEstimator{})
+		if err != nil {
+			return nil, errors.Wrap(err, errCELCompile)
+		}
+		if est.Min > CELCostLimit {
+			return nil, errors.Errorf(errFmtCELCost, est.Min, CELCostLimit)
+		}
+		p, err := e.Program(ast, cel.CostLimit(CELCostLimit))
+		return p, errors.Wrap(err, errCELProgram)
+	})
+}
+
+// A celCostEstimator provides no estimates, so that CEL assumes nothing about
+// the size of its inputs or the cost of its functions.
+type celCostEstimator struct{}
+
+func (celCostEstimator) EstimateSize(_ checker.AstNode) *checker.SizeEstimate { return nil }
+
+func (celCostEstimator) EstimateCallCost(_, _ string, _ *checker.AstNode, _ []checker.AstNode) *checker.CallEstimate {
+	return nil
+}
+
+// A celProgramCache is a size-limited, least recently used cache of compiled
+// CEL programs. Programs are safe for concurrent use.
+type celProgramCache struct {
+	maxSize int
+
+	mx      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type cachedCELProgram struct {
+	key string
+	prg cel.Program
+}
+
+func newCELProgramCache(maxSize int) *celProgramCache {
+	return &celProgramCache{maxSize: maxSize, lru: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get returns the cached program for the supplied key, calling the supplied
+// compile function and caching its result if there is none. Errors aren't
+// cached.
+func (c *celProgramCache) Get(key string, compile func() (cel.Program, error)) (cel.Program, error) {
+	c.mx.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		c.mx.Unlock()
+		return e.Value.(*cachedCELProgram).prg, nil
+	}
+	c.mx.Unlock()
+
+	// We compile without holding the lock. Concurrent callers may compile
+	// the same expression, which is harmless.
+	prg, err := compile()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*cachedCELProgram).prg, nil
+	}
+	c.entries[key] = c.lru.PushFront(&cachedCELProgram{key: key, prg: prg})
+	for c.lru.Len() > c.maxSize {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedCELProgram).key)
+	}
+	return prg, nil
+}
+
+// ResolveCEL resolves a CEL transform.
+func ResolveCEL(t v1.CELTransform, input any, vars TransformVariables) (any, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	p, err := CompileCELTransform(t.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	xr := map[string]any{}
+	if vars.Composite != nil {
+		if xr, err = runtime.DefaultUnstructuredConverter.ToUnstructured(vars.Composite); err != nil {
+			return nil, errors.Wrap(err, errCELComposite)
+		}
+	}
+	e := map[string]any{}
+	if vars.Environment != nil {
+		e = vars.Environment.UnstructuredContent()
+	}
+
+	out, _, err := p.Eval(map[string]any{
+		CELVarSelf:        input,
+		CELVarComposite:   xr,
+		CELVarEnvironment: e,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errCELEval)
+	}
+	return nativeFromCEL(out)
+}
+
+// nativeFromCEL converts the supplied CEL value to the kind of value found in
+// an unstructured Kubernetes object, i.e. nil, a bool, an int64, a float64, a
+// string, a []any, or a map[string]any.
+func nativeFromCEL(v ref.Val) (any, error) {
+	switch v.Type() {
+	case types.NullType:
+		return nil, nil
+	case types.BoolType, types.IntType, types.DoubleType, types.StringType:
+		return v.Value(), nil
+	case types.UintType:
+		return int64(v.Value().(uint64)), nil
+	case types.ListType:
+		l := v.(traits.Lister)
+		out := make([]any, 0)
+		for it := l.Iterator(); it.HasNext() == types.True; {
+			e, err := nativeFromCEL(it.Next())
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, e)
+		}
+		return out, nil
+	case types.MapType:
+		m := v.(traits.Mapper)
+		out := make(map[string]any)
+		for it := m.Iterator(); it.HasNext() == types.True; {
+			k := it.Next()
+			ks, ok := k.(types.String)
+			if !ok {
+				return nil, errors.Errorf(errFmtCELMapKey, k.Type().TypeName())
+			}
+			e, err := nativeFromCEL(m.Get(k))
+			if err != nil {
+				return nil, err
+			}
+			out[string(ks)] = e
+		}
+		return out, nil
+	}
+
+	// Values like timestamps and durations are most usefully represented by
+	// their string form.
+	s := v.ConvertToType(types.StringType)
+	if types.IsError(s) {
+		return nil, errors.Errorf(errFmtCELOutputValue, v.Type().TypeName())
+	}
+	return s.Value(), nil
+}
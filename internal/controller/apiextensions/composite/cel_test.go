@@ -0,0 +1,257 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+func TestResolveCEL(t *testing.T) {
+	xr := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"region": "us-east-1",
+		},
+	}}}
+	e := &env.Environment{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"tier": "premium",
+	}}}
+
+	large := make([]any, 1000)
+	for i := range large {
+		large[i] = int64(i)
+	}
+
+	type args struct {
+		t     v1.CELTransform
+		input any
+		vars  TransformVariables
+	}
+	type want struct {
+		out any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ExpressionRequired": {
+			reason: "We should return an error if the expression is empty.",
+			args: args{
+				t: v1.CELTransform{},
+			},
+			want: want{
+				err: field.Required(field.NewPath("expression"), "expression must be set"),
+			},
+		},
+		"CompileError": {
+			reason: "We should return an error if the expression doesn't compile.",
+			args: args{
+				t: v1.CELTransform{Expression: "foo"},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("ERROR: <input>:1:1: undeclared reference to 'foo' (in container '')\n | foo\n | ^"), errCELCompile),
+			},
+		},
+		"Self": {
+			reason: "The input should be available as self.",
+			args: args{
+				t:     v1.CELTransform{Expression: "self * 2"},
+				input: int64(3),
+			},
+			want: want{
+				out: int64(6),
+			},
+		},
+		"Composite": {
+			reason: "The composite resource should be available as xr.",
+			args: args{
+				t:     v1.CELTransform{Expression: "xr.spec.region + '-' + self"},
+				input: "db",
+				vars:  TransformVariables{Composite: xr},
+			},
+			want: want{
+				out: "us-east-1-db",
+			},
+		},
+		"Environment": {
+			reason: "The environment should be available as environment.",
+			args: args{
+				t:    v1.CELTransform{Expression: "environment.tier == 'premium' ? 'db.r5.large' : 'db.t3.small'"},
+				vars: TransformVariables{Composite: xr, Environment: e},
+			},
+			want: want{
+				out: "db.r5.large",
+			},
+		},
+		"NilEnvironment": {
+			reason: "A nil environment should be available as an empty map.",
+			args: args{
+				t: v1.CELTransform{Expression: "has(environment.tier)"},
+			},
+			want: want{
+				out: false,
+			},
+		},
+		"Object": {
+			reason: "Maps returned by the expression should be converted to objects.",
+			args: args{
+				t:     v1.CELTransform{Expression: "{'name': self, 'tags': [self.upperAscii()], 'size': 1u}"},
+				input: "db",
+			},
+			want: want{
+				out: map[string]any{
+					"name": "db",
+					"tags": []any{"DB"},
+					"size": int64(1),
+				},
+			},
+		},
+		"NonStringMapKey": {
+			reason: "We should return an error if the expression returns a map with non-string keys.",
+			args: args{
+				t: v1.CELTransform{Expression: "{1: 'one'}"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtCELMapKey, "int"),
+			},
+		},
+		"CostLimitExceeded": {
+			reason: "We should return an error if evaluating the expression exceeds the cost limit.",
+			args: args{
+				t:     v1.CELTransform{Expression: "self.all(x, self.all(y, x + y >= 0))"},
+				input: large,
+			},
+			want: want{
+				err: errors.Wrap(errors.New("operation cancelled: actual cost limit exceeded"), errCELEval),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveCEL(tc.args.t, tc.args.input, tc.args.vars)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveCEL(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("\n%s\nResolveCEL(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompileCELTransform(t *testing.T) {
+	// A literal list of 1000 elements. Iterating it twice over costs more
+	// than CELCostLimit regardless of the transform's input.
+	elems := make([]string, 1000)
+	for i := range elems {
+		elems[i] = strconv.Itoa(i)
+	}
+	l := "[" + strings.Join(elems, ",") + "]"
+
+	cases := map[string]struct {
+		reason     string
+		expression string
+		wantErr    bool
+	}{
+		"Valid": {
+			reason:     "We should compile a valid expression.",
+			expression: "self.size() > 0 && xr.spec.region == environment.region",
+		},
+		"CompileError": {
+			reason:     "We should return an error if the expression doesn't compile.",
+			expression: "self.",
+			wantErr:    true,
+		},
+		"UnboundedInput": {
+			reason:     "We should not reject an expression whose cost depends on the size of its input.",
+			expression: "self.all(x, self.all(y, x + y >= 0))",
+		},
+		"CostLimitExceeded": {
+			reason:     "We should reject an expression that would exceed the cost limit regardless of its input.",
+			expression: l + ".map(x, " + l + ".map(y, x + y))",
+			wantErr:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := CompileCELTransform(tc.expression)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("\n%s\nCompileCELTransform(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCELProgramCache(t *testing.T) {
+	compiles := 0
+	compile := func(expression string) func() (cel.Program, error) {
+		return func() (cel.Program, error) {
+			compiles++
+			return compileCEL(expression, nil, CELVarSelf)
+		}
+	}
+
+	c := newCELProgramCache(2)
+
+	// Compile two expressions, filling the cache.
+	for _, e := range []string{"self + 1", "self + 2", "self + 1"} {
+		if _, err := c.Get(e, compile(e)); err != nil {
+			t.Fatalf("c.Get(%q): %v", e, err)
+		}
+	}
+	if compiles != 2 {
+		t.Errorf("c.Get(...): want 2 compiles after a cache hit, got %d", compiles)
+	}
+
+	// Adding a third expression should evict the least recently used one,
+	// which is "self + 2".
+	if _, err := c.Get("self + 3", compile("self + 3")); err != nil {
+		t.Fatalf("c.Get(...): %v", err)
+	}
+	if _, ok := c.entries["self + 2"]; ok {
+		t.Errorf("c.Get(...): want least recently used program to be evicted")
+	}
+	if _, ok := c.entries["self + 1"]; !ok {
+		t.Errorf("c.Get(...): want recently used program to be cached")
+	}
+
+	// Compile errors should not be cached.
+	if _, err := c.Get("self.", compile("self.")); err == nil {
+		t.Errorf("c.Get(...): want compile error")
+	}
+	if _, ok := c.entries["self."]; ok {
+		t.Errorf("c.Get(...): want compile error not to be cached")
+	}
+}
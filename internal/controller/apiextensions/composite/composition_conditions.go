@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"encoding/json"
+	"reflect"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+// Error strings.
+const (
+	errConditionValue = "cannot unmarshal condition value"
+	errConditionField = "cannot marshal condition field value"
+
+	errFmtConditionSourceNotSupported   = "condition source %s is not supported"
+	errFmtConditionOperatorNotSupported = "condition operator %s is not supported"
+	errFmtConditionFieldPath            = "cannot get condition field path %q"
+	errFmtCondition                     = "cannot evaluate the condition of composed resource %q"
+	errFmtPatchCondition                = "cannot evaluate the condition of the patch at index %d"
+)
+
+// ConditionMet returns true if the supplied condition is met by the supplied
+// composite resource or environment, depending on the condition's source. A
+// nil condition is always met. A nil environment is treated as empty.
+func ConditionMet(c *v1.Condition, xr resource.Composite, e *env.Environment) (bool, error) { //nolint:gocyclo // Only slightly over (10).
+	if c == nil {
+		return true, nil
+	}
+
+	var from map[string]any
+	switch c.GetSource() {
+	case v1.ConditionSourceComposite:
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(xr)
+		if err != nil {
+			return false, err
+		}
+		from = u
+	case v1.ConditionSourceEnvironment:
+		if e != nil {
+			from = e.UnstructuredContent()
+		}
+	default:
+		return false, errors.Errorf(errFmtConditionSourceNotSupported, c.Source)
+	}
+
+	v, err := fieldpath.Pave(from).GetValue(c.FieldPath)
+	if resource.Ignore(fieldpath.IsNotFound, err) != nil {
+		return false, errors.Wrapf(err, errFmtConditionFieldPath, c.FieldPath)
+	}
+	exists := err == nil
+
+	switch c.Operator {
+	case v1.ConditionOperatorExists:
+		return exists, nil
+	case v1.ConditionOperatorDoesNotExist:
+		return !exists, nil
+	case v1.ConditionOperatorEquals, v1.ConditionOperatorNotEquals:
+		eq := false
+		if exists {
+			var want []extv1.JSON
+			if c.Value != nil {
+				want = append(want, *c.Value)
+			}
+			if eq, err = matchesAny(v, want); err != nil {
+				return false, err
+			}
+		}
+		return eq == (c.Operator == v1.ConditionOperatorEquals), nil
+	case v1.ConditionOperatorIn, v1.ConditionOperatorNotIn:
+		in := false
+		if exists {
+			if in, err = matchesAny(v, c.Values); err != nil {
+				return false, err
+			}
+		}
+		return in == (c.Operator == v1.ConditionOperatorIn), nil
+	}
+
+	return false, errors.Errorf(errFmtConditionOperatorNotSupported, c.Operator)
+}
+
+// matchesAny returns true if the supplied value is equal to any of the
+// supplied JSON values. Values are compared as JSON, such that for example the
+// integer 1 is equal to the JSON number 1.0.
+func matchesAny(v any, values []extv1.JSON) (bool, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return false, errors.Wrap(err, errConditionField)
+	}
+	var got any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		return false, errors.Wrap(err, errConditionField)
+	}
+
+	for _, value := range values {
+		var want any
+		if err := json.Unmarshal(value.Raw, &want); err != nil {
+			return false, errors.Wrap(err, errConditionValue)
+		}
+		if reflect.DeepEqual(got, want) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
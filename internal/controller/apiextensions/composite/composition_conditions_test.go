@@ -0,0 +1,233 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+func TestConditionMet(t *testing.T) {
+	xr := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"replicas": int64(3),
+			"tier":     "premium",
+			"readReplica": map[string]any{
+				"enabled": true,
+			},
+		},
+	}}}
+	e := &env.Environment{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"region": "us-east-1",
+	}}}
+
+	type args struct {
+		c   *v1.Condition
+		xr  resource.Composite
+		env *env.Environment
+	}
+	type want struct {
+		met bool
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NilCondition": {
+			reason: "A nil condition should always be met.",
+			args: args{
+				xr: xr,
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"Exists": {
+			reason: "An Exists condition should be met if the field exists.",
+			args: args{
+				c:  &v1.Condition{FieldPath: "spec.readReplica.enabled", Operator: v1.ConditionOperatorExists},
+				xr: xr,
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"ExistsNotFound": {
+			reason: "An Exists condition should not be met if the field does not exist.",
+			args: args{
+				c:  &v1.Condition{FieldPath: "spec.kmsKey", Operator: v1.ConditionOperatorExists},
+				xr: xr,
+			},
+			want: want{
+				met: false,
+			},
+		},
+		"DoesNotExist": {
+			reason: "A DoesNotExist condition should be met if the field does not exist.",
+			args: args{
+				c:  &v1.Condition{FieldPath: "spec.kmsKey", Operator: v1.ConditionOperatorDoesNotExist},
+				xr: xr,
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"EqualsBool": {
+			reason: "An Equals condition should be met if the field is equal to the value.",
+			args: args{
+				c:  &v1.Condition{FieldPath: "spec.readReplica.enabled", Operator: v1.ConditionOperatorEquals, Value: &extv1.JSON{Raw: []byte(`true`)}},
+				xr: xr,
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"EqualsNumber": {
+			reason: "Integer fields should be equal to JSON numbers of the same value.",
+			args: args{
+				c:  &v1.Condition{FieldPath: "spec.replicas", Operator: v1.ConditionOperatorEquals, Value: &extv1.JSON{Raw: []byte(`3.0`)}},
+				xr: xr,
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"EqualsNotFound": {
+			reason: "An Equals condition should not be met if the field does not exist.",
+			args: args{
+				c:  &v1.Condition{FieldPath: "spec.kmsKey", Operator: v1.ConditionOperatorEquals, Value: &extv1.JSON{Raw: []byte(`null`)}},
+				xr: xr,
+			},
+			want: want{
+				met: false,
+			},
+		},
+		"NotEquals": {
+			reason: "A NotEquals condition should be met if the field is not equal to the value.",
+			args: args{
+				c:  &v1.Condition{FieldPath: "spec.tier", Operator: v1.ConditionOperatorNotEquals, Value: &extv1.JSON{Raw: []byte(`"basic"`)}},
+				xr: xr,
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"NotEqualsNotFound": {
+			reason: "A NotEquals condition should be met if the field does not exist.",
+			args: args{
+				c:  &v1.Condition{FieldPath: "spec.kmsKey", Operator: v1.ConditionOperatorNotEquals, Value: &extv1.JSON{Raw: []byte(`"basic"`)}},
+				xr: xr,
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"In": {
+			reason: "An In condition should be met if the field is equal to any of the values.",
+			args: args{
+				c: &v1.Condition{FieldPath: "spec.tier", Operator: v1.ConditionOperatorIn, Values: []extv1.JSON{
+					{Raw: []byte(`"premium"`)},
+					{Raw: []byte(`"enterprise"`)},
+				}},
+				xr: xr,
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"NotIn": {
+			reason: "A NotIn condition should not be met if the field is equal to any of the values.",
+			args: args{
+				c: &v1.Condition{FieldPath: "spec.tier", Operator: v1.ConditionOperatorNotIn, Values: []extv1.JSON{
+					{Raw: []byte(`"premium"`)},
+					{Raw: []byte(`"enterprise"`)},
+				}},
+				xr: xr,
+			},
+			want: want{
+				met: false,
+			},
+		},
+		"Environment": {
+			reason: "A condition with an Environment source should be evaluated against the environment.",
+			args: args{
+				c:   &v1.Condition{Source: v1.ConditionSourceEnvironment, FieldPath: "region", Operator: v1.ConditionOperatorEquals, Value: &extv1.JSON{Raw: []byte(`"us-east-1"`)}},
+				xr:  xr,
+				env: e,
+			},
+			want: want{
+				met: true,
+			},
+		},
+		"NilEnvironment": {
+			reason: "A nil environment should be treated as empty.",
+			args: args{
+				c:  &v1.Condition{Source: v1.ConditionSourceEnvironment, FieldPath: "region", Operator: v1.ConditionOperatorExists},
+				xr: xr,
+			},
+			want: want{
+				met: false,
+			},
+		},
+		"InvalidValue": {
+			reason: "We should return an error if a value is not valid JSON.",
+			args: args{
+				c:  &v1.Condition{FieldPath: "spec.tier", Operator: v1.ConditionOperatorEquals, Value: &extv1.JSON{Raw: []byte(`premium`)}},
+				xr: xr,
+			},
+			want: want{
+				err: errors.Wrap(errors.New("invalid character 'p' looking for beginning of value"), errConditionValue),
+			},
+		},
+		"UnknownOperator": {
+			reason: "We should return an error if the operator is unknown.",
+			args: args{
+				c:  &v1.Condition{FieldPath: "spec.tier", Operator: "Matches"},
+				xr: xr,
+			},
+			want: want{
+				err: errors.Errorf(errFmtConditionOperatorNotSupported, "Matches"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			met, err := ConditionMet(tc.args.c, tc.args.xr, tc.args.env)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConditionMet(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.met, met); diff != "" {
+				t.Errorf("\n%s\nConditionMet(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
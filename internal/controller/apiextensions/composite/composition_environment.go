@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+// Error strings.
+const (
+	errSetEnvironmentStatus = "cannot set environment in composite resource status"
+)
+
+// AnnotationKeyDebugEnvironment opts a composite resource in to having its
+// merged environment, and the source of each of its top-level keys, recorded
+// in its status. It has no effect unless the composite resource's composition
+// allows debugging its environment.
+const AnnotationKeyDebugEnvironment = "crossplane.io/debug-environment"
+
+// FieldPathEnvironment is the field path at which an XR's merged environment
+// is recorded when AnnotationKeyDebugEnvironment is set.
+const FieldPathEnvironment = "status.environment"
+
+// SetEnvironmentStatus records a debug view of the supplied environment in the
+// status of the supplied XR, if the supplied environment configuration allows
+// it and the XR's AnnotationKeyDebugEnvironment annotation is "true". Any
+// previously recorded environment is removed otherwise. Values read from
+// Secrets are redacted.
+func SetEnvironmentStatus(xr resource.Composite, cfg *v1.EnvironmentConfiguration, e *env.Environment) error {
+	u, ok := xr.(interface{ UnstructuredContent() map[string]any })
+	if !ok {
+		// Only unstructured XRs can have arbitrary status fields.
+		return nil
+	}
+	p := fieldpath.Pave(u.UnstructuredContent())
+
+	d := e.Debug()
+	if !cfg.IsDebugAllowed() || xr.GetAnnotations()[AnnotationKeyDebugEnvironment] != "true" || d == nil {
+		return errors.Wrap(p.DeleteField(FieldPathEnvironment), errSetEnvironmentStatus)
+	}
+	return errors.Wrap(p.SetValue(FieldPathEnvironment, d), errSetEnvironmentStatus)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+func TestSetEnvironmentStatus(t *testing.T) {
+	e, err := env.NewEnvironment(v1alpha1.EnvironmentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-config"},
+		Data:       map[string]extv1.JSON{"region": {Raw: []byte(`"us-east-1"`)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xr := func(debug bool, status map[string]any) *composite.Unstructured {
+		xr := composite.New()
+		if debug {
+			xr.SetAnnotations(map[string]string{AnnotationKeyDebugEnvironment: "true"})
+		}
+		if status != nil {
+			xr.Object["status"] = status
+		}
+		return xr
+	}
+
+	allowed := &v1.EnvironmentConfiguration{AllowDebug: pointer.Bool(true)}
+
+	type args struct {
+		xr  resource.Composite
+		cfg *v1.EnvironmentConfiguration
+		env *env.Environment
+	}
+	type want struct {
+		xr  resource.Composite
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotUnstructured": {
+			reason: "We should not modify an XR that isn't unstructured.",
+			args: args{
+				xr:  &fake.Composite{},
+				cfg: allowed,
+				env: e,
+			},
+			want: want{
+				xr: &fake.Composite{},
+			},
+		},
+		"NotDebugging": {
+			reason: "We should not record the environment of an XR that hasn't opted in.",
+			args: args{
+				xr:  xr(false, nil),
+				cfg: allowed,
+				env: e,
+			},
+			want: want{
+				xr: xr(false, nil),
+			},
+		},
+		"StoppedDebugging": {
+			reason: "We should remove a previously recorded environment from an XR that has opted out.",
+			args: args{
+				xr:  xr(false, map[string]any{"environment": map[string]any{}, "cool": "status"}),
+				cfg: allowed,
+				env: e,
+			},
+			want: want{
+				xr: xr(false, map[string]any{"cool": "status"}),
+			},
+		},
+		"NotAllowed": {
+			reason: "We should not record the environment of an XR that has opted in if its composition doesn't allow it.",
+			args: args{
+				xr:  xr(true, map[string]any{"environment": map[string]any{}}),
+				cfg: &v1.EnvironmentConfiguration{},
+				env: e,
+			},
+			want: want{
+				xr: xr(true, map[string]any{}),
+			},
+		},
+		"Debugging": {
+			reason: "We should record the merged environment and its sources in the status of an XR that has opted in.",
+			args: args{
+				xr:  xr(true, nil),
+				cfg: allowed,
+				env: e,
+			},
+			want: want{
+				xr: xr(true, map[string]any{
+					"environment": map[string]any{
+						"data": map[string]any{"region": "us-east-1"},
+						"sources": map[string]any{
+							"region": []any{
+								map[string]any{
+									"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+									"kind":       v1alpha1.EnvironmentConfigKind,
+									"name":       "cool-config",
+								},
+							},
+						},
+					},
+				}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := SetEnvironmentStatus(tc.args.xr, tc.args.cfg, tc.args.env)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetEnvironmentStatus(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.xr, tc.args.xr); diff != "" {
+				t.Errorf("\n%s\nSetEnvironmentStatus(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
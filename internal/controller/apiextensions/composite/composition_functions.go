@@ -0,0 +1,400 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
+	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1alpha1 "github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+)
+
+// Error strings.
+const (
+	errMarshalProtoStruct    = "cannot marshal protobuf Struct to JSON"
+	errUnmarshalProtoStruct  = "cannot unmarshal JSON to protobuf Struct"
+	errUnmarshalJSON         = "cannot unmarshal JSON to object"
+	errMarshalRequest        = "cannot marshal RunFunctionRequest"
+	errBuildDesiredState     = "cannot build desired state"
+	errNoDesiredXR           = "function pipeline did not return a desired composite resource"
+	errUnmarshalDesiredState = "cannot unmarshal desired composite resource from function pipeline state"
+
+	errFmtGetFunction          = "cannot get Function %q"
+	errFmtNoFunctionEndpoint   = "Function %q has no endpoint - is it installed and healthy?"
+	errFmtNoStaticEndpoint     = "no endpoint was specified for Function %q"
+	errFmtDialFunction         = "cannot dial Function %q"
+	errFmtRunFunction          = "cannot run Function %q"
+	errFmtCloseFunction        = "cannot close connection to Function %q"
+	errFmtTagMismatch          = "Function %q returned a response with tag %q, but the request had tag %q"
+	errFmtRunPipelineStep      = "cannot run pipeline step %q"
+	errFmtPipelineStepResult   = "pipeline step %q"
+	errFmtParseDesiredCDState  = "cannot parse desired composed resource %q from function pipeline state"
+	errFmtConvertComposedState = "cannot convert composed resource %q to protobuf Struct"
+	errFmtParseStepInput       = "cannot parse input of pipeline step %q"
+)
+
+// A FunctionRunner runs a single Composition Function using the v1beta1
+// RunFunction protocol.
+type FunctionRunner interface {
+	// RunFunction runs the named Function.
+	RunFunction(ctx context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error)
+}
+
+// A FunctionRunnerFn runs a single Composition Function using the v1beta1
+// RunFunction protocol.
+type FunctionRunnerFn func(ctx context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error)
+
+// RunFunction runs the named Function.
+func (fn FunctionRunnerFn) RunFunction(ctx context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+	return fn(ctx, name, req)
+}
+
+// A PackagedFunctionRunner runs Composition Functions that were installed as
+// Function packages. It sends RunFunctionRequests to the gRPC endpoint the
+// package manager recorded in each Function's status.
+type PackagedFunctionRunner struct {
+	client client.Reader
+}
+
+// NewPackagedFunctionRunner returns a FunctionRunner that runs Composition
+// Functions that were installed as Function packages.
+func NewPackagedFunctionRunner(c client.Reader) *PackagedFunctionRunner {
+	return &PackagedFunctionRunner{client: c}
+}
+
+// RunFunction sends the supplied RunFunctionRequest to the named Function.
+func (r *PackagedFunctionRunner) RunFunction(ctx context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+	fn := &pkgv1alpha1.Function{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name}, fn); err != nil {
+		return nil, errors.Wrapf(err, errFmtGetFunction, name)
+	}
+
+	if fn.GetEndpoint() == "" {
+		return nil, errors.Errorf(errFmtNoFunctionEndpoint, name)
+	}
+
+	// TODO(negz): Authenticate Crossplane to the Function (and vice versa)
+	// using mTLS rather than relying on network policy.
+	return runFunctionAt(ctx, name, fn.GetEndpoint(), req)
+}
+
+// A StaticFunctionRunner runs Composition Functions that are served at a fixed
+// set of gRPC endpoints, keyed by Function name. It's useful for running
+// Functions outside of a Crossplane control plane, for example while they're
+// being developed.
+type StaticFunctionRunner struct {
+	endpoints map[string]string
+}
+
+// NewStaticFunctionRunner returns a FunctionRunner that runs Composition
+// Functions served at the supplied gRPC endpoints, keyed by Function name.
+func NewStaticFunctionRunner(endpoints map[string]string) *StaticFunctionRunner {
+	return &StaticFunctionRunner{endpoints: endpoints}
+}
+
+// RunFunction sends the supplied RunFunctionRequest to the named Function.
+func (r *StaticFunctionRunner) RunFunction(ctx context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+	endpoint, ok := r.endpoints[name]
+	if !ok || endpoint == "" {
+		return nil, errors.Errorf(errFmtNoStaticEndpoint, name)
+	}
+	return runFunctionAt(ctx, name, endpoint, req)
+}
+
+func runFunctionAt(ctx context.Context, name, endpoint string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+	conn, err := grpc.DialContext(ctx, endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtDialFunction, name)
+	}
+	// Remember to close the connection, we are not deferring it to be able to
+	// properly handle errors, without having to use a named return.
+
+	rsp, err := fnv1beta1.NewFunctionRunnerServiceClient(conn).RunFunction(ctx, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrapf(err, errFmtRunFunction, name)
+	}
+
+	if err := conn.Close(); err != nil {
+		return nil, errors.Wrapf(err, errFmtCloseFunction, name)
+	}
+
+	// A Function must echo the tag of the request it's responding to.
+	if rsp.GetMeta().GetTag() != req.GetMeta().GetTag() {
+		return nil, errors.Errorf(errFmtTagMismatch, name, rsp.GetMeta().GetTag(), req.GetMeta().GetTag())
+	}
+
+	return rsp, nil
+}
+
+// Tag returns an opaque string identifying the content of the supplied
+// RunFunctionRequest. Two identical requests have the same tag. Any existing
+// request metadata is ignored when computing the tag.
+func Tag(req *fnv1beta1.RunFunctionRequest) (string, error) {
+	r := proto.Clone(req).(*fnv1beta1.RunFunctionRequest) //nolint:forcetypeassert // Clone always returns the type it was passed.
+	r.Meta = nil
+
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(r)
+	if err != nil {
+		return "", errors.Wrap(err, errMarshalRequest)
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// AsStruct converts the supplied object to a protobuf Struct by way of its JSON
+// representation.
+func AsStruct(o runtime.Object) (*structpb.Struct, error) {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalJSON)
+	}
+	s := &structpb.Struct{}
+	return s, errors.Wrap(protojson.Unmarshal(b, s), errUnmarshalProtoStruct)
+}
+
+// FromStruct populates the supplied object with the content of the supplied
+// protobuf Struct by way of its JSON representation.
+func FromStruct(o runtime.Object, s *structpb.Struct) error {
+	b, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return errors.Wrap(err, errMarshalProtoStruct)
+	}
+	return errors.Wrap(json.Unmarshal(b, o), errUnmarshalJSON)
+}
+
+// FunctionStateObserved builds observed state for a RunFunctionRequest from
+// the XR and any existing composed resources. This reflects the observed state
+// of the world before any Composition (P&T or function-based) has taken place.
+func FunctionStateObserved(s *PTFCompositionState) (*fnv1beta1.State, error) {
+	xr, err := AsStruct(s.Composite)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalXR)
+	}
+
+	oxr := &fnv1beta1.Resource{Resource: xr, ConnectionDetails: s.ConnectionDetails}
+
+	ocds := make(map[string]*fnv1beta1.Resource, len(s.ComposedResources))
+	for _, cd := range s.ComposedResources {
+		r, err := AsStruct(cd.Resource)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtConvertComposedState, cd.ResourceName)
+		}
+		ocds[cd.ResourceName] = &fnv1beta1.Resource{Resource: r, ConnectionDetails: cd.ConnectionDetails}
+	}
+
+	return &fnv1beta1.State{Composite: oxr, Resources: ocds}, nil
+}
+
+// FunctionStateDesired builds the initial desired state for a
+// RunFunctionRequest from the XR and any existing or impending composed
+// resources. This reflects the observed state of the world plus the initial
+// desired state as built up by any P&T Composition that has taken place.
+func FunctionStateDesired(s *PTFCompositionState) (*fnv1beta1.State, error) {
+	xr, err := AsStruct(s.Composite)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalXR)
+	}
+
+	dxr := &fnv1beta1.Resource{Resource: xr, ConnectionDetails: s.ConnectionDetails}
+
+	dcds := make(map[string]*fnv1beta1.Resource, len(s.ComposedResources))
+	for _, cd := range s.ComposedResources {
+		if cd.Template == nil {
+			// This composed resource isn't associated with a template. It must
+			// be an existing resource that isn't desired by P&T Composition.
+			continue
+		}
+		r, err := AsStruct(cd.Resource)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtConvertComposedState, cd.ResourceName)
+		}
+		dcds[cd.ResourceName] = &fnv1beta1.Resource{Resource: r}
+	}
+
+	return &fnv1beta1.State{Composite: dxr, Resources: dcds}, nil
+}
+
+// A FunctionStep is a step in a pipeline of Composition Functions that are run
+// using the v1beta1 RunFunction protocol.
+type FunctionStep struct {
+	// Step is the name of this step in the pipeline.
+	Step string
+
+	// Function is the name of the Function package this step runs.
+	Function string
+
+	// Input is optional, Function specific input for this step.
+	Input *structpb.Struct
+}
+
+// FunctionSteps converts the supplied Composition pipeline to FunctionSteps.
+func FunctionSteps(pipeline []v1.PipelineStep) ([]FunctionStep, error) {
+	out := make([]FunctionStep, len(pipeline))
+	for i, ps := range pipeline {
+		out[i] = FunctionStep{Step: ps.Step, Function: ps.FunctionRef.Name}
+		if ps.Input == nil {
+			continue
+		}
+		in := &structpb.Struct{}
+		if err := protojson.Unmarshal(ps.Input.Raw, in); err != nil {
+			return nil, errors.Wrapf(errors.Wrap(err, errUnmarshalProtoStruct), errFmtParseStepInput, ps.Step)
+		}
+		out[i].Input = in
+	}
+	return out, nil
+}
+
+// A PackagedFunctionPipeline runs a pipeline of Composition Functions using the
+// v1beta1 RunFunction protocol.
+type PackagedFunctionPipeline struct {
+	runner FunctionRunner
+}
+
+// NewPackagedFunctionPipeline returns a PackagedFunctionPipeline that runs
+// functions using the supplied FunctionRunner.
+func NewPackagedFunctionPipeline(r FunctionRunner) *PackagedFunctionPipeline {
+	return &PackagedFunctionPipeline{runner: r}
+}
+
+// severity returns the supplied result severity as a string.
+func severity(s fnv1beta1.Severity) string {
+	switch s {
+	case fnv1beta1.Severity_SEVERITY_FATAL:
+		return severityFatal
+	case fnv1beta1.Severity_SEVERITY_WARNING:
+		return severityWarning
+	case fnv1beta1.Severity_SEVERITY_NORMAL:
+		return severityNormal
+	case fnv1beta1.Severity_SEVERITY_UNSPECIFIED:
+	}
+	return ""
+}
+
+// RunFunctionSteps runs the supplied pipeline steps in order. Each step is
+// passed the supplied observed state, which should represent the state before
+// any Composition took place, and the desired state returned by the previous
+// step. The initial desired state is derived from the supplied composition
+// state, which is updated to reflect the pipeline's final desired state.
+func (p *PackagedFunctionPipeline) RunFunctionSteps(ctx context.Context, s *PTFCompositionState, o *fnv1beta1.State, steps ...FunctionStep) error { //nolint:gocyclo // Only slightly over.
+	d, err := FunctionStateDesired(s)
+	if err != nil {
+		return errors.Wrap(err, errBuildDesiredState)
+	}
+
+	for _, step := range steps {
+		req := &fnv1beta1.RunFunctionRequest{Observed: o, Desired: d, Input: step.Input}
+		tag, err := Tag(req)
+		if err != nil {
+			return errors.Wrapf(err, errFmtRunPipelineStep, step.Step)
+		}
+		req.Meta = &fnv1beta1.RequestMeta{Tag: tag}
+
+		sr := FunctionStepResult{Step: step.Step, Function: step.Function}
+		start := time.Now()
+		rsp, err := p.runner.RunFunction(ctx, step.Function, req)
+		sr.Duration = metav1.Duration{Duration: time.Since(start)}
+		if err != nil {
+			sr.Message = err.Error()
+			s.FunctionResults = append(s.FunctionResults, sr)
+			return errors.Wrapf(err, errFmtRunPipelineStep, step.Step)
+		}
+
+		// We require each function to pass through any desired state from
+		// previous functions in the pipeline that they're unconcerned with,
+		// as well as their own desired state. We pass all functions the same
+		// observed state, since it should represent the state before the
+		// function pipeline started.
+		d = rsp.GetDesired()
+		for _, rs := range rsp.GetResults() {
+			sr.count(severity(rs.GetSeverity()), rs.GetMessage())
+		}
+		s.FunctionResults = append(s.FunctionResults, sr)
+
+		// Results of fatal severity stop the Composition process, so we don't
+		// run any subsequent steps. Normal or warning results are accumulated
+		// to be emitted as events by the Reconciler.
+		for _, rs := range rsp.GetResults() {
+			msg := errors.Wrapf(errors.New(rs.GetMessage()), errFmtPipelineStepResult, step.Step)
+			switch rs.GetSeverity() {
+			case fnv1beta1.Severity_SEVERITY_FATAL:
+				return errors.Wrap(msg, errFatalResult)
+			case fnv1beta1.Severity_SEVERITY_WARNING:
+				s.Events = append(s.Events, event.Warning(reasonCompose, msg))
+			case fnv1beta1.Severity_SEVERITY_NORMAL:
+				s.Events = append(s.Events, event.Normal(reasonCompose, msg.Error()))
+			case fnv1beta1.Severity_SEVERITY_UNSPECIFIED:
+				// We don't know what to do with this result; ignore it.
+			}
+		}
+	}
+
+	if d.GetComposite().GetResource() == nil {
+		return errors.New(errNoDesiredXR)
+	}
+
+	u := &kunstructured.Unstructured{}
+	if err := FromStruct(u, d.GetComposite().GetResource()); err != nil {
+		return errors.Wrap(err, errUnmarshalDesiredState)
+	}
+	s.Composite = &composite.Unstructured{Unstructured: *u}
+
+	s.ConnectionDetails = managed.ConnectionDetails{}
+	for k, v := range d.GetComposite().GetConnectionDetails() {
+		s.ConnectionDetails[k] = v
+	}
+
+	for name, dr := range d.GetResources() {
+		// We use encoding/json rather than protojson here because the latter
+		// deliberately produces unstable output.
+		raw, err := json.Marshal(dr.GetResource().AsMap())
+		if err != nil {
+			return errors.Wrapf(errors.Wrap(err, errMarshalProtoStruct), errFmtParseDesiredCDState, name)
+		}
+
+		cd, err := ParseDesiredResource(iov1alpha1.DesiredResource{Name: name, Resource: runtime.RawExtension{Raw: raw}}, s.Composite)
+		if err != nil {
+			return errors.Wrapf(err, errFmtParseDesiredCDState, name)
+		}
+
+		s.ComposedResources.Merge(cd)
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+)
+
+// Error strings.
+const (
+	errTagRequest = "cannot compute RunFunctionRequest tag"
+)
+
+// FunctionCacheMetrics records Composition Function response cache metrics.
+type FunctionCacheMetrics interface {
+	// Hit records that a response from the named Function was served from
+	// the cache.
+	Hit(name string)
+
+	// Miss records that the named Function had to be run because no
+	// unexpired response was cached.
+	Miss(name string)
+}
+
+// NopFunctionCacheMetrics does not record any metrics.
+type NopFunctionCacheMetrics struct{}
+
+// Hit does nothing.
+func (m NopFunctionCacheMetrics) Hit(_ string) {}
+
+// Miss does nothing.
+func (m NopFunctionCacheMetrics) Miss(_ string) {}
+
+// PrometheusFunctionCacheMetrics records Composition Function response cache
+// metrics as Prometheus counters, labelled by Function name.
+type PrometheusFunctionCacheMetrics struct {
+	hits   *prometheus.CounterVec
+	misses *prometheus.CounterVec
+}
+
+// NewPrometheusFunctionCacheMetrics returns FunctionCacheMetrics that may be
+// registered with a Prometheus registry.
+func NewPrometheusFunctionCacheMetrics() *PrometheusFunctionCacheMetrics {
+	return &PrometheusFunctionCacheMetrics{
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "function_cache_hits_total",
+			Help:      "Total number of Composition Function responses served from the cache.",
+		}, []string{"function"}),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "function_cache_misses_total",
+			Help:      "Total number of Composition Function runs that could not be served from the cache.",
+		}, []string{"function"}),
+	}
+}
+
+// Hit records a cache hit.
+func (m *PrometheusFunctionCacheMetrics) Hit(name string) {
+	m.hits.WithLabelValues(name).Inc()
+}
+
+// Miss records a cache miss.
+func (m *PrometheusFunctionCacheMetrics) Miss(name string) {
+	m.misses.WithLabelValues(name).Inc()
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel.
+func (m *PrometheusFunctionCacheMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.hits.Describe(ch)
+	m.misses.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (m *PrometheusFunctionCacheMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.hits.Collect(ch)
+	m.misses.Collect(ch)
+}
+
+// A CachingFunctionRunner wraps a FunctionRunner, caching the responses of
+// Functions that specify a TTL. Responses are cached in memory, keyed by the
+// Function name and the content hash (i.e. tag) of the request, until their
+// TTL expires. Functions that don't specify a TTL are always run.
+type CachingFunctionRunner struct {
+	wrapped FunctionRunner
+	metrics FunctionCacheMetrics
+	maxSize int
+	now     func() time.Time
+
+	mx      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type cachedResponse struct {
+	key     string
+	rsp     *fnv1beta1.RunFunctionResponse
+	expires time.Time
+}
+
+// A CachingFunctionRunnerOption configures a CachingFunctionRunner.
+type CachingFunctionRunnerOption func(r *CachingFunctionRunner)
+
+// WithMaxCacheSize bounds the number of responses a CachingFunctionRunner will
+// cache. The least recently used response is evicted when the cache is full.
+// The cache is unbounded if the size is zero or less.
+func WithMaxCacheSize(n int) CachingFunctionRunnerOption {
+	return func(r *CachingFunctionRunner) {
+		r.maxSize = n
+	}
+}
+
+// WithFunctionCacheMetrics configures how a CachingFunctionRunner records
+// cache hit and miss metrics.
+func WithFunctionCacheMetrics(m FunctionCacheMetrics) CachingFunctionRunnerOption {
+	return func(r *CachingFunctionRunner) {
+		r.metrics = m
+	}
+}
+
+// NewCachingFunctionRunner returns a FunctionRunner that caches the responses
+// of the supplied FunctionRunner.
+func NewCachingFunctionRunner(wrapped FunctionRunner, o ...CachingFunctionRunnerOption) *CachingFunctionRunner {
+	r := &CachingFunctionRunner{
+		wrapped: wrapped,
+		metrics: NopFunctionCacheMetrics{},
+		now:     time.Now,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+
+	for _, fn := range o {
+		fn(r)
+	}
+
+	return r
+}
+
+// RunFunction returns a cached response to the supplied request if one exists
+// and has not expired. Otherwise it runs the named Function, caching its
+// response if the Function specified a TTL.
+func (r *CachingFunctionRunner) RunFunction(ctx context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+	tag := req.GetMeta().GetTag()
+	if tag == "" {
+		t, err := Tag(req)
+		if err != nil {
+			return nil, errors.Wrap(err, errTagRequest)
+		}
+		tag = t
+	}
+	key := name + "/" + tag
+
+	if rsp, ok := r.get(key); ok {
+		r.metrics.Hit(name)
+		return rsp, nil
+	}
+	r.metrics.Miss(name)
+
+	rsp, err := r.wrapped.RunFunction(ctx, name, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl := rsp.GetMeta().GetTtl(); ttl != nil && ttl.AsDuration() > 0 {
+		r.put(key, rsp, r.now().Add(ttl.AsDuration()))
+	}
+
+	return rsp, nil
+}
+
+func (r *CachingFunctionRunner) get(key string) (*fnv1beta1.RunFunctionResponse, bool) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	e, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	c := e.Value.(*cachedResponse) //nolint:forcetypeassert // We only ever store *cachedResponse.
+	if !r.now().Before(c.expires) {
+		r.lru.Remove(e)
+		delete(r.entries, key)
+		return nil, false
+	}
+
+	r.lru.MoveToFront(e)
+
+	// Return a copy so callers can't mutate our cached response.
+	return proto.Clone(c.rsp).(*fnv1beta1.RunFunctionResponse), true //nolint:forcetypeassert // Clone always returns the type it was passed.
+}
+
+func (r *CachingFunctionRunner) put(key string, rsp *fnv1beta1.RunFunctionResponse, expires time.Time) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	c := &cachedResponse{key: key, rsp: proto.Clone(rsp).(*fnv1beta1.RunFunctionResponse), expires: expires} //nolint:forcetypeassert // Clone always returns the type it was passed.
+
+	if e, ok := r.entries[key]; ok {
+		e.Value = c
+		r.lru.MoveToFront(e)
+		return
+	}
+
+	r.entries[key] = r.lru.PushFront(c)
+
+	for r.maxSize > 0 && r.lru.Len() > r.maxSize {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.entries, oldest.Value.(*cachedResponse).key) //nolint:forcetypeassert // We only ever store *cachedResponse.
+	}
+}
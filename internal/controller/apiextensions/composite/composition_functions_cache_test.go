@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+)
+
+type MockFunctionCacheMetrics struct {
+	hits   int
+	misses int
+}
+
+func (m *MockFunctionCacheMetrics) Hit(_ string)  { m.hits++ }
+func (m *MockFunctionCacheMetrics) Miss(_ string) { m.misses++ }
+
+func TestCachingFunctionRunner(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+
+	rsp := func(ttl time.Duration) *fnv1beta1.RunFunctionResponse {
+		r := &fnv1beta1.RunFunctionResponse{
+			Results: []*fnv1beta1.Result{{Severity: fnv1beta1.Severity_SEVERITY_NORMAL, Message: "good stuff"}},
+		}
+		if ttl > 0 {
+			r.Meta = &fnv1beta1.ResponseMeta{Ttl: durationpb.New(ttl)}
+		}
+		return r
+	}
+
+	type call struct {
+		name string
+		req  *fnv1beta1.RunFunctionRequest
+		// How far after now this call happens.
+		after time.Duration
+	}
+
+	type params struct {
+		maxSize int
+		rsp     *fnv1beta1.RunFunctionResponse
+		err     error
+	}
+
+	type want struct {
+		rsp    *fnv1beta1.RunFunctionResponse
+		err    error
+		runs   int
+		hits   int
+		misses int
+	}
+
+	cases := map[string]struct {
+		reason string
+		params params
+		calls  []call
+		want   want
+	}{
+		"RunFunctionError": {
+			reason: "We should return any error encountered running the Function, and not cache anything.",
+			params: params{err: errBoom},
+			calls: []call{
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}},
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}},
+			},
+			want: want{
+				err:    errBoom,
+				runs:   2,
+				misses: 2,
+			},
+		},
+		"NoTTL": {
+			reason: "We should not cache responses that don't specify a TTL.",
+			params: params{rsp: rsp(0)},
+			calls: []call{
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}},
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}},
+			},
+			want: want{
+				rsp:    rsp(0),
+				runs:   2,
+				misses: 2,
+			},
+		},
+		"CacheHit": {
+			reason: "We should serve identical requests from the cache until their TTL expires.",
+			params: params{rsp: rsp(time.Minute)},
+			calls: []call{
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}},
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}, after: 30 * time.Second},
+			},
+			want: want{
+				rsp:    rsp(time.Minute),
+				runs:   1,
+				hits:   1,
+				misses: 1,
+			},
+		},
+		"UntaggedCacheHit": {
+			reason: "We should derive a tag for requests that don't have one.",
+			params: params{rsp: rsp(time.Minute)},
+			calls: []call{
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{}},
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{}},
+			},
+			want: want{
+				rsp:    rsp(time.Minute),
+				runs:   1,
+				hits:   1,
+				misses: 1,
+			},
+		},
+		"DifferentFunction": {
+			reason: "We should not serve a response cached for one Function to another.",
+			params: params{rsp: rsp(time.Minute)},
+			calls: []call{
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}},
+				{name: "other-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}},
+			},
+			want: want{
+				rsp:    rsp(time.Minute),
+				runs:   2,
+				misses: 2,
+			},
+		},
+		"Expired": {
+			reason: "We should run the Function again once a cached response's TTL has expired.",
+			params: params{rsp: rsp(time.Minute)},
+			calls: []call{
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}},
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}, after: 2 * time.Minute},
+			},
+			want: want{
+				rsp:    rsp(time.Minute),
+				runs:   2,
+				misses: 2,
+			},
+		},
+		"Evicted": {
+			reason: "We should evict the least recently used response when the cache is full.",
+			params: params{rsp: rsp(time.Minute), maxSize: 1},
+			calls: []call{
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}},
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "b"}}},
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "b"}}},
+				{name: "cool-fn", req: &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "a"}}},
+			},
+			want: want{
+				rsp:    rsp(time.Minute),
+				runs:   3,
+				hits:   1,
+				misses: 3,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			runs := 0
+			wrapped := FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+				runs++
+				return tc.params.rsp, tc.params.err
+			})
+
+			m := &MockFunctionCacheMetrics{}
+			r := NewCachingFunctionRunner(wrapped, WithMaxCacheSize(tc.params.maxSize), WithFunctionCacheMetrics(m))
+
+			var rsp *fnv1beta1.RunFunctionResponse
+			var err error
+			for _, c := range tc.calls {
+				r.now = func() time.Time { return now.Add(c.after) }
+				rsp, err = r.RunFunction(context.Background(), c.name, c.req)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rsp, rsp, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.runs, runs); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want runs, +got runs:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.hits, m.hits); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want hits, +got hits:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.misses, m.misses); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want misses, +got misses:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
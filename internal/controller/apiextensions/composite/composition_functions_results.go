@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errSetFunctionResults = "cannot set function results in composite resource status"
+)
+
+// FieldPathFunctionResults is the field path at which the results of each
+// step of a Composition Function pipeline are recorded in an XR's status.
+const FieldPathFunctionResults = "status.functionResults"
+
+// Severities of Composition Function results, as used to label metrics.
+const (
+	severityNormal  = "Normal"
+	severityWarning = "Warning"
+	severityFatal   = "Fatal"
+)
+
+// A FunctionStepResult records the outcome of running one step of a
+// Composition Function pipeline.
+type FunctionStepResult struct {
+	// Step is the name of the pipeline step.
+	Step string `json:"step"`
+
+	// Function is the Function the step ran.
+	Function string `json:"function"`
+
+	// Duration is how long the step took to run.
+	Duration metav1.Duration `json:"duration"`
+
+	// Normal is the number of results of normal severity the step returned.
+	Normal int `json:"normal"`
+
+	// Warning is the number of results of warning severity the step
+	// returned.
+	Warning int `json:"warning"`
+
+	// Fatal is the number of results of fatal severity the step returned.
+	Fatal int `json:"fatal"`
+
+	// Message is the message of the last result the step returned, or the
+	// error encountered if the step could not be run.
+	Message string `json:"message,omitempty"`
+}
+
+// count the supplied result severity, recording its message as the most recent
+// message returned by this step.
+func (r *FunctionStepResult) count(severity, message string) {
+	switch severity {
+	case severityNormal:
+		r.Normal++
+	case severityWarning:
+		r.Warning++
+	case severityFatal:
+		r.Fatal++
+	default:
+		// We don't know what to do with this result; ignore it.
+		return
+	}
+	r.Message = message
+}
+
+// SetFunctionResults records the supplied Composition Function pipeline step
+// results in the status of the supplied XR. Any previously recorded results
+// are removed if no results are supplied.
+func SetFunctionResults(xr resource.Composite, results []FunctionStepResult) error {
+	u, ok := xr.(interface{ UnstructuredContent() map[string]any })
+	if !ok {
+		// Only unstructured XRs can have arbitrary status fields.
+		return nil
+	}
+	p := fieldpath.Pave(u.UnstructuredContent())
+	if len(results) == 0 {
+		return errors.Wrap(p.DeleteField(FieldPathFunctionResults), errSetFunctionResults)
+	}
+	return errors.Wrap(p.SetValue(FieldPathFunctionResults, results), errSetFunctionResults)
+}
+
+// FunctionPipelineMetrics records Composition Function pipeline metrics.
+type FunctionPipelineMetrics interface {
+	// Observe records the outcome of a Composition Function pipeline step.
+	Observe(r FunctionStepResult)
+}
+
+// NopFunctionPipelineMetrics does not record any metrics.
+type NopFunctionPipelineMetrics struct{}
+
+// Observe does nothing.
+func (m NopFunctionPipelineMetrics) Observe(_ FunctionStepResult) {}
+
+// PrometheusFunctionPipelineMetrics records Composition Function pipeline
+// metrics as Prometheus histograms and counters, labelled by Function name.
+type PrometheusFunctionPipelineMetrics struct {
+	duration *prometheus.HistogramVec
+	results  *prometheus.CounterVec
+}
+
+// NewPrometheusFunctionPipelineMetrics returns FunctionPipelineMetrics that
+// may be registered with a Prometheus registry.
+func NewPrometheusFunctionPipelineMetrics() *PrometheusFunctionPipelineMetrics {
+	return &PrometheusFunctionPipelineMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "composition",
+			Name:      "function_run_duration_seconds",
+			Help:      "Histogram of how long Composition Function pipeline steps took to run, in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, []string{"function"}),
+		results: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "function_results_total",
+			Help:      "Total number of results returned by Composition Function pipeline steps, by severity.",
+		}, []string{"function", "severity"}),
+	}
+}
+
+// Observe records the outcome of a Composition Function pipeline step.
+func (m *PrometheusFunctionPipelineMetrics) Observe(r FunctionStepResult) {
+	m.duration.WithLabelValues(r.Function).Observe(r.Duration.Seconds())
+	m.results.WithLabelValues(r.Function, severityNormal).Add(float64(r.Normal))
+	m.results.WithLabelValues(r.Function, severityWarning).Add(float64(r.Warning))
+	m.results.WithLabelValues(r.Function, severityFatal).Add(float64(r.Fatal))
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel.
+func (m *PrometheusFunctionPipelineMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.duration.Describe(ch)
+	m.results.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (m *PrometheusFunctionPipelineMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.duration.Collect(ch)
+	m.results.Collect(ch)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestSetFunctionResults(t *testing.T) {
+	type args struct {
+		xr      resource.Composite
+		results []FunctionStepResult
+	}
+	type want struct {
+		xr  resource.Composite
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotUnstructured": {
+			reason: "We should not modify an XR that isn't unstructured.",
+			args: args{
+				xr:      &fake.Composite{},
+				results: []FunctionStepResult{{Step: "cool-step"}},
+			},
+			want: want{
+				xr: &fake.Composite{},
+			},
+		},
+		"ClearResults": {
+			reason: "We should remove previously recorded results from the XR's status when there are no results.",
+			args: args{
+				xr: func() resource.Composite {
+					xr := composite.New()
+					xr.Object["status"] = map[string]any{
+						"functionResults": []any{map[string]any{"step": "cool-step"}},
+					}
+					return xr
+				}(),
+			},
+			want: want{
+				xr: func() resource.Composite {
+					xr := composite.New()
+					xr.Object["status"] = map[string]any{}
+					return xr
+				}(),
+			},
+		},
+		"Success": {
+			reason: "We should record the supplied results in the XR's status.",
+			args: args{
+				xr: composite.New(),
+				results: []FunctionStepResult{
+					{
+						Step:     "cool-step",
+						Function: "cool-fn",
+						Duration: metav1.Duration{Duration: 1500 * time.Millisecond},
+						Normal:   1,
+						Warning:  2,
+						Message:  "oh no",
+					},
+				},
+			},
+			want: want{
+				xr: func() resource.Composite {
+					xr := composite.New()
+					xr.Object["status"] = map[string]any{
+						"functionResults": []any{
+							map[string]any{
+								"step":     "cool-step",
+								"function": "cool-fn",
+								"duration": "1.5s",
+								"normal":   int64(1),
+								"warning":  int64(2),
+								"fatal":    int64(0),
+								"message":  "oh no",
+							},
+						},
+					}
+					return xr
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := SetFunctionResults(tc.args.xr, tc.args.results)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetFunctionResults(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.xr, tc.args.xr); diff != "" {
+				t.Errorf("\n%s\nSetFunctionResults(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,780 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
+	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1alpha1 "github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+)
+
+type MockFunctionRunnerServer struct {
+	fnv1beta1.UnimplementedFunctionRunnerServiceServer
+
+	rsp *fnv1beta1.RunFunctionResponse
+	err error
+}
+
+func (s *MockFunctionRunnerServer) RunFunction(_ context.Context, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+	if s.rsp != nil && s.rsp.Meta == nil {
+		// Echo the request's tag, like a well behaved Function would.
+		s.rsp.Meta = &fnv1beta1.ResponseMeta{Tag: req.GetMeta().GetTag()}
+	}
+	return s.rsp, s.err
+}
+
+func TestPackagedFunctionRunner(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type params struct {
+		server fnv1beta1.FunctionRunnerServiceServer
+		c      client.Reader
+	}
+
+	type args struct {
+		ctx  context.Context
+		name string
+		req  *fnv1beta1.RunFunctionRequest
+	}
+
+	type want struct {
+		rsp *fnv1beta1.RunFunctionResponse
+		err error
+	}
+
+	// A client that returns a Function whose endpoint is the address of our
+	// mock server. The endpoint is injected below.
+	withEndpoint := func(endpoint *string) client.Reader {
+		return &test.MockClient{
+			MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*pkgv1alpha1.Function).SetEndpoint(*endpoint)
+				return nil
+			}),
+		}
+	}
+
+	var endpoint string
+
+	cases := map[string]struct {
+		reason string
+		params params
+		args   args
+		want   want
+	}{
+		"GetFunctionError": {
+			reason: "We should return an error if we can't get the Function.",
+			params: params{
+				server: &MockFunctionRunnerServer{},
+				c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1beta1.RunFunctionRequest{},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetFunction, "cool-fn"),
+			},
+		},
+		"NoEndpointError": {
+			reason: "We should return an error if the Function has no endpoint.",
+			params: params{
+				server: &MockFunctionRunnerServer{},
+				c:      &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1beta1.RunFunctionRequest{},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoFunctionEndpoint, "cool-fn"),
+			},
+		},
+		"RunFunctionError": {
+			reason: "We should return an error if we can't make an RPC call to run the function.",
+			params: params{
+				server: &MockFunctionRunnerServer{err: errBoom},
+				c:      withEndpoint(&endpoint),
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1beta1.RunFunctionRequest{},
+			},
+			want: want{
+				err: errors.Wrapf(status.Error(codes.Unknown, errBoom.Error()), errFmtRunFunction, "cool-fn"),
+			},
+		},
+		"TagMismatchError": {
+			reason: "We should return an error if the Function doesn't echo the request's tag.",
+			params: params{
+				server: &MockFunctionRunnerServer{
+					rsp: &fnv1beta1.RunFunctionResponse{Meta: &fnv1beta1.ResponseMeta{Tag: "wat"}},
+				},
+				c: withEndpoint(&endpoint),
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "cool-tag"}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtTagMismatch, "cool-fn", "wat", "cool-tag"),
+			},
+		},
+		"RunFunctionSuccess": {
+			reason: "We should return the same RunFunctionResponse our server returned.",
+			params: params{
+				server: &MockFunctionRunnerServer{
+					rsp: &fnv1beta1.RunFunctionResponse{
+						Results: []*fnv1beta1.Result{{Severity: fnv1beta1.Severity_SEVERITY_NORMAL, Message: "good stuff"}},
+					},
+				},
+				c: withEndpoint(&endpoint),
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "cool-tag"}},
+			},
+			want: want{
+				rsp: &fnv1beta1.RunFunctionResponse{
+					Meta:    &fnv1beta1.ResponseMeta{Tag: "cool-tag"},
+					Results: []*fnv1beta1.Result{{Severity: fnv1beta1.Severity_SEVERITY_NORMAL, Message: "good stuff"}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+
+			// Listen on a random port.
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wg := &sync.WaitGroup{}
+			wg.Add(1)
+			go func() {
+				s := grpc.NewServer()
+				fnv1beta1.RegisterFunctionRunnerServiceServer(s, tc.params.server)
+				_ = s.Serve(lis)
+				wg.Done()
+			}()
+
+			// Tell the Function to connect to our mock server.
+			endpoint = lis.Addr().String()
+
+			r := NewPackagedFunctionRunner(tc.params.c)
+			rsp, err := r.RunFunction(tc.args.ctx, tc.args.name, tc.args.req)
+
+			_ = lis.Close() // This should terminate the goroutine above.
+			wg.Wait()
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.rsp, rsp, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+		})
+	}
+}
+
+func TestStaticFunctionRunner(t *testing.T) {
+	type args struct {
+		ctx  context.Context
+		name string
+		req  *fnv1beta1.RunFunctionRequest
+	}
+
+	type want struct {
+		rsp *fnv1beta1.RunFunctionResponse
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		server fnv1beta1.FunctionRunnerServiceServer
+		args   args
+		want   want
+	}{
+		"NoEndpointError": {
+			reason: "We should return an error if no endpoint was specified for the Function.",
+			server: &MockFunctionRunnerServer{},
+			args: args{
+				ctx:  context.Background(),
+				name: "other-fn",
+				req:  &fnv1beta1.RunFunctionRequest{},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoStaticEndpoint, "other-fn"),
+			},
+		},
+		"RunFunctionSuccess": {
+			reason: "We should return the same RunFunctionResponse our server returned.",
+			server: &MockFunctionRunnerServer{
+				rsp: &fnv1beta1.RunFunctionResponse{
+					Results: []*fnv1beta1.Result{{Severity: fnv1beta1.Severity_SEVERITY_NORMAL, Message: "good stuff"}},
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1beta1.RunFunctionRequest{Meta: &fnv1beta1.RequestMeta{Tag: "cool-tag"}},
+			},
+			want: want{
+				rsp: &fnv1beta1.RunFunctionResponse{
+					Meta:    &fnv1beta1.ResponseMeta{Tag: "cool-tag"},
+					Results: []*fnv1beta1.Result{{Severity: fnv1beta1.Severity_SEVERITY_NORMAL, Message: "good stuff"}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wg := &sync.WaitGroup{}
+			wg.Add(1)
+			go func() {
+				s := grpc.NewServer()
+				fnv1beta1.RegisterFunctionRunnerServiceServer(s, tc.server)
+				_ = s.Serve(lis)
+				wg.Done()
+			}()
+
+			r := NewStaticFunctionRunner(map[string]string{"cool-fn": lis.Addr().String()})
+			rsp, err := r.RunFunction(tc.args.ctx, tc.args.name, tc.args.req)
+
+			_ = lis.Close() // This should terminate the goroutine above.
+			wg.Wait()
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.rsp, rsp, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nRunFunction(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTag(t *testing.T) {
+	input, _ := structpb.NewStruct(map[string]any{"cool": "input"})
+	a := &fnv1beta1.RunFunctionRequest{Input: input}
+	b := &fnv1beta1.RunFunctionRequest{Input: input, Meta: &fnv1beta1.RequestMeta{Tag: "ignored"}}
+	c := &fnv1beta1.RunFunctionRequest{}
+
+	ta, err := Tag(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb, err := Tag(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, err := Tag(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ta != tb {
+		t.Errorf("Tag(...): requests that differ only in metadata should have the same tag: %q != %q", ta, tb)
+	}
+	if ta == tc {
+		t.Errorf("Tag(...): requests with different content should have different tags: %q == %q", ta, tc)
+	}
+	if b.GetMeta().GetTag() != "ignored" {
+		t.Errorf("Tag(...): should not mutate the supplied request")
+	}
+}
+
+func TestFunctionStateObserved(t *testing.T) {
+	xr := func() *composite.Unstructured {
+		xr := composite.New()
+		xr.SetAPIVersion("example.org/v1")
+		xr.SetKind("XR")
+		xr.SetName("cool-xr")
+		return xr
+	}
+	cd := func() *composed.Unstructured {
+		cd := composed.New()
+		cd.SetAPIVersion("example.org/v1")
+		cd.SetKind("Composed")
+		cd.SetName("cool-cd")
+		return cd
+	}
+
+	type args struct {
+		s *PTFCompositionState
+	}
+	type want struct {
+		o   *fnv1beta1.State
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "We should include the XR and all existing composed resources in observed state.",
+			args: args{
+				s: &PTFCompositionState{
+					Composite:         xr(),
+					ConnectionDetails: managed.ConnectionDetails{"a": []byte("b")},
+					ComposedResources: ComposedResourceStates{
+						"cool-resource": ComposedResourceState{
+							ComposedResource:  ComposedResource{ResourceName: "cool-resource"},
+							Resource:          cd(),
+							ConnectionDetails: managed.ConnectionDetails{"c": []byte("d")},
+						},
+					},
+				},
+			},
+			want: want{
+				o: &fnv1beta1.State{
+					Composite: &fnv1beta1.Resource{
+						Resource: MustStruct(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "XR",
+							"metadata":   map[string]any{"name": "cool-xr"},
+						}),
+						ConnectionDetails: map[string][]byte{"a": []byte("b")},
+					},
+					Resources: map[string]*fnv1beta1.Resource{
+						"cool-resource": {
+							Resource: MustStruct(map[string]any{
+								"apiVersion": "example.org/v1",
+								"kind":       "Composed",
+								"metadata":   map[string]any{"name": "cool-cd"},
+							}),
+							ConnectionDetails: map[string][]byte{"c": []byte("d")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := FunctionStateObserved(tc.args.s)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFunctionStateObserved(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.o, o, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nFunctionStateObserved(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFunctionStateDesired(t *testing.T) {
+	cd := func() *composed.Unstructured {
+		cd := composed.New()
+		cd.SetAPIVersion("example.org/v1")
+		cd.SetKind("Composed")
+		return cd
+	}
+
+	type args struct {
+		s *PTFCompositionState
+	}
+	type want struct {
+		d   *fnv1beta1.State
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "We should only include composed resources that are associated with a P&T template in desired state.",
+			args: args{
+				s: &PTFCompositionState{
+					Composite: composite.New(),
+					ComposedResources: ComposedResourceStates{
+						"desired-resource": ComposedResourceState{
+							ComposedResource:  ComposedResource{ResourceName: "desired-resource"},
+							Resource:          cd(),
+							Template:          &v1.ComposedTemplate{},
+							ConnectionDetails: managed.ConnectionDetails{"c": []byte("d")},
+						},
+						"existing-resource": ComposedResourceState{
+							ComposedResource: ComposedResource{ResourceName: "existing-resource"},
+							Resource:         cd(),
+						},
+					},
+				},
+			},
+			want: want{
+				d: &fnv1beta1.State{
+					Composite: &fnv1beta1.Resource{
+						Resource: MustStruct(map[string]any{}),
+					},
+					Resources: map[string]*fnv1beta1.Resource{
+						"desired-resource": {
+							Resource: MustStruct(map[string]any{
+								"apiVersion": "example.org/v1",
+								"kind":       "Composed",
+							}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := FunctionStateDesired(tc.args.s)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFunctionStateDesired(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.d, d, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nFunctionStateDesired(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFunctionSteps(t *testing.T) {
+	type args struct {
+		pipeline []v1.PipelineStep
+	}
+	type want struct {
+		steps []FunctionStep
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"InvalidInput": {
+			reason: "We should return an error if a step's input isn't a JSON object.",
+			args: args{
+				pipeline: []v1.PipelineStep{
+					{
+						Step:        "cool-step",
+						FunctionRef: v1.FunctionReference{Name: "cool-fn"},
+						Input:       &runtime.RawExtension{Raw: []byte(`["not","an","object"]`)},
+					},
+				},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"Success": {
+			reason: "We should convert each pipeline step, including its input.",
+			args: args{
+				pipeline: []v1.PipelineStep{
+					{
+						Step:        "cool-step",
+						FunctionRef: v1.FunctionReference{Name: "cool-fn"},
+						Input:       &runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Input","cool":true}`)},
+					},
+					{
+						Step:        "other-step",
+						FunctionRef: v1.FunctionReference{Name: "other-fn"},
+					},
+				},
+			},
+			want: want{
+				steps: []FunctionStep{
+					{
+						Step:     "cool-step",
+						Function: "cool-fn",
+						Input: MustStruct(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Input",
+							"cool":       true,
+						}),
+					},
+					{
+						Step:     "other-step",
+						Function: "other-fn",
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			steps, err := FunctionSteps(tc.args.pipeline)
+
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFunctionSteps(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.steps, steps, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nFunctionSteps(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRunFunctionSteps(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	desiredXR := MustStruct(map[string]any{"apiVersion": "a/v1", "kind": "XR"})
+	desiredCD := MustStruct(map[string]any{"apiVersion": "a/v1", "kind": "Composed"})
+
+	type params struct {
+		r FunctionRunner
+	}
+
+	type args struct {
+		ctx   context.Context
+		s     *PTFCompositionState
+		o     *fnv1beta1.State
+		steps []FunctionStep
+	}
+
+	type want struct {
+		s   *PTFCompositionState
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		params params
+		args   args
+		want   want
+	}{
+		"RunFunctionError": {
+			reason: "We should return an error if we can't run a pipeline step.",
+			params: params{
+				r: FunctionRunnerFn(func(ctx context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+					return nil, errBoom
+				}),
+			},
+			args: args{
+				s:     &PTFCompositionState{Composite: composite.New()},
+				steps: []FunctionStep{{Step: "cool-step", Function: "cool-fn"}},
+			},
+			want: want{
+				s: &PTFCompositionState{
+					Composite: composite.New(),
+					FunctionResults: []FunctionStepResult{
+						{Step: "cool-step", Function: "cool-fn", Message: errBoom.Error()},
+					},
+				},
+				err: errors.Wrapf(errBoom, errFmtRunPipelineStep, "cool-step"),
+			},
+		},
+		"FatalResult": {
+			reason: "We should return an error, and not run subsequent steps, if a pipeline step returns a fatal result.",
+			params: params{
+				r: FunctionRunnerFn(func(ctx context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+					if name != "fatal-fn" {
+						return nil, errors.New("steps after a fatal result should not run")
+					}
+					rsp := &fnv1beta1.RunFunctionResponse{Desired: req.GetDesired()}
+					rsp.Results = []*fnv1beta1.Result{{Severity: fnv1beta1.Severity_SEVERITY_FATAL, Message: "oh no"}}
+					return rsp, nil
+				}),
+			},
+			args: args{
+				s: &PTFCompositionState{Composite: composite.New()},
+				steps: []FunctionStep{
+					{Step: "fatal-step", Function: "fatal-fn"},
+					{Step: "cool-step", Function: "cool-fn"},
+				},
+			},
+			want: want{
+				s: &PTFCompositionState{
+					Composite: composite.New(),
+					FunctionResults: []FunctionStepResult{
+						{Step: "fatal-step", Function: "fatal-fn", Fatal: 1, Message: "oh no"},
+					},
+				},
+				err: errors.Wrap(errors.Wrapf(errors.New("oh no"), errFmtPipelineStepResult, "fatal-step"), errFatalResult),
+			},
+		},
+		"NoDesiredXR": {
+			reason: "We should return an error if the pipeline doesn't return a desired XR.",
+			params: params{
+				r: FunctionRunnerFn(func(ctx context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+					return &fnv1beta1.RunFunctionResponse{}, nil
+				}),
+			},
+			args: args{
+				s:     &PTFCompositionState{Composite: composite.New()},
+				steps: []FunctionStep{{Step: "cool-step", Function: "cool-fn"}},
+			},
+			want: want{
+				s: &PTFCompositionState{
+					Composite: composite.New(),
+					FunctionResults: []FunctionStepResult{
+						{Step: "cool-step", Function: "cool-fn"},
+					},
+				},
+				err: errors.New(errNoDesiredXR),
+			},
+		},
+		"Success": {
+			reason: "We should update our state to reflect the pipeline's final desired state, and its results.",
+			params: params{
+				r: FunctionRunnerFn(func(ctx context.Context, name string, req *fnv1beta1.RunFunctionRequest) (*fnv1beta1.RunFunctionResponse, error) {
+					if req.GetMeta().GetTag() == "" {
+						return nil, errors.New("request should be tagged")
+					}
+					switch name {
+					case "first-fn":
+						if req.GetInput().GetFields()["cool"].GetStringValue() != "input" {
+							return nil, errors.New("first step should be passed its input")
+						}
+						return &fnv1beta1.RunFunctionResponse{
+							Desired: &fnv1beta1.State{
+								Composite: &fnv1beta1.Resource{Resource: desiredXR},
+							},
+							Results: []*fnv1beta1.Result{{Severity: fnv1beta1.Severity_SEVERITY_WARNING, Message: "oh no"}},
+						}, nil
+					case "second-fn":
+						if req.GetDesired().GetComposite().GetResource() == nil {
+							return nil, errors.New("second step should be passed the desired state returned by the first")
+						}
+						d := req.GetDesired()
+						d.Composite.ConnectionDetails = map[string][]byte{"a": []byte("b")}
+						d.Resources = map[string]*fnv1beta1.Resource{"cool-resource": {Resource: desiredCD}}
+						return &fnv1beta1.RunFunctionResponse{
+							Desired: d,
+							Results: []*fnv1beta1.Result{{Severity: fnv1beta1.Severity_SEVERITY_NORMAL, Message: "good stuff"}},
+						}, nil
+					}
+					return nil, errors.Errorf("unexpected function %q", name)
+				}),
+			},
+			args: args{
+				s: &PTFCompositionState{
+					Composite:         composite.New(),
+					ComposedResources: ComposedResourceStates{},
+				},
+				steps: []FunctionStep{
+					{Step: "first-step", Function: "first-fn", Input: MustStruct(map[string]any{"cool": "input"})},
+					{Step: "second-step", Function: "second-fn"},
+				},
+			},
+			want: want{
+				s: &PTFCompositionState{
+					Composite: func() *composite.Unstructured {
+						xr := composite.New()
+						xr.SetAPIVersion("a/v1")
+						xr.SetKind("XR")
+						return xr
+					}(),
+					ConnectionDetails: managed.ConnectionDetails{"a": []byte("b")},
+					ComposedResources: ComposedResourceStates{
+						// We don't need to test that ParseDesiredResource works
+						// here - we do that elsewhere - so just call it.
+						"cool-resource": func() ComposedResourceState {
+							dr := iov1alpha1.DesiredResource{
+								Name: "cool-resource",
+								Resource: runtime.RawExtension{
+									Raw: []byte(`{"apiVersion":"a/v1","kind":"Composed"}`),
+								},
+							}
+							xr := composite.New()
+							xr.SetAPIVersion("a/v1")
+							xr.SetKind("XR")
+							cd, _ := ParseDesiredResource(dr, xr)
+							return cd
+						}(),
+					},
+					Events: []event.Event{
+						event.Warning(reasonCompose, errors.Wrapf(errors.New("oh no"), errFmtPipelineStepResult, "first-step")),
+						event.Normal(reasonCompose, errors.Wrapf(errors.New("good stuff"), errFmtPipelineStepResult, "second-step").Error()),
+					},
+					FunctionResults: []FunctionStepResult{
+						{Step: "first-step", Function: "first-fn", Warning: 1, Message: "oh no"},
+						{Step: "second-step", Function: "second-fn", Normal: 1, Message: "good stuff"},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewPackagedFunctionPipeline(tc.params.r)
+			err := p.RunFunctionSteps(tc.args.ctx, tc.args.s, tc.args.o, tc.args.steps...)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRunFunctionSteps(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			// We don't know how long each step took to run.
+			if diff := cmp.Diff(tc.want.s, tc.args.s, cmpopts.EquateEmpty(), cmpopts.IgnoreFields(FunctionStepResult{}, "Duration")); diff != "" {
+				t.Errorf("\n%s\nRunFunctionSteps(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func MustStruct(v map[string]any) *structpb.Struct {
+	s, err := structpb.NewStruct(v)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/dag"
+)
+
+// Error strings.
+const (
+	errOrder            = "cannot determine the order in which to render composed resources"
+	errSnapshotComposed = "cannot copy composed resource"
+	errResolveComposed  = "cannot patch from composed resources"
+)
+
+// A templateNode is a composed resource template in a graph of the templates
+// it depends on, or patches from.
+type templateNode struct {
+	name string
+	from []string
+}
+
+// Identifier returns the name of a composed resource template.
+func (n *templateNode) Identifier() string {
+	return n.name
+}
+
+// Neighbors returns the templates a composed resource template depends on, or
+// patches from.
+func (n *templateNode) Neighbors() []dag.Node {
+	nodes := make([]dag.Node, len(n.from))
+	for i := range n.from {
+		nodes[i] = &templateNode{name: n.from[i]}
+	}
+	return nodes
+}
+
+// AddNeighbors is a no-op. A template's neighbors are known when its node is
+// created.
+func (n *templateNode) AddNeighbors(_ ...dag.Node) error {
+	return nil
+}
+
+// RenderOrder returns the indices of the supplied composed resource templates
+// in the order they should be rendered. Templates are rendered after any
+// templates they depend on, or patch from using FromComposedFieldPath patches,
+// and otherwise in the order they're supplied. Dependencies on resources that
+// aren't composed by any of the supplied templates are ignored.
+func RenderOrder(cts []v1.ComposedTemplate) ([]int, error) {
+	order := make([]int, len(cts))
+	for i := range order {
+		order[i] = i
+	}
+
+	from := dependencies(cts)
+	if len(from) == 0 {
+		return order, nil
+	}
+
+	// Only named templates may depend on, or be depended on by, others.
+	nodes := make([]dag.Node, 0, len(cts))
+	for _, t := range cts {
+		if t.Name != nil {
+			nodes = append(nodes, &templateNode{name: *t.Name, from: from[*t.Name]})
+		}
+	}
+	g := dag.NewMapDag()
+	if _, err := g.Init(nodes); err != nil {
+		return nil, err
+	}
+
+	// We only use the DAG to detect cycles. Its sort order isn't stable, so we
+	// instead order templates by the length of the longest chain of templates
+	// they depend on.
+	if _, err := g.Sort(); err != nil {
+		return nil, err
+	}
+	depth := make(map[string]int)
+	var depthOf func(name string) int
+	depthOf = func(name string) int {
+		if d, ok := depth[name]; ok {
+			return d
+		}
+		d := 0
+		for _, f := range from[name] {
+			if fd := depthOf(f) + 1; fd > d {
+				d = fd
+			}
+		}
+		depth[name] = d
+		return d
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return depthOf(cts[order[i]].GetName()) < depthOf(cts[order[j]].GetName())
+	})
+	return order, nil
+}
+
+// patchSources returns the names of the composed resources each of the
+// supplied templates patches from, keyed by template name. Only resources
+// that are composed by one of the supplied templates are returned.
+func patchSources(cts []v1.ComposedTemplate) map[string][]string {
+	composes := composedNames(cts)
+	from := make(map[string][]string)
+	for _, t := range cts {
+		for _, p := range t.Patches {
+			if p.GetType() != v1.PatchTypeFromComposedFieldPath || t.Name == nil || !composes[p.GetFromResourceName()] {
+				continue
+			}
+			from[*t.Name] = append(from[*t.Name], p.GetFromResourceName())
+		}
+	}
+	return from
+}
+
+// dependsOn returns the names of the composed resources each of the supplied
+// templates depends on, keyed by template name. Only resources that are
+// composed by one of the supplied templates are returned.
+func dependsOn(cts []v1.ComposedTemplate) map[string][]string {
+	composes := composedNames(cts)
+	deps := make(map[string][]string)
+	for _, t := range cts {
+		for _, d := range t.DependsOn {
+			if t.Name == nil || !composes[d] {
+				continue
+			}
+			deps[*t.Name] = append(deps[*t.Name], d)
+		}
+	}
+	return deps
+}
+
+// dependencies returns the names of the composed resources each of the
+// supplied templates either depends on or patches from, keyed by template
+// name.
+func dependencies(cts []v1.ComposedTemplate) map[string][]string {
+	from := patchSources(cts)
+	for name, deps := range dependsOn(cts) {
+		from[name] = append(from[name], deps...)
+	}
+	return from
+}
+
+// composedNames returns the names of the supplied templates.
+func composedNames(cts []v1.ComposedTemplate) map[string]bool {
+	names := make(map[string]bool)
+	for _, t := range cts {
+		if t.Name != nil {
+			names[*t.Name] = true
+		}
+	}
+	return names
+}
+
+// patchedFrom returns the names of the composed resources any of the supplied
+// templates patch from.
+func patchedFrom(cts []v1.ComposedTemplate) map[string]bool {
+	names := make(map[string]bool)
+	for _, from := range patchSources(cts) {
+		for _, n := range from {
+			names[n] = true
+		}
+	}
+	return names
+}
+
+// dependedOn returns the names of the composed resources any of the supplied
+// templates depend on.
+func dependedOn(cts []v1.ComposedTemplate) map[string]bool {
+	names := make(map[string]bool)
+	for _, deps := range dependsOn(cts) {
+		for _, n := range deps {
+			names[n] = true
+		}
+	}
+	return names
+}
+
+// waitingFor returns the names of the supplied dependencies that aren't ready.
+// A dependency that isn't in the supplied map isn't composed, and is never
+// waited for.
+func waitingFor(deps []string, ready map[string]bool) []string {
+	var waiting []string
+	for _, d := range deps {
+		if r, composed := ready[d]; composed && !r {
+			waiting = append(waiting, d)
+		}
+	}
+	return waiting
+}
+
+// waitingEvent returns an event indicating that the named composed resource
+// won't be created until the resources it's waiting for are ready.
+func waitingEvent(name string, waiting []string) event.Event {
+	return event.Normal(reasonCompose, fmt.Sprintf("Composed resource %q is waiting for %s to become ready", name, strings.Join(waiting, ", ")))
+}
+
+// ResolveComposedPatches returns a copy of the supplied template with its
+// FromComposedFieldPath patches applied to its base. Each patch reads from the
+// named composed resource in the supplied map. A resource that isn't in the map
+// is treated as having no fields.
+func ResolveComposedPatches(t v1.ComposedTemplate, from map[string]resource.Composed, vars TransformVariables) (v1.ComposedTemplate, error) {
+	c, err := applyPatchesToBase(t, v1.PatchTypeFromComposedFieldPath, func(p v1.Patch) runtime.Object {
+		if cd, ok := from[p.GetFromResourceName()]; ok {
+			return cd
+		}
+		return composed.New()
+	}, vars)
+	return c, errors.Wrap(err, errResolveComposed)
+}
+
+// snapshotComposed returns a deep copy of the supplied composed resource.
+func snapshotComposed(cd resource.Composed) (resource.Composed, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cd)
+	if err != nil {
+		return nil, errors.Wrap(err, errSnapshotComposed)
+	}
+	out := composed.New()
+	out.SetUnstructuredContent(runtime.DeepCopyJSON(u))
+	return out, nil
+}
+
+// A DependencyOrderedDeleter deletes the resources composed by a composite
+// resource in reverse dependency order. A composed resource that others depend
+// on isn't deleted until they're gone. Composed resources that don't depend on,
+// and aren't depended on by, others are left to be garbage collected once the
+// composite resource is deleted.
+type DependencyOrderedDeleter struct {
+	client client.Client
+}
+
+// NewDependencyOrderedDeleter returns a ComposedDeleter that deletes composed
+// resources in reverse dependency order.
+func NewDependencyOrderedDeleter(c client.Client) *DependencyOrderedDeleter {
+	return &DependencyOrderedDeleter{client: c}
+}
+
+// DeleteComposed deletes each composed resource that no remaining composed
+// resource depends on. It returns true once all composed resources that
+// depend on, or are depended on by, others are gone.
+func (d *DependencyOrderedDeleter) DeleteComposed(ctx context.Context, xr resource.Composite) (bool, error) { //nolint:gocyclo // Only slightly over (10).
+	ref := xr.GetCompositionRevisionReference()
+	if ref == nil {
+		return true, nil
+	}
+	rev := &v1.CompositionRevision{}
+	if err := d.client.Get(ctx, meta.NamespacedNameOf(ref), rev); err != nil {
+		// We can't know which resources depend on each other without the
+		// revision that composed them.
+		return kerrors.IsNotFound(err), errors.Wrap(resource.IgnoreNotFound(err), errGetCompositionRevision)
+	}
+
+	ct, err := ComposedTemplates(rev.Spec.PatchSets, rev.Spec.Resources)
+	if err != nil {
+		return false, errors.Wrap(err, errInline)
+	}
+	ct, err = ExpandComposedTemplates(xr, nil, ct)
+	if err != nil {
+		return false, errors.Wrap(err, errExpand)
+	}
+	deps := dependsOn(ct)
+	if len(deps) == 0 {
+		return true, nil
+	}
+
+	// Resources that depend on each other could never be deleted in order.
+	// We leave them to be garbage collected.
+	if _, err := RenderOrder(ct); err != nil {
+		return true, nil //nolint:nilerr // A cycle isn't an error here.
+	}
+	depended := dependedOn(ct)
+
+	existing := make(map[string]resource.Composed)
+	for _, ref := range xr.GetResourceReferences() {
+		if ref.Name == "" {
+			continue
+		}
+		cd := composed.New(composed.FromReference(ref))
+		err := d.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cd)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, errors.Wrap(err, errGetComposed)
+		}
+		if c := metav1.GetControllerOf(cd); c == nil || c.UID != xr.GetUID() {
+			continue
+		}
+		existing[GetCompositionResourceName(cd)] = cd
+	}
+
+	// A resource can't be deleted while a resource that depends on it exists.
+	blocked := make(map[string]bool)
+	for name := range existing {
+		for _, dep := range deps[name] {
+			blocked[dep] = true
+		}
+	}
+
+	done := true
+	for name, cd := range existing {
+		if _, dependent := deps[name]; !dependent && !depended[name] {
+			continue
+		}
+		done = false
+		if blocked[name] || meta.WasDeleted(cd) {
+			continue
+		}
+		if err := d.client.Delete(ctx, cd); resource.IgnoreNotFound(err) != nil {
+			return false, errors.Wrapf(err, errFmtDeleteCD, name, cd.GetObjectKind().GroupVersionKind().Kind, cd.GetName())
+		}
+	}
+	return done, nil
+}
@@ -0,0 +1,396 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func fromComposed(name, from, to string) v1.Patch {
+	return v1.Patch{
+		Type:             v1.PatchTypeFromComposedFieldPath,
+		FromResourceName: pointer.String(name),
+		FromFieldPath:    pointer.String(from),
+		ToFieldPath:      pointer.String(to),
+	}
+}
+
+func TestRenderOrder(t *testing.T) {
+	type want struct {
+		order []int
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		cts    []v1.ComposedTemplate
+		want   want
+	}{
+		"Anonymous": {
+			reason: "Anonymous templates should be rendered in order.",
+			cts:    []v1.ComposedTemplate{{}, {}, {}},
+			want: want{
+				order: []int{0, 1, 2},
+			},
+		},
+		"NoComposedPatches": {
+			reason: "Templates that don't patch from composed resources should be rendered in order.",
+			cts: []v1.ComposedTemplate{
+				{Name: pointer.String("a")},
+				{Name: pointer.String("b")},
+			},
+			want: want{
+				order: []int{0, 1},
+			},
+		},
+		"ComposedPatches": {
+			reason: "Templates should be rendered after the templates they patch from, and otherwise in order.",
+			cts: []v1.ComposedTemplate{
+				{Name: pointer.String("db"), Patches: []v1.Patch{fromComposed("subnets", "status.atProvider.id", "spec.forProvider.subnetGroupName")}},
+				{Name: pointer.String("subnets"), Patches: []v1.Patch{fromComposed("vpc", "status.atProvider.id", "spec.forProvider.vpcId")}},
+				{Name: pointer.String("bucket")},
+				{Name: pointer.String("vpc")},
+			},
+			want: want{
+				order: []int{2, 3, 1, 0},
+			},
+		},
+		"UnknownResource": {
+			reason: "Patches from resources that aren't composed should be ignored.",
+			cts: []v1.ComposedTemplate{
+				{Name: pointer.String("db"), Patches: []v1.Patch{fromComposed("nodepool-3", "status.atProvider.id", "spec.forProvider.id")}},
+				{Name: pointer.String("vpc")},
+			},
+			want: want{
+				order: []int{0, 1},
+			},
+		},
+		"DependsOn": {
+			reason: "Templates should be rendered after the templates they depend on.",
+			cts: []v1.ComposedTemplate{
+				{Name: pointer.String("cluster"), DependsOn: []string{"vpc", "nodepool-3"}},
+				{Name: pointer.String("db"), Patches: []v1.Patch{fromComposed("cluster", "status.atProvider.endpoint", "spec.forProvider.clusterEndpoint")}},
+				{Name: pointer.String("vpc")},
+			},
+			want: want{
+				order: []int{2, 0, 1},
+			},
+		},
+		"DependsOnCycle": {
+			reason: "We should return an error if templates depend on each other.",
+			cts: []v1.ComposedTemplate{
+				{Name: pointer.String("a"), DependsOn: []string{"b"}},
+				{Name: pointer.String("b"), Patches: []v1.Patch{fromComposed("a", "spec.a", "spec.b")}},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"Cycle": {
+			reason: "We should return an error if templates patch from each other.",
+			cts: []v1.ComposedTemplate{
+				{Name: pointer.String("a"), Patches: []v1.Patch{fromComposed("b", "spec.b", "spec.a")}},
+				{Name: pointer.String("b"), Patches: []v1.Patch{fromComposed("a", "spec.a", "spec.b")}},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			order, err := RenderOrder(tc.cts)
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRenderOrder(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.order, order); diff != "" {
+				t.Errorf("\n%s\nRenderOrder(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveComposedPatches(t *testing.T) {
+	base := runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Database"}`)}
+	vpc := composed.New()
+	vpc.SetUnstructuredContent(map[string]any{
+		"status": map[string]any{
+			"atProvider": map[string]any{
+				"id": "vpc-1234",
+			},
+		},
+	})
+	fromXR := v1.Patch{Type: v1.PatchTypeFromCompositeFieldPath, FromFieldPath: pointer.String("spec.size")}
+
+	type args struct {
+		t    v1.ComposedTemplate
+		from map[string]resource.Composed
+	}
+	type want struct {
+		t   v1.ComposedTemplate
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoComposedPatches": {
+			reason: "A template without FromComposedFieldPath patches should be returned unchanged.",
+			args: args{
+				t: v1.ComposedTemplate{Name: pointer.String("db"), Base: base, Patches: []v1.Patch{fromXR}},
+			},
+			want: want{
+				t: v1.ComposedTemplate{Name: pointer.String("db"), Base: base, Patches: []v1.Patch{fromXR}},
+			},
+		},
+		"Observed": {
+			reason: "FromComposedFieldPath patches should be applied to the base from the named composed resource.",
+			args: args{
+				t: v1.ComposedTemplate{
+					Name:    pointer.String("db"),
+					Base:    base,
+					Patches: []v1.Patch{fromComposed("vpc", "status.atProvider.id", "spec.forProvider.vpcId"), fromXR},
+				},
+				from: map[string]resource.Composed{"vpc": vpc},
+			},
+			want: want{
+				t: v1.ComposedTemplate{
+					Name:    pointer.String("db"),
+					Base:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Database","spec":{"forProvider":{"vpcId":"vpc-1234"}}}`)},
+					Patches: []v1.Patch{fromXR},
+				},
+			},
+		},
+		"NotComposedOptional": {
+			reason: "Optional FromComposedFieldPath patches from a resource that isn't composed should be skipped.",
+			args: args{
+				t: v1.ComposedTemplate{
+					Name:    pointer.String("db"),
+					Base:    base,
+					Patches: []v1.Patch{fromComposed("vpc", "status.atProvider.id", "spec.forProvider.vpcId")},
+				},
+			},
+			want: want{
+				t: v1.ComposedTemplate{
+					Name:    pointer.String("db"),
+					Base:    base,
+					Patches: []v1.Patch{},
+				},
+			},
+		},
+		"NotComposedRequired": {
+			reason: "We should return an error if a required FromComposedFieldPath patch reads from a resource that isn't composed.",
+			args: args{
+				t: v1.ComposedTemplate{
+					Name: pointer.String("db"),
+					Base: base,
+					Patches: []v1.Patch{{
+						Type:             v1.PatchTypeFromComposedFieldPath,
+						FromResourceName: pointer.String("vpc"),
+						FromFieldPath:    pointer.String("status.atProvider.id"),
+						ToFieldPath:      pointer.String("spec.forProvider.vpcId"),
+						Policy: &v1.PatchPolicy{
+							FromFieldPath: func() *v1.FromFieldPathPolicy {
+								s := v1.FromFieldPathPolicyRequired
+								return &s
+							}(),
+						},
+					}},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrapf(errors.New("status: no such field"), errFmtPatch, 0), errResolveComposed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveComposedPatches(tc.args.t, tc.args.from, TransformVariables{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveComposedPatches(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.t, got); diff != "" {
+				t.Errorf("\n%s\nResolveComposedPatches(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDependencyOrderedDeleter(t *testing.T) {
+	errBoom := errors.New("boom")
+	uid := types.UID("xr-uid")
+
+	rev := &v1.CompositionRevision{
+		Spec: v1.CompositionRevisionSpec{
+			Resources: []v1.ComposedTemplate{
+				{Name: pointer.String("vpc")},
+				{Name: pointer.String("subnet"), DependsOn: []string{"vpc"}},
+				{Name: pointer.String("cluster"), DependsOn: []string{"subnet"}},
+				{Name: pointer.String("bucket")},
+			},
+		},
+	}
+
+	// Each composed resource is named after the template that composed it.
+	get := func(revErr error, gone ...string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1.CompositionRevision:
+				if revErr != nil {
+					return revErr
+				}
+				rev.DeepCopyInto(o)
+			case *composed.Unstructured:
+				for _, name := range gone {
+					if key.Name == name {
+						return kerrors.NewNotFound(schema.GroupResource{}, name)
+					}
+				}
+				o.SetName(key.Name)
+				o.SetOwnerReferences([]metav1.OwnerReference{{UID: uid, Controller: pointer.Bool(true)}})
+				SetCompositionResourceName(o, key.Name)
+			}
+			return nil
+		}
+	}
+
+	xr := func(refs ...string) resource.Composite {
+		xr := NewComposite()
+		xr.SetUID(uid)
+		xr.SetCompositionRevisionReference(&corev1.ObjectReference{Name: "rev"})
+		rr := make([]corev1.ObjectReference, len(refs))
+		for i := range refs {
+			rr[i] = corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Composed", Name: refs[i]}
+		}
+		xr.SetResourceReferences(rr)
+		return xr
+	}
+
+	type args struct {
+		client client.Client
+		xr     resource.Composite
+	}
+	type want struct {
+		deleted bool
+		names   []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoRevision": {
+			reason: "We should leave composed resources to be garbage collected if the XR never selected a revision.",
+			args: args{
+				xr: NewComposite(),
+			},
+			want: want{
+				deleted: true,
+			},
+		},
+		"RevisionNotFound": {
+			reason: "We should leave composed resources to be garbage collected if the XR's revision doesn't exist.",
+			args: args{
+				client: &test.MockClient{MockGet: get(kerrors.NewNotFound(schema.GroupResource{}, "rev"))},
+				xr:     xr("vpc"),
+			},
+			want: want{
+				deleted: true,
+			},
+		},
+		"GetRevisionError": {
+			reason: "We should return any error encountered getting the XR's revision.",
+			args: args{
+				client: &test.MockClient{MockGet: get(errBoom)},
+				xr:     xr("vpc"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCompositionRevision),
+			},
+		},
+		"DeleteDependents": {
+			reason: "We should delete only the composed resources that no remaining composed resource depends on.",
+			args: args{
+				client: &test.MockClient{MockGet: get(nil, "cluster")},
+				xr:     xr("vpc", "subnet", "cluster", "bucket"),
+			},
+			want: want{
+				deleted: false,
+				names:   []string{"subnet"},
+			},
+		},
+		"DependentsDeleted": {
+			reason: "The XR may be deleted once all composed resources that depend on, or are depended on by, others are gone.",
+			args: args{
+				client: &test.MockClient{MockGet: get(nil, "vpc", "subnet", "cluster")},
+				xr:     xr("vpc", "subnet", "cluster", "bucket"),
+			},
+			want: want{
+				deleted: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var names []string
+			if mc, ok := tc.args.client.(*test.MockClient); ok {
+				mc.MockDelete = func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+					names = append(names, obj.GetName())
+					return nil
+				}
+			}
+
+			d := NewDependencyOrderedDeleter(tc.args.client)
+			deleted, err := d.DeleteComposed(context.Background(), tc.args.xr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDeleteComposed(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\nDeleteComposed(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("\n%s\nDeleteComposed(...): -want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package composite
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -26,27 +27,37 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
 )
 
+// wildcard matches every element of an array or object in a field path.
+const wildcard = "[*]"
+
 const (
 	errPatchSetType             = "a patch in a PatchSet cannot be of type PatchSet"
 	errCombineRequiresVariables = "combine patch types require at least one variable"
+	errMarshalBase              = "cannot marshal base template"
 
 	errFmtUndefinedPatchSet           = "cannot find PatchSet by name %s"
 	errFmtInvalidPatchType            = "patch type %s is unsupported"
 	errFmtCombineStrategyNotSupported = "combine strategy %s is not supported"
 	errFmtCombineConfigMissing        = "given combine strategy %s requires configuration"
 	errFmtCombineStrategyFailed       = "%s strategy could not combine"
+	errFmtCombineMathTypeNotSupported = "math combine type %s is not supported"
+	errFmtCombineMathNonNumber        = "variable at index %d is required to be a number, got %T"
 	errFmtExpandingArrayFieldPaths    = "cannot expand ToFieldPath %s"
+	errFmtExpandingFromFieldPath      = "cannot expand FromFieldPath %s"
+	errFmtInvalidWildcards            = "ToFieldPath %s must contain no wildcards, or exactly one wildcard when FromFieldPath %s contains exactly one wildcard"
+	errFmtNotArray                    = "%s is not an array"
+	errFmtTransformElement            = "cannot transform element %s"
 )
 
-// ApplyEnvironmentPatch executes a patching operation between the cp and env objects.
-func ApplyEnvironmentPatch(p v1.EnvironmentPatch, cp, env runtime.Object) error {
-	// TODO(negz): Should this take composite.Resource and *env.Environment as
-	// arguments rather than generic runtime.Objects?
-
+// ApplyEnvironmentPatch executes a patching operation between the cp and e
+// objects.
+func ApplyEnvironmentPatch(p v1.EnvironmentPatch, cp resource.Composite, e *env.Environment) error {
 	regularPatch := p.ToPatch()
 	if regularPatch == nil {
 		// Should never happen, but just in case, nothing to do.
@@ -57,7 +68,8 @@ func ApplyEnvironmentPatch(p v1.EnvironmentPatch, cp, env runtime.Object) error
 	return ApplyToObjects(
 		*regularPatch,
 		cp,
-		env,
+		e,
+		TransformVariables{Composite: cp, Environment: e},
 		v1.PatchTypeFromCompositeFieldPath,
 		v1.PatchTypeCombineFromComposite,
 		v1.PatchTypeToCompositeFieldPath,
@@ -66,29 +78,32 @@ func ApplyEnvironmentPatch(p v1.EnvironmentPatch, cp, env runtime.Object) error
 }
 
 // Apply executes a patching operation between the from and to resources.
-// Applies all patch types unless an 'only' filter is supplied.
+// Applies all patch types unless an 'only' filter is supplied. No environment
+// is made available to transforms; use ApplyToObjects to supply one.
 func Apply(p v1.Patch, cp resource.Composite, cd resource.Composed, only ...v1.PatchType) error {
-	return ApplyToObjects(p, cp, cd, only...)
+	return ApplyToObjects(p, cp, cd, TransformVariables{Composite: cp}, only...)
 }
 
 // ApplyToObjects works like c.Apply but accepts any kind of runtime.Object
 // (such as EnvironmentConfigs).
 // It might be vulnerable to conversion panics
 // (see https://github.com/crossplane/crossplane/pull/3394 for details).
-func ApplyToObjects(p v1.Patch, cp, cd runtime.Object, only ...v1.PatchType) error {
+// The supplied variables are made available to transforms that evaluate
+// expressions.
+func ApplyToObjects(p v1.Patch, cp, cd runtime.Object, vars TransformVariables, only ...v1.PatchType) error {
 	if filterPatch(p, only...) {
 		return nil
 	}
 
 	switch p.GetType() {
 	case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeFromEnvironmentFieldPath:
-		return ApplyFromFieldPathPatch(p, cp, cd)
+		return ApplyFromFieldPathPatch(p, cp, cd, vars)
 	case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeToEnvironmentFieldPath:
-		return ApplyFromFieldPathPatch(p, cd, cp)
+		return ApplyFromFieldPathPatch(p, cd, cp, vars)
 	case v1.PatchTypeCombineFromComposite, v1.PatchTypeCombineFromEnvironment:
-		return ApplyCombineFromVariablesPatch(p, cp, cd)
+		return ApplyCombineFromVariablesPatch(p, cp, cd, vars)
 	case v1.PatchTypeCombineToComposite, v1.PatchTypeCombineToEnvironment:
-		return ApplyCombineFromVariablesPatch(p, cd, cp)
+		return ApplyCombineFromVariablesPatch(p, cd, cp, vars)
 	case v1.PatchTypePatchSet:
 		// Already resolved - nothing to do.
 	}
@@ -103,7 +118,7 @@ func filterPatch(p v1.Patch, only ...v1.PatchType) bool {
 	}
 
 	for _, patchType := range only {
-		if patchType == p.Type {
+		if patchType == p.GetType() {
 			return false
 		}
 	}
@@ -111,10 +126,10 @@ func filterPatch(p v1.Patch, only ...v1.PatchType) bool {
 }
 
 // ResolveTransforms applies a list of transforms to a patch value.
-func ResolveTransforms(c v1.Patch, input any) (any, error) {
+func ResolveTransforms(c v1.Patch, input any, vars TransformVariables) (any, error) {
 	var err error
 	for i, t := range c.Transforms {
-		if input, err = Resolve(t, input); err != nil {
+		if input, err = Resolve(t, input, vars); err != nil {
 			// TODO(negz): Including the type might help find the offending transform faster.
 			return nil, errors.Wrapf(err, errFmtTransformAtIndex, i)
 		}
@@ -152,7 +167,7 @@ func patchFieldValueToMultiple(fieldPath string, value any, to runtime.Object, m
 // ApplyFromFieldPathPatch patches the "to" resource, using a source field
 // on the "from" resource. Values may be transformed if any are defined on
 // the patch.
-func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object) error {
+func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object, vars TransformVariables) error {
 	if p.FromFieldPath == nil {
 		return errors.Errorf(errFmtRequiredField, "FromFieldPath", p.Type)
 	}
@@ -167,6 +182,11 @@ func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object) error {
 		return err
 	}
 
+	// Patch each element individually if the FromFieldPath contains wildcards
+	if strings.Contains(*p.FromFieldPath, wildcard) {
+		return applyForEachPatch(p, fieldpath.Pave(fromMap), to, vars)
+	}
+
 	in, err := fieldpath.Pave(fromMap).GetValue(*p.FromFieldPath)
 	if IsOptionalFieldPathNotFound(err, p.Policy) {
 		return nil
@@ -181,24 +201,119 @@ func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object) error {
 	}
 
 	// Apply transform pipeline
-	out, err := ResolveTransforms(p, in)
+	out, err := ResolveTransforms(p, in, vars)
 	if err != nil {
 		return err
 	}
 
 	// Patch all expanded fields if the ToFieldPath contains wildcards
-	if strings.Contains(*p.ToFieldPath, "[*]") {
+	if strings.Contains(*p.ToFieldPath, wildcard) {
 		return patchFieldValueToMultiple(*p.ToFieldPath, out, to, mo)
 	}
 
 	return patchFieldValueToObject(*p.ToFieldPath, out, to, mo)
 }
 
+// applyForEachPatch patches the "to" resource using each element matched by
+// the wildcards in the patch's FromFieldPath, transforming each element
+// individually. If the ToFieldPath contains no wildcards the transformed
+// elements are patched to it as an array. If both field paths contain exactly
+// one wildcard each transformed element is patched to the element at the same
+// index of the "to" array, which is then truncated to the length of the "from"
+// array.
+func applyForEachPatch(p v1.Patch, from *fieldpath.Paved, to runtime.Object, vars TransformVariables) error {
+	var mo *xpv1.MergeOptions
+	if p.Policy != nil {
+		mo = p.Policy.MergeOptions
+	}
+
+	fromPrefix, fromSuffix, _ := strings.Cut(*p.FromFieldPath, wildcard)
+
+	// The array (or object) containing the first wildcard must exist for the
+	// patch to apply, otherwise we treat the field path as not found.
+	if _, err := from.GetValue(fromPrefix); err != nil {
+		if IsOptionalFieldPathNotFound(err, p.Policy) {
+			return nil
+		}
+		return err
+	}
+
+	switch strings.Count(*p.ToFieldPath, wildcard) {
+	case 0:
+		paths, err := from.ExpandWildcards(*p.FromFieldPath)
+		if err != nil {
+			return errors.Wrapf(err, errFmtExpandingFromFieldPath, *p.FromFieldPath)
+		}
+		out := make([]any, 0, len(paths))
+		for _, fp := range paths {
+			v, err := from.GetValue(fp)
+			if err != nil {
+				return err
+			}
+			o, err := ResolveTransforms(p, v, vars)
+			if err != nil {
+				return errors.Wrapf(err, errFmtTransformElement, fp)
+			}
+			out = append(out, o)
+		}
+		return patchFieldValueToObject(*p.ToFieldPath, out, to, mo)
+	case 1:
+		if strings.Count(*p.FromFieldPath, wildcard) != 1 {
+			break
+		}
+		in, err := from.GetValue(fromPrefix)
+		if err != nil {
+			return err
+		}
+		arr, ok := in.([]any)
+		if !ok {
+			return errors.Errorf(errFmtNotArray, fromPrefix)
+		}
+
+		paved, err := fieldpath.PaveObject(to)
+		if err != nil {
+			return err
+		}
+		toPrefix, toSuffix, _ := strings.Cut(*p.ToFieldPath, wildcard)
+		for i := range arr {
+			fp := fmt.Sprintf("%s[%d]%s", fromPrefix, i, fromSuffix)
+			v, err := from.GetValue(fp)
+			if IsOptionalFieldPathNotFound(err, p.Policy) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			o, err := ResolveTransforms(p, v, vars)
+			if err != nil {
+				return errors.Wrapf(err, errFmtTransformElement, fp)
+			}
+			if err := paved.MergeValue(fmt.Sprintf("%s[%d]%s", toPrefix, i, toSuffix), o, mo); err != nil {
+				return err
+			}
+		}
+
+		// Truncate the "to" array so that elements removed from the "from"
+		// array are removed from it too.
+		if existing, err := paved.GetValue(toPrefix); err == nil {
+			if toArr, ok := existing.([]any); ok && len(toArr) > len(arr) {
+				if err := paved.SetValue(toPrefix, toArr[:len(arr)]); err != nil {
+					return err
+				}
+			}
+		}
+
+		return runtime.DefaultUnstructuredConverter.FromUnstructured(paved.UnstructuredContent(), to)
+	}
+
+	return errors.Errorf(errFmtInvalidWildcards, *p.ToFieldPath, *p.FromFieldPath)
+}
+
 // ApplyCombineFromVariablesPatch patches the "to" resource, taking a list of
 // input variables and combining them into a single output value.
 // The single output value may then be further transformed if they are defined
 // on the patch.
-func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object) error {
+func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object, vars TransformVariables) error {
 	// Combine patch requires configuration
 	if p.Combine == nil {
 		return errors.Errorf(errFmtRequiredField, "Combine", p.Type)
@@ -251,7 +366,7 @@ func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object) error {
 	}
 
 	// Apply transform pipeline
-	out, err := ResolveTransforms(p, cb)
+	out, err := ResolveTransforms(p, cb, vars)
 	if err != nil {
 		return err
 	}
@@ -286,12 +401,15 @@ func Combine(c v1.Combine, vars []any) (any, error) {
 			return nil, errors.Errorf(errFmtCombineConfigMissing, c.Strategy)
 		}
 		out, err = CombineString(c.String.Format, vars)
+	case v1.CombineStrategyMath:
+		if c.Math == nil {
+			return nil, errors.Errorf(errFmtCombineConfigMissing, c.Strategy)
+		}
+		out, err = CombineMath(c.Math.Type, vars)
 	default:
 		return nil, errors.Errorf(errFmtCombineStrategyNotSupported, c.Strategy)
 	}
 
-	// Note: There are currently no tests or triggers to exercise this error as
-	// our only strategy ("String") uses fmt.Sprintf, which cannot return an error.
 	return out, errors.Wrapf(err, errFmtCombineStrategyFailed, string(c.Strategy))
 }
 
@@ -301,6 +419,42 @@ func CombineString(format string, vars []any) (any, error) {
 	return fmt.Sprintf(format, vars...), nil
 }
 
+// CombineMath returns the smallest or largest of its input variables, which
+// must all be numbers. The original type of the returned variable is preserved.
+func CombineMath(t v1.MathCombineType, vars []any) (any, error) {
+	switch t {
+	case v1.MathCombineTypeMin, v1.MathCombineTypeMax:
+	default:
+		return nil, errors.Errorf(errFmtCombineMathTypeNotSupported, t)
+	}
+
+	var out any
+	var outf float64
+	for i, v := range vars {
+		var f float64
+		switch n := v.(type) {
+		case int:
+			f = float64(n)
+		case int64:
+			f = float64(n)
+		case float64:
+			f = n
+		default:
+			return nil, errors.Errorf(errFmtCombineMathNonNumber, i, v)
+		}
+
+		if out == nil {
+			out, outf = v, f
+			continue
+		}
+
+		if (t == v1.MathCombineTypeMin && f < outf) || (t == v1.MathCombineTypeMax && f > outf) {
+			out, outf = v, f
+		}
+	}
+	return out, nil
+}
+
 // ComposedTemplates returns the supplied composed resource templates with any
 // supplied patchsets dereferenced.
 func ComposedTemplates(pss []v1.PatchSet, cts []v1.ComposedTemplate) ([]v1.ComposedTemplate, error) {
@@ -336,3 +490,50 @@ func ComposedTemplates(pss []v1.PatchSet, cts []v1.ComposedTemplate) ([]v1.Compo
 	}
 	return ct, nil
 }
+
+// applyPatchesToBase returns a copy of the supplied template with its patches
+// of the supplied type applied to its base, and removed from its patches. Each
+// patch reads from the object returned by the supplied function. Patches whose
+// condition isn't met are removed without being applied. The template is
+// returned unchanged if it has no patches of the supplied type.
+func applyPatchesToBase(t v1.ComposedTemplate, pt v1.PatchType, from func(p v1.Patch) runtime.Object, vars TransformVariables) (v1.ComposedTemplate, error) {
+	found := false
+	for i := range t.Patches {
+		found = found || t.Patches[i].GetType() == pt
+	}
+	if !found {
+		return t, nil
+	}
+
+	c := *t.DeepCopy()
+	c.Patches = make([]v1.Patch, 0, len(t.Patches))
+
+	cd := composed.New()
+	if err := json.Unmarshal(t.Base.Raw, cd); err != nil {
+		return v1.ComposedTemplate{}, errors.Wrap(err, errUnmarshal)
+	}
+
+	for i, p := range t.Patches {
+		if p.GetType() != pt {
+			c.Patches = append(c.Patches, p)
+			continue
+		}
+		ok, err := ConditionMet(p.Condition, vars.Composite, vars.Environment)
+		if err != nil {
+			return v1.ComposedTemplate{}, errors.Wrapf(err, errFmtPatchCondition, i)
+		}
+		if !ok {
+			continue
+		}
+		if err := ApplyFromFieldPathPatch(p, from(p), cd, vars); err != nil {
+			return v1.ComposedTemplate{}, errors.Wrapf(err, errFmtPatch, i)
+		}
+	}
+
+	raw, err := json.Marshal(cd)
+	if err != nil {
+		return v1.ComposedTemplate{}, errors.Wrap(err, errMarshalBase)
+	}
+	c.Base = runtime.RawExtension{Raw: raw}
+	return c, nil
+}
@@ -235,6 +235,136 @@ func TestPatchApply(t *testing.T) {
 				err: errors.Errorf(errFmtExpandingArrayFieldPaths, "objectMeta.ownerReferences[*].badField"),
 			},
 		},
+		"ForEachCompositeFieldPathPatchToArray": {
+			reason: "When passed a wildcarded FromFieldPath and a ToFieldPath without wildcards, patches each transformed element as an array",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.ownerReferences[*].name"),
+					ToFieldPath:   pointer.String("objectMeta.finalizers"),
+					Transforms: []v1.Transform{{
+						Type: v1.TransformTypeString,
+						String: &v1.StringTransform{
+							Type:   v1.StringTransformTypeFormat,
+							Format: pointer.String("owner-%s"),
+						},
+					}},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "a"},
+							{Name: "b"},
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Finalizers: []string{"old"},
+					},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Finalizers: []string{"owner-a", "owner-b"},
+					},
+				},
+			},
+		},
+		"ForEachCompositeFieldPathPatchElementWise": {
+			reason: "When passed a wildcard in both field paths, patches each transformed element to the same index and truncates the destination array",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.ownerReferences[*].name"),
+					ToFieldPath:   pointer.String("objectMeta.ownerReferences[*].name"),
+					Transforms: []v1.Transform{{
+						Type: v1.TransformTypeString,
+						String: &v1.StringTransform{
+							Type:    v1.StringTransformTypeConvert,
+							Convert: &[]v1.StringConversionType{v1.StringConversionTypeToUpper}[0],
+						},
+					}},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "a"},
+							{Name: "b"},
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "x", APIVersion: "v1"},
+							{Name: "y", APIVersion: "v1"},
+							{Name: "z", APIVersion: "v1"},
+						},
+					},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "A", APIVersion: "v1"},
+							{Name: "B", APIVersion: "v1"},
+						},
+					},
+				},
+			},
+		},
+		"ForEachCompositeFieldPathPatchMissingArray": {
+			reason: "A wildcarded FromFieldPath patch should be a no-op when the array doesn't exist",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.ownerReferences[*].name"),
+					ToFieldPath:   pointer.String("objectMeta.finalizers"),
+				},
+				cp: &fake.Composite{
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Finalizers: []string{"old"},
+					},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Finalizers: []string{"old"},
+					},
+				},
+			},
+		},
+		"ForEachCompositeFieldPathPatchTooManyWildcards": {
+			reason: "Should return an error when the ToFieldPath contains more wildcards than can be matched element-wise",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.ownerReferences[*].name"),
+					ToFieldPath:   pointer.String("objectMeta.ownerReferences[*].a[*]"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "a"},
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{},
+			},
+			want: want{
+				err: errors.Errorf(errFmtInvalidWildcards, "objectMeta.ownerReferences[*].a[*]", "objectMeta.ownerReferences[*].name"),
+			},
+		},
 		"MissingOptionalFieldPath": {
 			reason: "A FromFieldPath patch should be a no-op when an optional fromFieldPath doesn't exist",
 			args: args{
@@ -1168,6 +1298,76 @@ func TestOptionalFieldPathNotFound(t *testing.T) {
 	}
 }
 
+func TestCombineMath(t *testing.T) {
+	type args struct {
+		t    v1.MathCombineType
+		vars []any
+	}
+	type want struct {
+		out any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"Min": {
+			reason: "Should return the smallest variable, preserving its type.",
+			args: args{
+				t:    v1.MathCombineTypeMin,
+				vars: []any{int64(3), 1.5, int64(2)},
+			},
+			want: want{
+				out: 1.5,
+			},
+		},
+		"Max": {
+			reason: "Should return the largest variable, preserving its type.",
+			args: args{
+				t:    v1.MathCombineTypeMax,
+				vars: []any{int64(3), 1.5, int64(2)},
+			},
+			want: want{
+				out: int64(3),
+			},
+		},
+		"NonNumber": {
+			reason: "Should return an error if a variable is not a number.",
+			args: args{
+				t:    v1.MathCombineTypeMax,
+				vars: []any{int64(3), "foo"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtCombineMathNonNumber, 1, "foo"),
+			},
+		},
+		"UnknownType": {
+			reason: "Should return an error if the math combine type is unknown.",
+			args: args{
+				t:    "Average",
+				vars: []any{int64(3)},
+			},
+			want: want{
+				err: errors.Errorf(errFmtCombineMathTypeNotSupported, "Average"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CombineMath(tc.args.t, tc.args.vars)
+			if diff := cmp.Diff(tc.want.out, got); diff != "" {
+				t.Errorf("\n%s\nCombineMath(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCombineMath(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestComposedTemplates(t *testing.T) {
 	asJSON := func(val interface{}) extv1.JSON {
 		raw, err := json.Marshal(val)
@@ -1462,7 +1662,7 @@ func TestResolveTransforms(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ResolveTransforms(v1.Patch{Transforms: tt.args.ts}, tt.args.input)
+			got, err := ResolveTransforms(v1.Patch{Transforms: tt.args.ts}, tt.args.input, TransformVariables{})
 			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("ResolveTransforms(...): -want error, +got error:\n%s", diff)
 			}
@@ -1473,3 +1673,63 @@ func TestResolveTransforms(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterPatch(t *testing.T) {
+	type args struct {
+		p    v1.Patch
+		only []v1.PatchType
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"NoFilter": {
+			reason: "A patch should not be filtered if no patch types are supplied.",
+			args: args{
+				p: v1.Patch{Type: v1.PatchTypeToCompositeFieldPath},
+			},
+			want: false,
+		},
+		"TypeIncluded": {
+			reason: "A patch should not be filtered if its type is supplied.",
+			args: args{
+				p:    v1.Patch{Type: v1.PatchTypeToCompositeFieldPath},
+				only: patchTypesToXR(),
+			},
+			want: false,
+		},
+		"TypeExcluded": {
+			reason: "A patch should be filtered if its type is not supplied.",
+			args: args{
+				p:    v1.Patch{Type: v1.PatchTypeToCompositeFieldPath},
+				only: patchTypesFromXR(),
+			},
+			want: true,
+		},
+		"UntypedIncluded": {
+			reason: "A patch without a type is a FromCompositeFieldPath patch, and should not be filtered if that type is supplied.",
+			args: args{
+				p:    v1.Patch{FromFieldPath: pointer.String("spec.cool")},
+				only: patchTypesFromXR(),
+			},
+			want: false,
+		},
+		"UntypedExcluded": {
+			reason: "A patch without a type is a FromCompositeFieldPath patch, and should be filtered if that type is not supplied.",
+			args: args{
+				p:    v1.Patch{FromFieldPath: pointer.String("spec.cool")},
+				only: patchTypesToXR(),
+			},
+			want: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := filterPatch(tc.args.p, tc.args.only...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nfilterPatch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
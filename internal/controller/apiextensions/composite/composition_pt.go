@@ -176,11 +176,6 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 		return CompositionResult{}, errors.Wrap(err, errInline)
 	}
 
-	tas, err := c.composition.AssociateTemplates(ctx, xr, ct)
-	if err != nil {
-		return CompositionResult{}, errors.Wrap(err, errAssociate)
-	}
-
 	// If we have an environment, run all environment patches before composing
 	// resources.
 	if req.Environment != nil && req.Revision.Spec.Environment != nil {
@@ -191,6 +186,50 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 		}
 	}
 
+	// Replace any templates with a count or forEach with a copy per element.
+	// Each copy has a stable name, so it's associated with the composed
+	// resource it previously produced. Composed resources produced by copies
+	// that no longer exist are garbage collected.
+	ct, err = ExpandComposedTemplates(xr, req.Environment, ct)
+	if err != nil {
+		return CompositionResult{}, errors.Wrap(err, errExpand)
+	}
+
+	tas, err := c.composition.AssociateTemplates(ctx, xr, ct)
+	if err != nil {
+		return CompositionResult{}, errors.Wrap(err, errAssociate)
+	}
+
+	// Resources are rendered after any resources they depend on or patch
+	// from. We observe these resources, and the resources that depend on them,
+	// before rendering anything.
+	associated := make([]v1.ComposedTemplate, len(tas))
+	for i := range tas {
+		associated[i] = tas[i].Template
+	}
+	order, err := RenderOrder(associated)
+	if err != nil {
+		return CompositionResult{}, errors.Wrap(err, errOrder)
+	}
+	sources := patchedFrom(associated)
+	deps := dependsOn(associated)
+	depended := dependedOn(associated)
+	names := make(map[string]bool, len(sources)+len(deps)+len(depended))
+	for name := range sources {
+		names[name] = true
+	}
+	for name := range deps {
+		names[name] = true
+	}
+	for name := range depended {
+		names[name] = true
+	}
+	from, err := c.observe(ctx, tas, names)
+	if err != nil {
+		return CompositionResult{}, err
+	}
+	ready := make(map[string]bool, len(depended))
+
 	events := make([]event.Event, 0)
 
 	// We optimistically render all composed resources that we are able to with
@@ -199,25 +238,70 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 	// input. Errors are recorded, but not considered fatal to the composition
 	// process.
 	refs := make([]corev1.ObjectReference, len(tas))
-	cds := make([]ComposedResourceState, len(tas))
-	for i := range tas {
+	cds := make([]ComposedResourceState, 0, len(tas))
+	for _, i := range order {
 		ta := tas[i]
 
 		// If this resource is anonymous its "name" is just its index.
 		name := pointer.StringDeref(ta.Template.Name, strconv.Itoa(i))
+
+		// We don't compose resources whose template's condition isn't met,
+		// and garbage collect any we composed while it was. We still record
+		// a reference without a name so that anonymous templates remain
+		// associated with their references by index.
+		ok, err := ConditionMet(ta.Template.Condition, xr, req.Environment)
+		if err != nil {
+			return CompositionResult{}, errors.Wrapf(err, errFmtCondition, name)
+		}
+		if !ok {
+			if err := deleteComposed(ctx, c.client, xr, ta.Reference); err != nil {
+				return CompositionResult{}, err
+			}
+			refs[i] = placeholderReference(ta.Template)
+			continue
+		}
+
+		// Resources that others depend on are only ready if they exist.
+		cd, exists := from[name]
+		if depended[name] {
+			ready[name] = false
+			if exists {
+				if ready[name], err = c.composed.IsReady(ctx, cd, ReadinessChecksFromComposedTemplate(&ta.Template)...); err != nil {
+					return CompositionResult{}, errors.Wrap(err, errReadiness)
+				}
+			}
+		}
+
+		// We don't create resources until the resources they depend on are
+		// ready. We record a reference without a name until we do.
+		if waiting := waitingFor(deps[name], ready); !exists && len(waiting) > 0 {
+			events = append(events, waitingEvent(name, waiting))
+			refs[i] = placeholderReference(ta.Template)
+			continue
+		}
+
 		r := composed.New(composed.FromReference(ta.Reference))
 
-		rerr := c.composed.Render(ctx, xr, r, ta.Template, req.Environment)
+		t, rerr := ResolveComposedPatches(ta.Template, from, TransformVariables{Composite: xr, Environment: req.Environment})
+		if rerr == nil {
+			rerr = c.composed.Render(ctx, xr, r, t, req.Environment)
+		}
 		if rerr != nil {
 			events = append(events, event.Warning(reasonCompose, errors.Wrapf(rerr, errFmtResourceName, name)))
 		}
 
-		cds[i] = ComposedResourceState{
+		// Resources that patch from this resource are rendered after it. They
+		// patch from its rendered state if it doesn't exist yet.
+		if sources[name] && !exists && rerr == nil {
+			from[name] = r
+		}
+
+		cds = append(cds, ComposedResourceState{
 			ComposedResource:  ComposedResource{ResourceName: name},
 			TemplateRenderErr: rerr,
 			Template:          &ta.Template,
 			Resource:          r,
-		}
+		})
 		refs[i] = *meta.ReferenceTo(r, r.GetObjectKind().GroupVersionKind())
 	}
 
@@ -263,18 +347,35 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 			return CompositionResult{}, errors.Wrap(err, errFetchDetails)
 		}
 
-		e, err := c.composed.ExtractConnection(cds[i].Resource, cds[i].ConnectionDetails, ExtractConfigsFromTemplate(cds[i].Template)...)
+		cds[i].Ready, err = c.composed.IsReady(ctx, cds[i].Resource, ReadinessChecksFromComposedTemplate(cds[i].Template)...)
 		if err != nil {
-			return CompositionResult{}, errors.Wrap(err, errExtractDetails)
+			return CompositionResult{}, errors.Wrap(err, errReadiness)
 		}
+	}
 
-		for key, val := range e {
-			conn[key] = val
+	// We extract connection details only once we've fetched them for all
+	// composed resources, because a combined connection detail may read from
+	// a resource other than the one it's declared on.
+	observed := ComposedResourceStates{}
+	for _, cd := range cds {
+		if cd.TemplateRenderErr != nil {
+			continue
+		}
+		observed[cd.ResourceName] = cd
+	}
+	vars := TransformVariables{Composite: xr, Environment: req.Environment}
+	for _, cd := range cds {
+		if cd.TemplateRenderErr != nil {
+			continue
 		}
 
-		cds[i].Ready, err = c.composed.IsReady(ctx, cds[i].Resource, ReadinessChecksFromComposedTemplate(cds[i].Template)...)
+		e, err := c.composed.ExtractConnection(cd.Resource, cd.ConnectionDetails, ExtractConfigsFromTemplate(cd.Template, vars, observed)...)
 		if err != nil {
-			return CompositionResult{}, errors.Wrap(err, errReadiness)
+			return CompositionResult{}, errors.Wrap(err, errExtractDetails)
+		}
+
+		for key, val := range e {
+			conn[key] = val
 		}
 	}
 
@@ -331,6 +432,28 @@ func filterPatches(pas []v1.Patch, onlyTypes ...v1.PatchType) []v1.Patch {
 	return filtered
 }
 
+// observe returns the observed state of the named composed resources that
+// exist, keyed by name.
+func (c *PTComposer) observe(ctx context.Context, tas []TemplateAssociation, names map[string]bool) (map[string]resource.Composed, error) {
+	observed := make(map[string]resource.Composed)
+	for _, ta := range tas {
+		name := ta.Template.GetName()
+		if !names[name] || ta.Reference.Name == "" {
+			continue
+		}
+		cd := composed.New(composed.FromReference(ta.Reference))
+		err := c.client.Get(ctx, types.NamespacedName{Namespace: ta.Reference.Namespace, Name: ta.Reference.Name}, cd)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errGetComposed)
+		}
+		observed[name] = cd
+	}
+	return observed, nil
+}
+
 // A TemplateAssociation associates a composed resource template with a composed
 // resource. If no such resource exists the reference will be empty.
 type TemplateAssociation struct {
@@ -464,6 +587,42 @@ func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr
 	return tas, nil
 }
 
+// deleteComposed deletes the composed resource the supplied reference refers
+// to, if it exists and is controlled by the supplied composite resource.
+func deleteComposed(ctx context.Context, c client.Client, xr resource.Composite, ref corev1.ObjectReference) error {
+	// If reference does not have a name then we haven't rendered it yet.
+	if ref.Name == "" {
+		return nil
+	}
+
+	cd := composed.New(composed.FromReference(ref))
+	err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cd)
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetComposed)
+	}
+
+	// We want to garbage collect this resource, but we don't control it.
+	if ctrl := metav1.GetControllerOf(cd); ctrl == nil || ctrl.UID != xr.GetUID() {
+		return nil
+	}
+
+	return errors.Wrap(resource.IgnoreNotFound(c.Delete(ctx, cd)), errGCComposed)
+}
+
+// placeholderReference returns a reference to the kind of resource the supplied
+// template composes. The reference has no name, because no such resource
+// exists.
+func placeholderReference(t v1.ComposedTemplate) corev1.ObjectReference {
+	r := composed.New()
+	// A template we can't unmarshal would fail to render anyway, in which case
+	// we return an empty reference.
+	_ = json.Unmarshal(t.Base.Raw, r)
+	return corev1.ObjectReference{APIVersion: r.GetAPIVersion(), Kind: r.GetKind()}
+}
+
 // Observation is the result of composed reconciliation.
 type Observation struct {
 	Ref               corev1.ObjectReference
@@ -496,7 +655,34 @@ func NewAPIDryRunRenderer(c client.Client) *APIDryRunRenderer {
 // Render the supplied composed resource using the supplied composite resource
 // and template. The rendered resource may be submitted to an API server via a
 // dry run create in order to name and validate it.
-func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error { //nolint:gocyclo // Only slightly over (11).
+func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+	if err := RenderComposed(ctx, cp, cd, t, env); err != nil {
+		return err
+	}
+
+	// We don't want to dry-run create a resource that can't be named by the API
+	// server due to a missing generate name. We also don't want to create one
+	// that is already named, because doing so will result in an error. The API
+	// server seems to respond with a 500 ServerTimeout error for all dry-run
+	// failures, so we can't just perform a dry-run and ignore 409 Conflicts for
+	// resources that are already named.
+	if cd.GetName() != "" || cd.GetGenerateName() == "" {
+		return nil
+	}
+
+	// The API server returns an available name derived from generateName when
+	// we perform a dry-run create. This name is likely (but not guaranteed) to
+	// be available when we create the composed resource. If the API server
+	// generates a name that is unavailable it will return a 500 ServerTimeout
+	// error.
+	return errors.Wrap(r.client.Create(ctx, cd, client.DryRunAll), errName)
+}
+
+// RenderComposed renders the supplied composed resource using the supplied
+// composite resource and template. Unlike an APIDryRunRenderer it never names
+// the composed resource; a composed resource that doesn't exist yet will only
+// have a generate name.
+func RenderComposed(_ context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error { //nolint:gocyclo // Only slightly over (10).
 	kind := cd.GetObjectKind().GroupVersionKind().Kind
 	name := cd.GetName()
 	namespace := cd.GetNamespace()
@@ -524,12 +710,20 @@ func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, c
 	cd.SetName(name)
 	cd.SetNamespace(namespace)
 
+	vars := TransformVariables{Composite: cp, Environment: env}
 	for i := range t.Patches {
-		if err := Apply(t.Patches[i], cp, cd, patchTypesFromXR()...); err != nil {
+		ok, err := ConditionMet(t.Patches[i].Condition, cp, env)
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatchCondition, i)
+		}
+		if !ok {
+			continue
+		}
+		if err := ApplyToObjects(t.Patches[i], cp, cd, vars, patchTypesFromXR()...); err != nil {
 			return errors.Wrapf(err, errFmtPatch, i)
 		}
 		if env != nil {
-			if err := ApplyToObjects(t.Patches[i], env, cd, patchTypesFromToEnvironment()...); err != nil {
+			if err := ApplyToObjects(t.Patches[i], env, cd, vars, patchTypesFromToEnvironment()...); err != nil {
 				return errors.Wrapf(err, errFmtPatch, i)
 			}
 		}
@@ -552,29 +746,22 @@ func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, c
 		return errors.Wrap(err, errSetControllerRef)
 	}
 
-	// We don't want to dry-run create a resource that can't be named by the API
-	// server due to a missing generate name. We also don't want to create one
-	// that is already named, because doing so will result in an error. The API
-	// server seems to respond with a 500 ServerTimeout error for all dry-run
-	// failures, so we can't just perform a dry-run and ignore 409 Conflicts for
-	// resources that are already named.
-	if cd.GetName() != "" || cd.GetGenerateName() == "" {
-		return nil
-	}
-
-	// The API server returns an available name derived from generateName when
-	// we perform a dry-run create. This name is likely (but not guaranteed) to
-	// be available when we create the composed resource. If the API server
-	// generates a name that is unavailable it will return a 500 ServerTimeout
-	// error.
-	return errors.Wrap(r.client.Create(ctx, cd, client.DryRunAll), errName)
+	return nil
 }
 
 // RenderComposite renders the supplied composite resource using the supplied composed
 // resource and template.
-func RenderComposite(_ context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, _ *env.Environment) error {
+func RenderComposite(_ context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+	vars := TransformVariables{Composite: cp, Environment: env}
 	for i, p := range t.Patches {
-		if err := Apply(p, cp, cd, patchTypesToXR()...); err != nil {
+		ok, err := ConditionMet(p.Condition, cp, env)
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatchCondition, i)
+		}
+		if !ok {
+			continue
+		}
+		if err := ApplyToObjects(p, cp, cd, vars, patchTypesToXR()...); err != nil {
 			return errors.Wrapf(err, errFmtPatch, i)
 		}
 	}
@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -37,6 +38,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
@@ -417,6 +420,109 @@ func TestPTCompose(t *testing.T) {
 				err: errors.Wrap(errors.Wrap(errBoom, "cannot get object"), errUpdate),
 			},
 		},
+		"DeleteUndesiredComposedError": {
+			reason: "We should return any error encountered while deleting a resource whose template's condition is not met.",
+			params: params{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetOwnerReferences([]metav1.OwnerReference{{Controller: pointer.Bool(true), UID: "cool-uid"}})
+						return nil
+					}),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name:      pointer.String("optional-resource"),
+								Condition: &v1.Condition{FieldPath: "spec.optional", Operator: v1.ConditionOperatorExists},
+							},
+							Reference: corev1.ObjectReference{Name: "cool-optional-resource"},
+						}}
+						return tas, nil
+					})),
+				},
+			},
+			args: args{
+				xr: &fake.Composite{ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"}},
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGCComposed),
+			},
+		},
+		"ConditionNotMet": {
+			reason: "We should not compose resources whose template's condition is not met, but should record a placeholder reference to them.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+						want := []corev1.ObjectReference{
+							{},
+							{APIVersion: "test.crossplane.io/v1", Kind: "Optional"},
+						}
+						if diff := cmp.Diff(want, obj.(resource.Composite).GetResourceReferences()); diff != "" {
+							t.Errorf("Update(...): -want refs, +got refs:\n%s", diff)
+						}
+						return nil
+					}),
+
+					// Apply uses Get and Patch.
+					MockGet:   test.NewMockGetFn(nil),
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{
+							{
+								Template: v1.ComposedTemplate{
+									Name: pointer.String("cool-resource"),
+								},
+							},
+							{
+								Template: v1.ComposedTemplate{
+									Name:      pointer.String("optional-resource"),
+									Base:      runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"Optional"}`)},
+									Condition: &v1.Condition{FieldPath: "spec.optional", Operator: v1.ConditionOperatorExists},
+								},
+							},
+						}
+						return tas, nil
+					})),
+					WithComposedRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+						return nil
+					})),
+					WithCompositeRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+						return nil
+					})),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(cd resource.Composed, conn managed.ConnectionDetails, cfg ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return details, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, o ConditionedObject, rc ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: &fake.Composite{},
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "cool-resource",
+						Ready:        true,
+					}},
+					ConnectionDetails: details,
+				},
+			},
+		},
 		"Success": {
 			reason: "We should return the resources we composed, and our derived connection details.",
 			params: params{
@@ -611,6 +717,65 @@ func TestRender(t *testing.T) {
 				}},
 			},
 		},
+		"PatchConditions": {
+			reason: "Patches should only be applied when their condition is met",
+			client: &test.MockClient{MockCreate: test.NewMockCreateFn(nil)},
+			args: args{
+				cp: func() resource.Composite {
+					xr := composite.New()
+					xr.SetLabels(map[string]string{xcrd.LabelKeyNamePrefixForComposed: "ola"})
+					xr.SetAnnotations(map[string]string{"tier": "premium"})
+					return xr
+				}(),
+				cd: func() resource.Composed {
+					cd := composed.New()
+					cd.SetName("cd")
+					return cd
+				}(),
+				t: v1.ComposedTemplate{
+					Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"Composed"}`)},
+					Patches: []v1.Patch{
+						{
+							Type:          v1.PatchTypeFromCompositeFieldPath,
+							FromFieldPath: pointer.String("metadata.annotations[tier]"),
+							ToFieldPath:   pointer.String("metadata.annotations[met]"),
+							Condition: &v1.Condition{
+								FieldPath: "metadata.annotations[tier]",
+								Operator:  v1.ConditionOperatorIn,
+								Values:    []extv1.JSON{{Raw: []byte(`"premium"`)}, {Raw: []byte(`"enterprise"`)}},
+							},
+						},
+						{
+							Type:          v1.PatchTypeFromCompositeFieldPath,
+							FromFieldPath: pointer.String("metadata.annotations[tier]"),
+							ToFieldPath:   pointer.String("metadata.annotations[unmet]"),
+							Condition: &v1.Condition{
+								FieldPath: "metadata.annotations[tier]",
+								Operator:  v1.ConditionOperatorNotEquals,
+								Value:     &extv1.JSON{Raw: []byte(`"premium"`)},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cd: func() resource.Composed {
+					cd := composed.New()
+					cd.SetAPIVersion("test.crossplane.io/v1")
+					cd.SetKind("Composed")
+					cd.SetName("cd")
+					cd.SetGenerateName("ola-")
+					cd.SetLabels(map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						xcrd.LabelKeyClaimName:             "",
+						xcrd.LabelKeyClaimNamespace:        "",
+					})
+					cd.SetAnnotations(map[string]string{"met": "premium"})
+					cd.SetOwnerReferences([]metav1.OwnerReference{{Controller: &ctrl, BlockOwnerDeletion: &ctrl}})
+					return cd
+				}(),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -18,6 +18,7 @@ package composite
 import (
 	"context"
 	"sort"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -45,7 +46,9 @@ import (
 
 	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
 	fnv1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1alpha1"
+	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -56,6 +59,8 @@ const (
 	errImgPullCfg               = "cannot get xfn image pull config"
 	errBuildFunctionIOObserved  = "cannot build FunctionIO observed state"
 	errBuildFunctionIODesired   = "cannot build initial FunctionIO desired state"
+	errBuildFunctionState       = "cannot build function pipeline observed state"
+	errBuildPipelineSteps       = "cannot build function pipeline steps"
 	errMarshalXR                = "cannot marshal composite resource"
 	errMarshalCD                = "cannot marshal composed resource"
 	errParseImage               = "cannot parse image reference"
@@ -101,7 +106,8 @@ const DefaultTarget = "unix-abstract:crossplane/fn/default.sock"
 // use only one or the other. It does not support anonymous, unnamed resource
 // templates and will panic if it encounters one.
 type PTFComposer struct {
-	client resource.ClientApplicator
+	client  resource.ClientApplicator
+	metrics FunctionPipelineMetrics
 
 	composite   ptfComposite
 	composition ptfComposition
@@ -117,6 +123,7 @@ type ptfComposite struct {
 type ptfComposition struct {
 	PatchAndTransformer
 	FunctionPipelineRunner
+	FunctionStepRunner
 }
 
 // A ComposedResourceGetter gets composed resource state.
@@ -187,6 +194,21 @@ func (fn FunctionPipelineRunnerFn) RunFunctionPipeline(ctx context.Context, req
 	return fn(ctx, req, s, o, d)
 }
 
+// A FunctionStepRunner runs a pipeline of Composition Functions using the
+// v1beta1 RunFunction protocol.
+type FunctionStepRunner interface {
+	RunFunctionSteps(ctx context.Context, s *PTFCompositionState, o *fnv1beta1.State, steps ...FunctionStep) error
+}
+
+// A FunctionStepRunnerFn runs a pipeline of Composition Functions using the
+// v1beta1 RunFunction protocol.
+type FunctionStepRunnerFn func(ctx context.Context, s *PTFCompositionState, o *fnv1beta1.State, steps ...FunctionStep) error
+
+// RunFunctionSteps runs a pipeline of Composition Functions.
+func (fn FunctionStepRunnerFn) RunFunctionSteps(ctx context.Context, s *PTFCompositionState, o *fnv1beta1.State, steps ...FunctionStep) error {
+	return fn(ctx, s, o, steps...)
+}
+
 // A PTFComposerOption is used to configure a PTFComposer.
 type PTFComposerOption func(*PTFComposer)
 
@@ -238,6 +260,22 @@ func WithFunctionPipelineRunner(r FunctionPipelineRunner) PTFComposerOption {
 	}
 }
 
+// WithFunctionStepRunner configures how the PTFComposer should run the
+// pipeline of a Composition that uses the Pipeline mode.
+func WithFunctionStepRunner(r FunctionStepRunner) PTFComposerOption {
+	return func(p *PTFComposer) {
+		p.composition.FunctionStepRunner = r
+	}
+}
+
+// WithFunctionPipelineMetrics configures how the PTFComposer should record
+// metrics about each step of a Composition Function pipeline.
+func WithFunctionPipelineMetrics(m FunctionPipelineMetrics) PTFComposerOption {
+	return func(p *PTFComposer) {
+		p.metrics = m
+	}
+}
+
 // NewPTFComposer returns a new Composer that supports composing resources using
 // both Patch and Transform (P&T) logic and a pipeline of Composition Functions.
 func NewPTFComposer(kube client.Client, o ...PTFComposerOption) *PTFComposer {
@@ -248,7 +286,8 @@ func NewPTFComposer(kube client.Client, o ...PTFComposerOption) *PTFComposer {
 	f := NewSecretConnectionDetailsFetcher(kube)
 
 	c := &PTFComposer{
-		client: resource.ClientApplicator{Client: kube, Applicator: resource.NewAPIPatchingApplicator(kube)},
+		client:  resource.ClientApplicator{Client: kube, Applicator: resource.NewAPIPatchingApplicator(kube)},
+		metrics: NopFunctionPipelineMetrics{},
 
 		composite: ptfComposite{
 			ConnectionDetailsFetcher: f,
@@ -262,6 +301,7 @@ func NewPTFComposer(kube client.Client, o ...PTFComposerOption) *PTFComposer {
 		composition: ptfComposition{
 			PatchAndTransformer:    NewXRCDPatchAndTransformer(RendererFn(RenderComposite), NewAPIDryRunRenderer(kube)),
 			FunctionPipelineRunner: NewFunctionPipeline(ContainerFunctionRunnerFn(RunFunction)),
+			FunctionStepRunner:     NewPackagedFunctionPipeline(NewPackagedFunctionRunner(kube)),
 		},
 	}
 
@@ -275,13 +315,16 @@ func NewPTFComposer(kube client.Client, o ...PTFComposerOption) *PTFComposer {
 // PTFCompositionState is used throughout the PTFComposer to track its state.
 type PTFCompositionState struct {
 	Composite         resource.Composite
+	Environment       *env.Environment
 	ConnectionDetails managed.ConnectionDetails
 	ComposedResources ComposedResourceStates
 	Events            []event.Event
+	FunctionResults   []FunctionStepResult
 }
 
 // Compose resources using both either the Patch & Transform style resources
-// array, the functions array, or both.
+// array, the functions array, or both. Compositions that use the Pipeline mode
+// compose resources using only their pipeline of Composition Functions.
 func (c *PTFComposer) Compose(ctx context.Context, xr resource.Composite, req CompositionRequest) (CompositionResult, error) { //nolint:gocyclo // We probably don't want any further abstraction for the sake of reduced complexity.
 	xc, err := c.composite.FetchConnection(ctx, xr)
 	if err != nil {
@@ -295,37 +338,67 @@ func (c *PTFComposer) Compose(ctx context.Context, xr resource.Composite, req Co
 
 	state := &PTFCompositionState{
 		Composite:         xr,
+		Environment:       req.Environment,
 		ConnectionDetails: xc,
 		ComposedResources: cds,
 		Events:            make([]event.Event, 0),
 	}
 
-	// Build observed state to be passed to our Composition Function pipeline.
-	// Doing this before we patch and transform ensures we report the state we
-	// actually observed before we made any mutations.
-	o, err := FunctionIOObserved(state)
-	if err != nil {
-		return CompositionResult{}, errors.Wrap(err, errBuildFunctionIOObserved)
-	}
+	switch {
+	case req.Revision != nil && req.Revision.Spec.GetMode() == v1.CompositionModePipeline:
+		// Build observed state to be passed to our Composition Function
+		// pipeline. Each step is passed the same observed state.
+		o, err := FunctionStateObserved(state)
+		if err != nil {
+			return CompositionResult{}, errors.Wrap(err, errBuildFunctionState)
+		}
 
-	// Run P&T logic, updating the composition state accordingly.
-	if err := c.composition.PatchAndTransform(ctx, req, state); err != nil {
-		return CompositionResult{}, errors.Wrap(err, errPatchAndTransform)
-	}
+		steps, err := FunctionSteps(req.Revision.Spec.Pipeline)
+		if err != nil {
+			return CompositionResult{}, errors.Wrap(err, errBuildPipelineSteps)
+		}
 
-	// Build the initial desired state to be passed to our Composition Function
-	// pipeline. It's expected that each function in the pipeline will mutate
-	// this state. It includes any desired state accumulated by the P&T logic.
-	d, err := FunctionIODesired(state)
-	if err != nil {
-		return CompositionResult{}, errors.Wrap(err, errBuildFunctionIODesired)
-	}
+		// In Pipeline mode the Composition Function pipeline is wholly
+		// responsible for producing desired state - there's no P&T. Note that
+		// this will replace state.Composite with a new object that was
+		// unmarshalled from the function pipeline's desired state.
+		err = c.composition.RunFunctionSteps(ctx, state, o, steps...)
+		c.observeFunctionResults(state)
+		if err != nil {
+			return CompositionResult{FunctionResults: state.FunctionResults}, errors.Wrap(err, errRunFunctionPipeline)
+		}
+	default:
+		// Build observed state to be passed to our Composition Function
+		// pipeline. Doing this before we patch and transform ensures we report
+		// the state we actually observed before we made any mutations.
+		o, err := FunctionIOObserved(state)
+		if err != nil {
+			return CompositionResult{}, errors.Wrap(err, errBuildFunctionIOObserved)
+		}
+
+		// Run P&T logic, updating the composition state accordingly.
+		if err := c.composition.PatchAndTransform(ctx, req, state); err != nil {
+			return CompositionResult{}, errors.Wrap(err, errPatchAndTransform)
+		}
+
+		// Build the initial desired state to be passed to our Composition
+		// Function pipeline. It's expected that each function in the pipeline
+		// will mutate this state. It includes any desired state accumulated by
+		// the P&T logic.
+		d, err := FunctionIODesired(state)
+		if err != nil {
+			return CompositionResult{}, errors.Wrap(err, errBuildFunctionIODesired)
+		}
 
-	// Run Composition Functions, updating the composition state accordingly.
-	// Note that this will replace state.Composite with a new object that was
-	// unmarshalled from the function pipeline's desired state.
-	if err := c.composition.RunFunctionPipeline(ctx, req, state, o, d); err != nil {
-		return CompositionResult{}, errors.Wrap(err, errRunFunctionPipeline)
+		// Run Composition Functions, updating the composition state
+		// accordingly. Note that this will replace state.Composite with a new
+		// object that was unmarshalled from the function pipeline's desired
+		// state.
+		err = c.composition.RunFunctionPipeline(ctx, req, state, o, d)
+		c.observeFunctionResults(state)
+		if err != nil {
+			return CompositionResult{FunctionResults: state.FunctionResults}, errors.Wrap(err, errRunFunctionPipeline)
+		}
 	}
 
 	// Garbage collect any resources that aren't part of our final desired
@@ -388,7 +461,13 @@ func (c *PTFComposer) Compose(ctx context.Context, xr resource.Composite, req Co
 		out = append(out, cd.ComposedResource)
 	}
 
-	return CompositionResult{ConnectionDetails: state.ConnectionDetails, Composed: out, Events: state.Events}, nil
+	return CompositionResult{ConnectionDetails: state.ConnectionDetails, Composed: out, Events: state.Events, FunctionResults: state.FunctionResults}, nil
+}
+
+func (c *PTFComposer) observeFunctionResults(s *PTFCompositionState) {
+	for _, r := range s.FunctionResults {
+		c.metrics.Observe(r)
+	}
 }
 
 func allPatches(cds ComposedResourceStates) []v1.Patch {
@@ -519,12 +598,15 @@ func FunctionIOObserved(s *PTFCompositionState) (iov1alpha1.Observed, error) {
 type XRCDPatchAndTransformer struct {
 	composite Renderer
 	composed  Renderer
+	ready     ReadinessChecker
 }
 
 // NewXRCDPatchAndTransformer returns a PatchAndTransformer that runs Patches
-// and Transforms against both the XR and composed resources.
+// and Transforms against both the XR and composed resources. Composed
+// resources aren't created until the resources they depend on pass their
+// readiness checks.
 func NewXRCDPatchAndTransformer(composite, composed Renderer) *XRCDPatchAndTransformer {
-	return &XRCDPatchAndTransformer{composite: composite, composed: composed}
+	return &XRCDPatchAndTransformer{composite: composite, composed: composed, ready: ReadinessCheckerFn(IsReady)}
 }
 
 // PatchAndTransform updates the supplied composition state by running all
@@ -546,17 +628,74 @@ func (pt *XRCDPatchAndTransformer) PatchAndTransform(ctx context.Context, req Co
 		}
 	}
 
+	// Replace any templates with a count or forEach with a copy per element.
+	ct, err = ExpandComposedTemplates(s.Composite, req.Environment, ct)
+	if err != nil {
+		return errors.Wrap(err, errExpand)
+	}
+
+	// Resources are rendered after any resources they depend on or patch
+	// from. Rendering a resource overwrites its observed state, so we take a
+	// copy of the observed state of any resources that are patched from first.
+	order, err := RenderOrder(ct)
+	if err != nil {
+		return errors.Wrap(err, errOrder)
+	}
+	sources := patchedFrom(ct)
+	deps := dependsOn(ct)
+	depended := dependedOn(ct)
+	ready := make(map[string]bool, len(depended))
+	from := make(map[string]resource.Composed)
+	for name := range sources {
+		cd, ok := s.ComposedResources[name]
+		if !ok || cd.Resource == nil {
+			continue
+		}
+		if from[name], err = snapshotComposed(cd.Resource); err != nil {
+			return err
+		}
+	}
+
 	// Render composite and composed resources using any P&T resource templates.
 	// Note that we require templates to be named; a CompositionValidator should
 	// enforce this.
-	for i := range ct {
+	for _, i := range order {
 		t := ct[i]
 
-		var r resource.Composed = composed.New()
+		// Resources whose template's condition isn't met aren't part of our
+		// desired state. Any that exist will be garbage collected.
+		ok, err := ConditionMet(t.Condition, s.Composite, req.Environment)
+		if err != nil {
+			return errors.Wrapf(err, errFmtCondition, *t.Name)
+		}
+		if !ok {
+			continue
+		}
 
 		// Templates must be named. This is a requirement to use Composition
 		// Functions and thus this Composer implementation.
-		if cd, exists := s.ComposedResources[*t.Name]; exists {
+		cd, exists := s.ComposedResources[*t.Name]
+
+		// Resources that others depend on are only ready if they exist. We
+		// check before rendering, which overwrites their observed state.
+		if depended[*t.Name] {
+			ready[*t.Name] = false
+			if exists {
+				if ready[*t.Name], err = pt.ready.IsReady(ctx, cd.Resource, ReadinessChecksFromComposedTemplate(&t)...); err != nil {
+					return errors.Wrapf(err, errFmtReadiness, *t.Name, cd.Resource.GetObjectKind().GroupVersionKind().Kind, cd.Resource.GetName())
+				}
+			}
+		}
+
+		// We don't create resources until the resources they depend on are
+		// ready. Until then they're not part of our desired state.
+		if waiting := waitingFor(deps[*t.Name], ready); !exists && len(waiting) > 0 {
+			s.Events = append(s.Events, waitingEvent(*t.Name, waiting))
+			continue
+		}
+
+		var r resource.Composed = composed.New()
+		if exists {
 			r = cd.Resource
 
 			// Typically we'll patch from composed resource status to the XR so
@@ -569,7 +708,10 @@ func (pt *XRCDPatchAndTransformer) PatchAndTransform(ctx context.Context, req Co
 			}
 		}
 
-		rerr := pt.composed.Render(ctx, s.Composite, r, t, req.Environment)
+		rt, rerr := ResolveComposedPatches(t, from, TransformVariables{Composite: s.Composite, Environment: req.Environment})
+		if rerr == nil {
+			rerr = pt.composed.Render(ctx, s.Composite, r, rt, req.Environment)
+		}
 		if rerr != nil {
 			// Failures to patch from XR->composed aren't terminal. It could be
 			// that other resources need to patch the XR in order for the fields
@@ -579,6 +721,12 @@ func (pt *XRCDPatchAndTransformer) PatchAndTransform(ctx context.Context, req Co
 			s.Events = append(s.Events, event.Warning(reasonCompose, errors.Wrapf(rerr, errFmtResourceName, *t.Name)))
 		}
 
+		// Resources that patch from this resource are rendered after it. They
+		// patch from its rendered state if it doesn't exist yet.
+		if _, observed := from[*t.Name]; sources[*t.Name] && !observed && rerr == nil {
+			from[*t.Name] = r
+		}
+
 		s.ComposedResources.Merge(ComposedResourceState{
 			ComposedResource:  ComposedResource{ResourceName: *t.Name},
 			Resource:          r,
@@ -737,10 +885,28 @@ func (p *FunctionPipeline) RunFunctionPipeline(ctx context.Context, req Composit
 	for _, fn := range req.Revision.Spec.Functions {
 		switch fn.Type {
 		case v1.FunctionTypeContainer:
+			sr := FunctionStepResult{Step: fn.Name}
+			if fn.Container != nil {
+				sr.Function = fn.Container.Image
+			}
+			start := time.Now()
 			fnio, err := p.container.RunFunction(ctx, &iov1alpha1.FunctionIO{Config: fn.Config, Observed: o, Desired: d, Results: r}, fn.Container, p.containerOpts...)
+			sr.Duration = metav1.Duration{Duration: time.Since(start)}
 			if err != nil {
+				sr.Message = err.Error()
+				s.FunctionResults = append(s.FunctionResults, sr)
 				return errors.Wrapf(err, errFmtRunFn, fn.Name)
 			}
+
+			// Functions pass through the results of previous functions, so
+			// we attribute any results appended by this function to it.
+			if len(fnio.Results) > len(r) {
+				for _, rs := range fnio.Results[len(r):] {
+					sr.count(string(rs.Severity), rs.Message)
+				}
+			}
+			s.FunctionResults = append(s.FunctionResults, sr)
+
 			// We require each function to pass through any results and desired
 			// state from previous functions in the pipeline that they're
 			// unconcerned with, as well as their own results and desired state.
@@ -1063,8 +1229,9 @@ func NewConnectionDetailsObserver(e ConnectionDetailsExtractor) *ConnectionDetai
 
 // ObserveComposedResources to extract XR connection details.
 func (o *ConnectionDetailsObserver) ObserveComposedResources(_ context.Context, s *PTFCompositionState) error {
+	vars := TransformVariables{Composite: s.Composite, Environment: s.Environment}
 	for _, cd := range s.ComposedResources {
-		ecfgs := append(ExtractConfigsFromTemplate(cd.Template), ExtractConfigsFromDesired(cd.Desired)...)
+		ecfgs := append(ExtractConfigsFromTemplate(cd.Template, vars, s.ComposedResources), ExtractConfigsFromDesired(cd.Desired)...)
 		e, err := o.details.ExtractConnection(cd.Resource, cd.ConnectionDetails, ecfgs...)
 		if err != nil {
 			return errors.Wrapf(err, errFmtExtractConnectionDetails, cd.ResourceName, cd.Resource.GetObjectKind().GroupVersionKind().Kind, cd.Resource.GetName())
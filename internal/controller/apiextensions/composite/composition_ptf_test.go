@@ -54,6 +54,7 @@ import (
 
 	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
 	fnpbv1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1alpha1"
+	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
 	"github.com/crossplane/crossplane/internal/xcrd"
@@ -211,6 +212,38 @@ func TestPTFCompose(t *testing.T) {
 				err: errors.Wrap(errBoom, errRunFunctionPipeline),
 			},
 		},
+		"RunFunctionStepsError": {
+			reason: "We should return any error encountered while running the Composition Function pipeline of a Composition that uses the Pipeline mode.",
+			params: params{
+				o: []PTFComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceGetter(ComposedResourceGetterFn(func(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithPatchAndTransformer(PatchAndTransformerFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
+						return errors.New("we shouldn't run P&T in Pipeline mode")
+					})),
+					WithFunctionStepRunner(FunctionStepRunnerFn(func(ctx context.Context, s *PTFCompositionState, o *fnv1beta1.State, steps ...FunctionStep) error {
+						return errBoom
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Mode: func() *v1.CompositionMode { m := v1.CompositionModePipeline; return &m }(),
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errRunFunctionPipeline),
+			},
+		},
 		"DeleteComposedResourcesError": {
 			reason: "We should return any error encountered while deleting undesired composed resources.",
 			params: params{
@@ -982,6 +1015,145 @@ func TestPatchAndTransform(t *testing.T) {
 				},
 			},
 		},
+		"FromComposedFieldPath": {
+			reason: "We should render resources after the resources they patch from, using their observed state.",
+			params: params{
+				composite: RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+					return nil
+				}),
+				composed: RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+					return json.Unmarshal(t.Base.Raw, cd)
+				}),
+			},
+			args: args{
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Resources: []v1.ComposedTemplate{
+								{
+									Name: pointer.String("db"),
+									Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Database"}`)},
+									Patches: []v1.Patch{{
+										Type:             v1.PatchTypeFromComposedFieldPath,
+										FromResourceName: pointer.String("vpc"),
+										FromFieldPath:    pointer.String("status.id"),
+										ToFieldPath:      pointer.String("spec.vpcId"),
+									}},
+								},
+								{
+									Name: pointer.String("vpc"),
+									Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"VPC"}`)},
+								},
+							},
+						},
+					},
+				},
+				s: &PTFCompositionState{
+					ComposedResources: ComposedResourceStates{
+						"vpc": ComposedResourceState{
+							Resource: &composed.Unstructured{Unstructured: kunstructured.Unstructured{Object: map[string]any{
+								"apiVersion": "example.org/v1",
+								"kind":       "VPC",
+								"status":     map[string]any{"id": "vpc-1234"},
+							}}},
+						},
+					},
+				},
+			},
+			want: want{
+				s: &PTFCompositionState{
+					ComposedResources: ComposedResourceStates{
+						"db": ComposedResourceState{
+							ComposedResource: ComposedResource{ResourceName: "db"},
+							Resource: &composed.Unstructured{Unstructured: kunstructured.Unstructured{Object: map[string]any{
+								"apiVersion": "example.org/v1",
+								"kind":       "Database",
+								"spec":       map[string]any{"vpcId": "vpc-1234"},
+							}}},
+							Template: &v1.ComposedTemplate{
+								Name: pointer.String("db"),
+								Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Database"}`)},
+								Patches: []v1.Patch{{
+									Type:             v1.PatchTypeFromComposedFieldPath,
+									FromResourceName: pointer.String("vpc"),
+									FromFieldPath:    pointer.String("status.id"),
+									ToFieldPath:      pointer.String("spec.vpcId"),
+								}},
+							},
+						},
+						"vpc": ComposedResourceState{
+							ComposedResource: ComposedResource{ResourceName: "vpc"},
+							Resource: &composed.Unstructured{Unstructured: kunstructured.Unstructured{Object: map[string]any{
+								"apiVersion": "example.org/v1",
+								"kind":       "VPC",
+							}}},
+							Template: &v1.ComposedTemplate{
+								Name: pointer.String("vpc"),
+								Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"VPC"}`)},
+							},
+						},
+					},
+				},
+			},
+		},
+		"DependenciesNotReady": {
+			reason: "We should not create resources until the resources they depend on are ready.",
+			params: params{
+				composite: RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+					return nil
+				}),
+				composed: RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+					return json.Unmarshal(t.Base.Raw, cd)
+				}),
+			},
+			args: args{
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Resources: []v1.ComposedTemplate{
+								{
+									Name:      pointer.String("db"),
+									Base:      runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Database"}`)},
+									DependsOn: []string{"vpc"},
+								},
+								{
+									Name: pointer.String("vpc"),
+									Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"VPC"}`)},
+								},
+							},
+						},
+					},
+				},
+				s: &PTFCompositionState{
+					ComposedResources: ComposedResourceStates{
+						"vpc": ComposedResourceState{
+							Resource: &composed.Unstructured{Unstructured: kunstructured.Unstructured{Object: map[string]any{
+								"apiVersion": "example.org/v1",
+								"kind":       "VPC",
+							}}},
+						},
+					},
+				},
+			},
+			want: want{
+				s: &PTFCompositionState{
+					ComposedResources: ComposedResourceStates{
+						"vpc": ComposedResourceState{
+							ComposedResource: ComposedResource{ResourceName: "vpc"},
+							Resource: &composed.Unstructured{Unstructured: kunstructured.Unstructured{Object: map[string]any{
+								"apiVersion": "example.org/v1",
+								"kind":       "VPC",
+							}}},
+							Template: &v1.ComposedTemplate{
+								Name: pointer.String("vpc"),
+								Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"VPC"}`)},
+							},
+						},
+					},
+					Events: []event.Event{waitingEvent("db", []string{"vpc"})},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -1292,8 +1464,14 @@ func TestRunFunctionPipeline(t *testing.T) {
 						},
 					},
 				},
+				s: &PTFCompositionState{},
 			},
 			want: want{
+				s: &PTFCompositionState{
+					FunctionResults: []FunctionStepResult{
+						{Step: "cool-fn", Message: errBoom.Error()},
+					},
+				},
 				err: errors.Wrapf(errBoom, errFmtRunFn, "cool-fn"),
 			},
 		},
@@ -1324,8 +1502,14 @@ func TestRunFunctionPipeline(t *testing.T) {
 						},
 					},
 				},
+				s: &PTFCompositionState{},
 			},
 			want: want{
+				s: &PTFCompositionState{
+					FunctionResults: []FunctionStepResult{
+						{Step: "cool-fn", Fatal: 1, Message: errBoom.Error()},
+					},
+				},
 				err: errors.Wrap(errBoom, errFatalResult),
 			},
 		},
@@ -1358,8 +1542,14 @@ func TestRunFunctionPipeline(t *testing.T) {
 						},
 					},
 				},
+				s: &PTFCompositionState{},
 			},
 			want: want{
+				s: &PTFCompositionState{
+					FunctionResults: []FunctionStepResult{
+						{Step: "cool-fn"},
+					},
+				},
 				err: errors.Wrap(json.Unmarshal([]byte("}"), nil), errUnmarshalDesiredXR),
 			},
 		},
@@ -1410,6 +1600,9 @@ func TestRunFunctionPipeline(t *testing.T) {
 						xr.SetKind("XR")
 						return xr
 					}(),
+					FunctionResults: []FunctionStepResult{
+						{Step: "cool-fn"},
+					},
 				},
 				err: errors.Wrapf(errors.Wrap(json.Unmarshal([]byte("}"), nil), errUnmarshalDesiredCD), errFmtParseDesiredCD, "cool-resource"),
 			},
@@ -1501,6 +1694,9 @@ func TestRunFunctionPipeline(t *testing.T) {
 						event.Warning(reasonCompose, errors.New("oh no")),
 						event.Normal(reasonCompose, "good stuff"),
 					},
+					FunctionResults: []FunctionStepResult{
+						{Step: "cool-fn", Warning: 1, Normal: 1, Message: "good stuff"},
+					},
 				},
 			},
 		},
@@ -1516,7 +1712,8 @@ func TestRunFunctionPipeline(t *testing.T) {
 				t.Errorf("\n%s\nRunFunctionPipeline(...): -want, +got:\n%s", tc.reason, diff)
 			}
 
-			if diff := cmp.Diff(tc.want.s, tc.args.s, cmpopts.EquateEmpty()); diff != "" {
+			// We don't know how long each function took to run.
+			if diff := cmp.Diff(tc.want.s, tc.args.s, cmpopts.EquateEmpty(), cmpopts.IgnoreFields(FunctionStepResult{}, "Duration")); diff != "" {
 				t.Errorf("\n%s\nRunFunctionPipeline(...): -want, +got:\n%s", tc.reason, diff)
 			}
 
@@ -0,0 +1,243 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+// Error strings.
+const (
+	errExpand          = "cannot expand composed resource templates with a count or forEach"
+	errRepeatComposite = "cannot convert composite resource"
+
+	errFmtRepeat            = "cannot repeat composed resource %q"
+	errFmtRepeatFieldPath   = "cannot get field path %q"
+	errFmtRepeatNegative    = "count field path %q must not be negative, got %d"
+	errFmtRepeatMaxCount    = "count field path %q must not exceed %d, got %d"
+	errFmtRepeatMaxForEach  = "forEach field path %q must not have more than %d elements, got %d"
+	errFmtRepeatForEachType = "forEach field path %q must be an array or an object, got %T"
+	errFmtRepeatElementKey  = "forEach field path %q element %d must be a string unless keyFieldPath is specified, got %T"
+	errFmtRepeatKeyObject   = "forEach field path %q element %d must be an object to read keyFieldPath, got %T"
+	errFmtRepeatKeyField    = "cannot get keyFieldPath %q of forEach field path %q element %d"
+	errFmtRepeatElement     = "cannot compose copy %q"
+	errFmtRepeatDuplicate   = "composed resource name %q is not unique"
+)
+
+// maxRepeat is the maximum number of copies of a composed template that a count
+// or forEach may compose. Counts and forEach elements are read from the XR, so
+// without a limit anyone who can edit a claim could make us compose (and
+// allocate) an arbitrary number of resources.
+const maxRepeat = 100
+
+// The fields of the object FromEachFieldPath patches read from.
+const (
+	eachIndex = "index"
+	eachKey   = "key"
+	eachValue = "value"
+)
+
+// ExpandComposedTemplates returns the supplied composed templates with each
+// template that specifies a count or for-each replaced by a copy per element.
+// Copies are named "<name>-<key>", which keeps their composition resource
+// name annotation stable as elements are added or removed. Any FromEachFieldPath
+// patches are applied to the base of each copy, and removed from it.
+func ExpandComposedTemplates(xr resource.Composite, e *env.Environment, cts []v1.ComposedTemplate) ([]v1.ComposedTemplate, error) {
+	repeated := false
+	for i := range cts {
+		repeated = repeated || cts[i].IsRepeated()
+	}
+	if !repeated {
+		return cts, nil
+	}
+
+	from, err := runtime.DefaultUnstructuredConverter.ToUnstructured(xr)
+	if err != nil {
+		return nil, errors.Wrap(err, errRepeatComposite)
+	}
+
+	names := make(map[string]bool)
+	for i := range cts {
+		if !cts[i].IsRepeated() && cts[i].Name != nil {
+			names[*cts[i].Name] = true
+		}
+	}
+
+	out := make([]v1.ComposedTemplate, 0, len(cts))
+	copies := make(map[string][]string)
+	for _, t := range cts {
+		if !t.IsRepeated() {
+			out = append(out, t)
+			continue
+		}
+
+		each, err := repeatElements(fieldpath.Pave(from), t)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtRepeat, t.GetName())
+		}
+		copies[t.GetName()] = make([]string, 0, len(each))
+		for _, el := range each {
+			name := fmt.Sprintf("%s-%s", t.GetName(), el[eachKey])
+			if names[name] {
+				return nil, errors.Errorf(errFmtRepeatDuplicate, name)
+			}
+			names[name] = true
+			copies[t.GetName()] = append(copies[t.GetName()], name)
+
+			c, err := repeatTemplate(t, name, el, xr, e)
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtRepeatElement, name)
+			}
+			out = append(out, c)
+		}
+	}
+
+	// Resources that depend on a template with a count or forEach depend on
+	// all of its copies.
+	for i := range out {
+		out[i].DependsOn = expandDependencies(out[i].DependsOn, copies)
+	}
+	return out, nil
+}
+
+// expandDependencies returns the supplied dependencies with the name of each
+// repeated template replaced by the names of its copies.
+func expandDependencies(deps []string, copies map[string][]string) []string {
+	if deps == nil {
+		return nil
+	}
+	out := make([]string, 0, len(deps))
+	for _, d := range deps {
+		if c, ok := copies[d]; ok {
+			out = append(out, c...)
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// repeatElements returns the index, key, and value of each element the
+// supplied template should be repeated for. A field path that doesn't exist
+// has no elements.
+func repeatElements(xr *fieldpath.Paved, t v1.ComposedTemplate) ([]map[string]any, error) {
+	if t.Count != nil {
+		n, err := xr.GetInteger(t.Count.FromFieldPath)
+		if fieldpath.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtRepeatFieldPath, t.Count.FromFieldPath)
+		}
+		if n < 0 {
+			return nil, errors.Errorf(errFmtRepeatNegative, t.Count.FromFieldPath, n)
+		}
+		if n > maxRepeat {
+			return nil, errors.Errorf(errFmtRepeatMaxCount, t.Count.FromFieldPath, maxRepeat, n)
+		}
+		each := make([]map[string]any, n)
+		for i := range each {
+			each[i] = map[string]any{eachIndex: int64(i), eachKey: strconv.Itoa(i)}
+		}
+		return each, nil
+	}
+
+	v, err := xr.GetValue(t.ForEach.FromFieldPath)
+	if fieldpath.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtRepeatFieldPath, t.ForEach.FromFieldPath)
+	}
+
+	switch v := v.(type) {
+	case []any:
+		if len(v) > maxRepeat {
+			return nil, errors.Errorf(errFmtRepeatMaxForEach, t.ForEach.FromFieldPath, maxRepeat, len(v))
+		}
+		each := make([]map[string]any, len(v))
+		for i, el := range v {
+			key, err := elementKey(t.ForEach, i, el)
+			if err != nil {
+				return nil, err
+			}
+			each[i] = map[string]any{eachIndex: int64(i), eachKey: key, eachValue: el}
+		}
+		return each, nil
+	case map[string]any:
+		if len(v) > maxRepeat {
+			return nil, errors.Errorf(errFmtRepeatMaxForEach, t.ForEach.FromFieldPath, maxRepeat, len(v))
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		each := make([]map[string]any, len(keys))
+		for i, k := range keys {
+			each[i] = map[string]any{eachIndex: int64(i), eachKey: k, eachValue: v[k]}
+		}
+		return each, nil
+	}
+	return nil, errors.Errorf(errFmtRepeatForEachType, t.ForEach.FromFieldPath, v)
+}
+
+// elementKey returns the key of the supplied array element. Copies are named
+// after their key, so we don't fall back to an element's index; that would
+// rename copies when elements were added or removed.
+func elementKey(fe *v1.TemplateForEach, i int, el any) (string, error) {
+	if fe.KeyFieldPath == nil {
+		key, ok := el.(string)
+		if !ok {
+			return "", errors.Errorf(errFmtRepeatElementKey, fe.FromFieldPath, i, el)
+		}
+		return key, nil
+	}
+	obj, ok := el.(map[string]any)
+	if !ok {
+		return "", errors.Errorf(errFmtRepeatKeyObject, fe.FromFieldPath, i, el)
+	}
+	key, err := fieldpath.Pave(obj).GetString(*fe.KeyFieldPath)
+	return key, errors.Wrapf(err, errFmtRepeatKeyField, *fe.KeyFieldPath, fe.FromFieldPath, i)
+}
+
+// repeatTemplate returns a copy of the supplied template with the supplied name
+// for the supplied element.
+func repeatTemplate(t v1.ComposedTemplate, name string, each map[string]any, xr resource.Composite, e *env.Environment) (v1.ComposedTemplate, error) {
+	from := &unstructured.Unstructured{Object: each}
+	c, err := applyPatchesToBase(t, v1.PatchTypeFromEachFieldPath, func(_ v1.Patch) runtime.Object { return from }, TransformVariables{Composite: xr, Environment: e})
+	if err != nil {
+		return v1.ComposedTemplate{}, err
+	}
+	c.Name = pointer.String(name)
+	c.Count = nil
+	c.ForEach = nil
+	return c, nil
+}
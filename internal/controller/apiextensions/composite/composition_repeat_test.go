@@ -0,0 +1,299 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestExpandComposedTemplates(t *testing.T) {
+	xr := &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"nodePools": int64(2),
+			"negative":  int64(-1),
+			"huge":      int64(1000000000),
+			"many":      make([]any, maxRepeat+1),
+			"regions":   []any{"us-east-1", "eu-west-1"},
+			"sizes":     []any{int64(10), int64(20)},
+			"pools": []any{
+				map[string]any{"name": "small", "size": int64(10)},
+				map[string]any{"name": "large", "size": int64(20)},
+			},
+			"buckets": map[string]any{
+				"logs":   map[string]any{"retention": int64(30)},
+				"assets": map[string]any{"retention": int64(365)},
+			},
+		},
+	}}}
+
+	base := runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket"}`)}
+	bucket := func(spec string) runtime.RawExtension {
+		return runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Bucket","spec":` + spec + `}`)}
+	}
+	fromEach := func(from, to string) v1.Patch {
+		return v1.Patch{Type: v1.PatchTypeFromEachFieldPath, FromFieldPath: pointer.String(from), ToFieldPath: pointer.String(to)}
+	}
+	fromXR := v1.Patch{Type: v1.PatchTypeFromCompositeFieldPath, FromFieldPath: pointer.String("spec.owner")}
+
+	type args struct {
+		xr  resource.Composite
+		cts []v1.ComposedTemplate
+	}
+	type want struct {
+		cts []v1.ComposedTemplate
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotRepeated": {
+			reason: "Templates without a count or forEach should be returned unchanged.",
+			args: args{
+				xr:  xr,
+				cts: []v1.ComposedTemplate{{Name: pointer.String("bucket"), Base: base}},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{{Name: pointer.String("bucket"), Base: base}},
+			},
+		},
+		"Count": {
+			reason: "A template with a count should be replaced by a copy per index, named after the template and its index.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("network"), Base: base},
+					{
+						Name:    pointer.String("nodepool"),
+						Base:    base,
+						Count:   &v1.TemplateCount{FromFieldPath: "spec.nodePools"},
+						Patches: []v1.Patch{fromEach("index", "spec.index"), fromXR},
+					},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("network"), Base: base},
+					{Name: pointer.String("nodepool-0"), Base: bucket(`{"index":0}`), Patches: []v1.Patch{fromXR}},
+					{Name: pointer.String("nodepool-1"), Base: bucket(`{"index":1}`), Patches: []v1.Patch{fromXR}},
+				},
+			},
+		},
+		"CountNotFound": {
+			reason: "A template with a count read from a field that doesn't exist should be removed.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("nodepool"), Base: base, Count: &v1.TemplateCount{FromFieldPath: "spec.missing"}},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{},
+			},
+		},
+		"CountNegative": {
+			reason: "We should return an error if a count is negative.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("nodepool"), Base: base, Count: &v1.TemplateCount{FromFieldPath: "spec.negative"}},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtRepeatNegative, "spec.negative", -1), errFmtRepeat, "nodepool"),
+			},
+		},
+		"CountTooLarge": {
+			reason: "We should return an error if a count exceeds the maximum number of copies.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("nodepool"), Base: base, Count: &v1.TemplateCount{FromFieldPath: "spec.huge"}},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtRepeatMaxCount, "spec.huge", maxRepeat, 1000000000), errFmtRepeat, "nodepool"),
+			},
+		},
+		"ForEachTooLarge": {
+			reason: "We should return an error if a forEach has more elements than the maximum number of copies.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("bucket"), Base: base, ForEach: &v1.TemplateForEach{FromFieldPath: "spec.many"}},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtRepeatMaxForEach, "spec.many", maxRepeat, maxRepeat+1), errFmtRepeat, "bucket"),
+			},
+		},
+		"ForEachStringArray": {
+			reason: "A template with a forEach over an array of strings should be replaced by a copy named after each element.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{
+						Name:    pointer.String("bucket"),
+						Base:    base,
+						ForEach: &v1.TemplateForEach{FromFieldPath: "spec.regions"},
+						Patches: []v1.Patch{fromEach("value", "spec.region")},
+					},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("bucket-us-east-1"), Base: bucket(`{"region":"us-east-1"}`), Patches: []v1.Patch{}},
+					{Name: pointer.String("bucket-eu-west-1"), Base: bucket(`{"region":"eu-west-1"}`), Patches: []v1.Patch{}},
+				},
+			},
+		},
+		"ForEachArrayWithoutKey": {
+			reason: "We should return an error if a forEach over an array of non-strings doesn't specify a key field path.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("bucket"), Base: base, ForEach: &v1.TemplateForEach{FromFieldPath: "spec.sizes"}},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtRepeatElementKey, "spec.sizes", 0, int64(10)), errFmtRepeat, "bucket"),
+			},
+		},
+		"ForEachArrayKeyFieldPath": {
+			reason: "A template with a forEach over an array of objects should be replaced by a copy named after each element's key field.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{
+						Name:    pointer.String("bucket"),
+						Base:    base,
+						ForEach: &v1.TemplateForEach{FromFieldPath: "spec.pools", KeyFieldPath: pointer.String("name")},
+						Patches: []v1.Patch{fromEach("value.size", "spec.size")},
+					},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("bucket-small"), Base: bucket(`{"size":10}`), Patches: []v1.Patch{}},
+					{Name: pointer.String("bucket-large"), Base: bucket(`{"size":20}`), Patches: []v1.Patch{}},
+				},
+			},
+		},
+		"ForEachArrayKeyFieldPathNotObject": {
+			reason: "We should return an error if a forEach specifies a key field path but its elements aren't objects.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("bucket"), Base: base, ForEach: &v1.TemplateForEach{FromFieldPath: "spec.sizes", KeyFieldPath: pointer.String("name")}},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtRepeatKeyObject, "spec.sizes", 0, int64(10)), errFmtRepeat, "bucket"),
+			},
+		},
+		"ForEachObject": {
+			reason: "A template with a forEach over an object should be replaced by a copy named after each key, in key order.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{
+						Name:    pointer.String("bucket"),
+						Base:    base,
+						ForEach: &v1.TemplateForEach{FromFieldPath: "spec.buckets"},
+						Patches: []v1.Patch{fromEach("key", "spec.name"), fromEach("value.retention", "spec.retention")},
+					},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("bucket-assets"), Base: bucket(`{"name":"assets","retention":365}`), Patches: []v1.Patch{}},
+					{Name: pointer.String("bucket-logs"), Base: bucket(`{"name":"logs","retention":30}`), Patches: []v1.Patch{}},
+				},
+			},
+		},
+		"ForEachNotIterable": {
+			reason: "We should return an error if a forEach field is neither an array nor an object.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("bucket"), Base: base, ForEach: &v1.TemplateForEach{FromFieldPath: "spec.nodePools"}},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtRepeatForEachType, "spec.nodePools", int64(2)), errFmtRepeat, "bucket"),
+			},
+		},
+		"DependsOnRepeated": {
+			reason: "A template that depends on a template with a count should depend on each of its copies.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("cluster"), Base: base, DependsOn: []string{"network", "nodepool"}},
+					{Name: pointer.String("network"), Base: base},
+					{Name: pointer.String("nodepool"), Base: base, Count: &v1.TemplateCount{FromFieldPath: "spec.nodePools"}},
+				},
+			},
+			want: want{
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("cluster"), Base: base, DependsOn: []string{"network", "nodepool-0", "nodepool-1"}},
+					{Name: pointer.String("network"), Base: base},
+					{Name: pointer.String("nodepool-0"), Base: base},
+					{Name: pointer.String("nodepool-1"), Base: base},
+				},
+			},
+		},
+		"DuplicateName": {
+			reason: "We should return an error if a copy has the same name as another template.",
+			args: args{
+				xr: xr,
+				cts: []v1.ComposedTemplate{
+					{Name: pointer.String("nodepool-1"), Base: base},
+					{Name: pointer.String("nodepool"), Base: base, Count: &v1.TemplateCount{FromFieldPath: "spec.nodePools"}},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtRepeatDuplicate, "nodepool-1"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ExpandComposedTemplates(tc.args.xr, nil, tc.args.cts)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExpandComposedTemplates(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cts, got); diff != "" {
+				t.Errorf("\n%s\nExpandComposedTemplates(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -24,13 +24,17 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	kjson "k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
@@ -40,6 +44,8 @@ import (
 const (
 	errMathTransformTypeFailed = "type %s is not supported for math transform type"
 	errFmtMathInputNonNumber   = "input is required to be a number for math transformer, got %T"
+	errFmtMathOverflow         = "%s math transform overflows a 64-bit integer"
+	errFmtMathDivideByZero     = "%s math transform cannot divide by zero"
 
 	errFmtRequiredField                 = "%s is required by type %s"
 	errFmtConvertInputTypeNotSupported  = "invalid input type %T"
@@ -65,17 +71,27 @@ const (
 	errStringTransformTypeConvert       = "string transform of type %s convert is not set"
 	errStringTransformTypeTrim          = "string transform of type %s trim is not set"
 	errStringTransformTypeRegexp        = "string transform of type %s regexp is not set"
+	errStringTransformTypeJoin          = "string transform of type %s join is not set"
+	errStringTransformTypeSplit         = "string transform of type %s split is not set"
+	errStringTransformTypeReplace       = "string transform of type %s replace is not set"
+	errStringTransformTypeSubstring     = "string transform of type %s substring is not set"
+	errStringTransformTypeTruncate      = "string transform of type %s truncate is not set"
 	errStringTransformTypeRegexpFailed  = "could not compile regexp"
+	errFmtStringJoinInputNonArray       = "input is required to be an array for join string transform, got %T"
 	errStringTransformTypeRegexpNoMatch = "regexp %q had no matches for group %d"
 	errStringConvertTypeFailed          = "type %s is not supported for string convert"
 
 	errDecodeString = "string is not valid base64"
 	errMarshalJSON  = "cannot marshal to JSON"
+	errMarshalYAML  = "cannot marshal to YAML"
+	errParseJSON    = "cannot parse input as JSON"
+	errParseYAML    = "cannot parse input as YAML"
 	errHash         = "cannot generate hash"
 )
 
-// Resolve the supplied Transform.
-func Resolve(t v1.Transform, input any) (any, error) { //nolint:gocyclo // This is a long but simple/same-y switch.
+// Resolve the supplied Transform. The supplied variables are made available
+// to transforms that evaluate expressions.
+func Resolve(t v1.Transform, input any, vars TransformVariables) (any, error) { //nolint:gocyclo // This is a long but simple/same-y switch.
 	var out any
 	var err error
 
@@ -105,6 +121,11 @@ func Resolve(t v1.Transform, input any) (any, error) { //nolint:gocyclo // This
 			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
 		}
 		out, err = ResolveConvert(*t.Convert, input)
+	case v1.TransformTypeCEL:
+		if t.CEL == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveCEL(*t.CEL, input, vars)
 	default:
 		return nil, errors.Errorf(errFmtTypeNotSupported, string(t.Type))
 	}
@@ -127,20 +148,28 @@ func ResolveMath(t v1.MathTransform, input any) (any, error) {
 		return resolveMathMultiply(t, input)
 	case v1.MathTransformTypeClampMin, v1.MathTransformTypeClampMax:
 		return resolveMathClamp(t, input)
+	case v1.MathTransformTypeAdd, v1.MathTransformTypeSubtract:
+		return resolveMathAdd(t, input)
+	case v1.MathTransformTypeDivide, v1.MathTransformTypeModulo:
+		return resolveMathDivide(t, input)
+	case v1.MathTransformTypeRound, v1.MathTransformTypeFloor, v1.MathTransformTypeCeil:
+		return resolveMathRound(t, input)
+	case v1.MathTransformTypeConvertUnit:
+		return resolveMathConvertUnit(t, input)
 	default:
 		return nil, errors.Errorf(errMathTransformTypeFailed, string(t.Type))
 	}
 }
 
 // resolveMathMultiply resolves a multiply transform, returning an error if the
-// input is not a number. If the input is a float, the result will be a float64, otherwise
-// it will be an int64.
+// input is not a number or if the result would overflow. If the input is a
+// float, the result will be a float64, otherwise it will be an int64.
 func resolveMathMultiply(t v1.MathTransform, input any) (any, error) {
 	switch i := input.(type) {
 	case int:
-		return int64(i) * *t.Multiply, nil
+		return mulInt64(t.GetType(), int64(i), *t.Multiply)
 	case int64:
-		return i * *t.Multiply, nil
+		return mulInt64(t.GetType(), i, *t.Multiply)
 	case float64:
 		return i * float64(*t.Multiply), nil
 	default:
@@ -179,6 +208,156 @@ func resolveMathClamp(t v1.MathTransform, input any) (any, error) {
 	return input, nil
 }
 
+// resolveMathAdd resolves an add or subtract transform, returning an error if
+// the input is not a number or if the result would overflow. If the input is a
+// float, the result will be a float64, otherwise it will be an int64.
+func resolveMathAdd(t v1.MathTransform, input any) (any, error) {
+	var operand int64
+	switch t.GetType() { //nolint:exhaustive // We validate the type in ResolveMath
+	case v1.MathTransformTypeAdd:
+		operand = *t.Add
+	case v1.MathTransformTypeSubtract:
+		operand = *t.Subtract
+	default:
+		return nil, errors.Errorf(errMathTransformTypeFailed, string(t.Type))
+	}
+
+	var in int64
+	switch i := input.(type) {
+	case int:
+		in = int64(i)
+	case int64:
+		in = i
+	case float64:
+		if t.GetType() == v1.MathTransformTypeSubtract {
+			return i - float64(operand), nil
+		}
+		return i + float64(operand), nil
+	default:
+		return nil, errors.Errorf(errFmtMathInputNonNumber, input)
+	}
+
+	if t.GetType() == v1.MathTransformTypeSubtract {
+		return toInt64(t.GetType(), new(big.Int).Sub(big.NewInt(in), big.NewInt(operand)))
+	}
+	return toInt64(t.GetType(), new(big.Int).Add(big.NewInt(in), big.NewInt(operand)))
+}
+
+// resolveMathDivide resolves a divide or modulo transform, returning an error
+// if the input is not a number, if the divisor is zero, or if the result would
+// overflow. If the input is a float, the result will be a float64, otherwise
+// it will be an int64. Integer division truncates toward zero.
+func resolveMathDivide(t v1.MathTransform, input any) (any, error) {
+	var divisor int64
+	switch t.GetType() { //nolint:exhaustive // We validate the type in ResolveMath
+	case v1.MathTransformTypeDivide:
+		divisor = *t.Divide
+	case v1.MathTransformTypeModulo:
+		divisor = *t.Modulo
+	default:
+		return nil, errors.Errorf(errMathTransformTypeFailed, string(t.Type))
+	}
+	if divisor == 0 {
+		return nil, errors.Errorf(errFmtMathDivideByZero, t.GetType())
+	}
+
+	var in int64
+	switch i := input.(type) {
+	case int:
+		in = int64(i)
+	case int64:
+		in = i
+	case float64:
+		if t.GetType() == v1.MathTransformTypeModulo {
+			return math.Mod(i, float64(divisor)), nil
+		}
+		return i / float64(divisor), nil
+	default:
+		return nil, errors.Errorf(errFmtMathInputNonNumber, input)
+	}
+
+	q, r := new(big.Int).QuoRem(big.NewInt(in), big.NewInt(divisor), new(big.Int))
+	if t.GetType() == v1.MathTransformTypeModulo {
+		return toInt64(t.GetType(), r)
+	}
+	return toInt64(t.GetType(), q)
+}
+
+// resolveMathRound resolves a round, floor or ceil transform, returning an
+// error if the input is not a number or if the result would overflow. The
+// result is always an int64.
+func resolveMathRound(t v1.MathTransform, input any) (any, error) {
+	var in float64
+	switch i := input.(type) {
+	case int:
+		return int64(i), nil
+	case int64:
+		return i, nil
+	case float64:
+		in = i
+	default:
+		return nil, errors.Errorf(errFmtMathInputNonNumber, input)
+	}
+
+	var out float64
+	switch t.GetType() { //nolint:exhaustive // We validate the type in ResolveMath
+	case v1.MathTransformTypeRound:
+		out = math.Round(in)
+	case v1.MathTransformTypeFloor:
+		out = math.Floor(in)
+	case v1.MathTransformTypeCeil:
+		out = math.Ceil(in)
+	default:
+		return nil, errors.Errorf(errMathTransformTypeFailed, string(t.Type))
+	}
+
+	// NaN and infinite values cannot be represented as a big.Int.
+	if math.IsNaN(out) || math.IsInf(out, 0) {
+		return nil, errors.Errorf(errFmtMathOverflow, t.GetType())
+	}
+	i, _ := big.NewFloat(out).Int(nil)
+	return toInt64(t.GetType(), i)
+}
+
+// resolveMathConvertUnit resolves a convert unit transform, returning an error
+// if the input is not a number or if the result would overflow. If the input is
+// a float, the result will be a float64, otherwise it will be an int64 that is
+// truncated toward zero if it can't be represented exactly in the target unit.
+func resolveMathConvertUnit(t v1.MathTransform, input any) (any, error) {
+	from := big.NewInt(t.ConvertUnit.From.Factor())
+	to := big.NewInt(t.ConvertUnit.To.Factor())
+
+	var in int64
+	switch i := input.(type) {
+	case int:
+		in = int64(i)
+	case int64:
+		in = i
+	case float64:
+		return i * float64(from.Int64()) / float64(to.Int64()), nil
+	default:
+		return nil, errors.Errorf(errFmtMathInputNonNumber, input)
+	}
+
+	out := new(big.Int).Mul(big.NewInt(in), from)
+	return toInt64(t.GetType(), out.Quo(out, to))
+}
+
+// mulInt64 multiplies the supplied integers, returning an error if the result
+// would overflow.
+func mulInt64(mt v1.MathTransformType, a, b int64) (any, error) {
+	return toInt64(mt, new(big.Int).Mul(big.NewInt(a), big.NewInt(b)))
+}
+
+// toInt64 returns the supplied integer as an int64, returning an error if it
+// does not fit.
+func toInt64(mt v1.MathTransformType, i *big.Int) (any, error) {
+	if !i.IsInt64() {
+		return nil, errors.Errorf(errFmtMathOverflow, mt)
+	}
+	return i.Int64(), nil
+}
+
 // ResolveMap resolves a Map transform.
 func ResolveMap(t v1.MapTransform, input any) (any, error) {
 	switch i := input.(type) {
@@ -278,32 +457,64 @@ func unmarshalJSON(j extv1.JSON, output *any) error {
 	return json.Unmarshal(j.Raw, output)
 }
 
-// ResolveString resolves a String transform.
-func ResolveString(t v1.StringTransform, input any) (string, error) {
+// ResolveString resolves a String transform. The result is a string for all
+// string transform types except Split, which produces an array of strings.
+func ResolveString(t v1.StringTransform, input any) (any, error) {
+	var out any
+	var err error
 	switch t.Type {
 	case v1.StringTransformTypeFormat:
 		if t.Format == nil {
-			return "", errors.Errorf(errStringTransformTypeFormat, string(t.Type))
+			return nil, errors.Errorf(errStringTransformTypeFormat, string(t.Type))
 		}
-		return fmt.Sprintf(*t.Format, input), nil
+		out = fmt.Sprintf(*t.Format, input)
 	case v1.StringTransformTypeConvert:
 		if t.Convert == nil {
-			return "", errors.Errorf(errStringTransformTypeConvert, string(t.Type))
+			return nil, errors.Errorf(errStringTransformTypeConvert, string(t.Type))
 		}
-		return stringConvertTransform(t.Convert, input)
+		out, err = stringConvertTransform(t.Convert, input)
 	case v1.StringTransformTypeTrimPrefix, v1.StringTransformTypeTrimSuffix:
 		if t.Trim == nil {
-			return "", errors.Errorf(errStringTransformTypeTrim, string(t.Type))
+			return nil, errors.Errorf(errStringTransformTypeTrim, string(t.Type))
 		}
-		return stringTrimTransform(input, t.Type, *t.Trim), nil
+		out = stringTrimTransform(input, t.Type, *t.Trim)
 	case v1.StringTransformTypeRegexp:
 		if t.Regexp == nil {
-			return "", errors.Errorf(errStringTransformTypeRegexp, string(t.Type))
+			return nil, errors.Errorf(errStringTransformTypeRegexp, string(t.Type))
+		}
+		out, err = stringRegexpTransform(input, *t.Regexp)
+	case v1.StringTransformTypeJoin:
+		if t.Join == nil {
+			return nil, errors.Errorf(errStringTransformTypeJoin, string(t.Type))
+		}
+		out, err = stringJoinTransform(input, *t.Join)
+	case v1.StringTransformTypeSplit:
+		if t.Split == nil {
+			return nil, errors.Errorf(errStringTransformTypeSplit, string(t.Type))
+		}
+		out = stringSplitTransform(input, *t.Split)
+	case v1.StringTransformTypeReplace:
+		if t.Replace == nil {
+			return nil, errors.Errorf(errStringTransformTypeReplace, string(t.Type))
+		}
+		out = strings.ReplaceAll(fmt.Sprintf("%v", input), t.Replace.Search, t.Replace.Replace)
+	case v1.StringTransformTypeSubstring:
+		if t.Substring == nil {
+			return nil, errors.Errorf(errStringTransformTypeSubstring, string(t.Type))
+		}
+		out = stringSubstringTransform(input, t.Substring.Start, t.Substring.End)
+	case v1.StringTransformTypeTruncate:
+		if t.Truncate == nil {
+			return nil, errors.Errorf(errStringTransformTypeTruncate, string(t.Type))
 		}
-		return stringRegexpTransform(input, *t.Regexp)
+		out = stringSubstringTransform(input, 0, &t.Truncate.Length)
 	default:
-		return "", errors.Errorf(errStringTransformTypeFailed, string(t.Type))
+		return nil, errors.Errorf(errStringTransformTypeFailed, string(t.Type))
 	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 func stringConvertTransform(t *v1.StringConversionType, input any) (string, error) {
@@ -362,6 +573,45 @@ func stringTrimTransform(input any, t v1.StringTransformType, trim string) strin
 	return str
 }
 
+func stringJoinTransform(input any, j v1.StringTransformJoin) (string, error) {
+	arr, ok := input.([]any)
+	if !ok {
+		return "", errors.Errorf(errFmtStringJoinInputNonArray, input)
+	}
+	elems := make([]string, len(arr))
+	for i, e := range arr {
+		elems[i] = fmt.Sprintf("%v", e)
+	}
+	return strings.Join(elems, j.Separator), nil
+}
+
+func stringSplitTransform(input any, sp v1.StringTransformSplit) []any {
+	parts := strings.Split(fmt.Sprintf("%v", input), sp.Separator)
+	out := make([]any, len(parts))
+	for i, p := range parts {
+		out[i] = p
+	}
+	return out
+}
+
+// stringSubstringTransform returns the characters of the input from start up
+// to, but not including, end. Positions outside the input are clamped to the
+// input. A nil end means the end of the input.
+func stringSubstringTransform(input any, start int, end *int) string {
+	r := []rune(fmt.Sprintf("%v", input))
+	e := len(r)
+	if end != nil && *end < e {
+		e = *end
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start >= e {
+		return ""
+	}
+	return string(r[start:e])
+}
+
 func stringRegexpTransform(input any, r v1.StringTransformRegexp) (string, error) {
 	re, err := regexp.Compile(r.Match)
 	if err != nil {
@@ -386,7 +636,7 @@ func ResolveConvert(t v1.ConvertTransform, input any) (any, error) {
 		return nil, err
 	}
 
-	from := v1.TransformIOType(fmt.Sprintf("%T", input))
+	from := transformIOType(input)
 	if !from.IsValid() {
 		return nil, errors.Errorf(errFmtConvertInputTypeNotSupported, input)
 	}
@@ -397,6 +647,17 @@ func ResolveConvert(t v1.ConvertTransform, input any) (any, error) {
 	return f(input)
 }
 
+// transformIOType returns the TransformIOType of the supplied input.
+func transformIOType(input any) v1.TransformIOType {
+	switch input.(type) {
+	case map[string]any:
+		return v1.TransformIOTypeObject
+	case []any:
+		return v1.TransformIOTypeArray
+	}
+	return v1.TransformIOType(fmt.Sprintf("%T", input))
+}
+
 type conversionPair struct {
 	from   v1.TransformIOType
 	to     v1.TransformIOType
@@ -483,4 +744,51 @@ var conversions = map[conversionPair]func(any) (any, error){
 	{from: v1.TransformIOTypeFloat64, to: v1.TransformIOTypeBool, format: v1.ConvertTransformFormatNone}: func(i any) (any, error) { //nolint:unparam // See note above.
 		return i.(float64) == float64(1), nil
 	},
+
+	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeObject, format: v1.ConvertTransformFormatJSON}: fromJSON[map[string]any],
+	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeObject, format: v1.ConvertTransformFormatYAML}: fromYAML[map[string]any],
+	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeArray, format: v1.ConvertTransformFormatJSON}:  fromJSON[[]any],
+	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeArray, format: v1.ConvertTransformFormatYAML}:  fromYAML[[]any],
+
+	{from: v1.TransformIOTypeObject, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatJSON}: toJSON,
+	{from: v1.TransformIOTypeObject, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatYAML}: toYAML,
+	{from: v1.TransformIOTypeArray, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatJSON}:  toJSON,
+	{from: v1.TransformIOTypeArray, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatYAML}:  toYAML,
+}
+
+// fromJSON parses the supplied JSON string. Numbers are parsed as int64 where
+// possible, consistent with how unstructured objects represent them.
+func fromJSON[T map[string]any | []any](i any) (any, error) {
+	var out T
+	if err := kjson.Unmarshal([]byte(i.(string)), &out); err != nil {
+		return nil, errors.Wrap(err, errParseJSON)
+	}
+	return out, nil
+}
+
+// fromYAML parses the supplied YAML string.
+func fromYAML[T map[string]any | []any](i any) (any, error) {
+	j, err := yaml.YAMLToJSON([]byte(i.(string)))
+	if err != nil {
+		return nil, errors.Wrap(err, errParseYAML)
+	}
+	return fromJSON[T](string(j))
+}
+
+// toJSON serializes the supplied input as a JSON string.
+func toJSON(i any) (any, error) {
+	b, err := json.Marshal(i)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalJSON)
+	}
+	return string(b), nil
+}
+
+// toYAML serializes the supplied input as a YAML string.
+func toYAML(i any) (any, error) {
+	b, err := yaml.Marshal(i)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalYAML)
+	}
+	return string(b), nil
 }
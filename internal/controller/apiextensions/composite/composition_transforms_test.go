@@ -19,13 +19,16 @@ package composite
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	kjson "k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -459,13 +462,20 @@ func TestMatchResolve(t *testing.T) {
 
 func TestMathResolve(t *testing.T) {
 	two := int64(2)
+	zero := int64(0)
+	maxInt64 := int64(math.MaxInt64)
 
 	type args struct {
-		mathType   v1.MathTransformType
-		multiplier *int64
-		clampMin   *int64
-		clampMax   *int64
-		i          any
+		mathType    v1.MathTransformType
+		multiplier  *int64
+		clampMin    *int64
+		clampMax    *int64
+		add         *int64
+		subtract    *int64
+		divide      *int64
+		modulo      *int64
+		convertUnit *v1.ConvertUnitTransform
+		i           any
 	}
 	type want struct {
 		o   any
@@ -624,10 +634,256 @@ func TestMathResolve(t *testing.T) {
 				},
 			},
 		},
+		"MultiplyOverflow": {
+			args: args{
+				mathType:   v1.MathTransformTypeMultiply,
+				multiplier: &maxInt64,
+				i:          3,
+			},
+			want: want{
+				err: errors.Errorf(errFmtMathOverflow, v1.MathTransformTypeMultiply),
+			},
+		},
+		"AddSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeAdd,
+				add:      &two,
+				i:        3,
+			},
+			want: want{
+				o: int64(5),
+			},
+		},
+		"AddSuccessFloat64": {
+			args: args{
+				mathType: v1.MathTransformTypeAdd,
+				add:      &two,
+				i:        1.5,
+			},
+			want: want{
+				o: 3.5,
+			},
+		},
+		"AddOverflow": {
+			args: args{
+				mathType: v1.MathTransformTypeAdd,
+				add:      &two,
+				i:        maxInt64,
+			},
+			want: want{
+				err: errors.Errorf(errFmtMathOverflow, v1.MathTransformTypeAdd),
+			},
+		},
+		"AddNoConfig": {
+			args: args{
+				mathType: v1.MathTransformTypeAdd,
+				i:        25,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "add",
+				},
+			},
+		},
+		"SubtractSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeSubtract,
+				subtract: &two,
+				i:        int64(3),
+			},
+			want: want{
+				o: int64(1),
+			},
+		},
+		"SubtractOverflow": {
+			args: args{
+				mathType: v1.MathTransformTypeSubtract,
+				subtract: &maxInt64,
+				i:        int64(-2),
+			},
+			want: want{
+				err: errors.Errorf(errFmtMathOverflow, v1.MathTransformTypeSubtract),
+			},
+		},
+		"DivideSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeDivide,
+				divide:   &two,
+				i:        7,
+			},
+			want: want{
+				o: int64(3),
+			},
+		},
+		"DivideSuccessFloat64": {
+			args: args{
+				mathType: v1.MathTransformTypeDivide,
+				divide:   &two,
+				i:        7.0,
+			},
+			want: want{
+				o: 3.5,
+			},
+		},
+		"DivideByZero": {
+			args: args{
+				mathType: v1.MathTransformTypeDivide,
+				divide:   &zero,
+				i:        7,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "divide",
+				},
+			},
+		},
+		"ModuloSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeModulo,
+				modulo:   &two,
+				i:        int64(7),
+			},
+			want: want{
+				o: int64(1),
+			},
+		},
+		"ModuloSuccessFloat64": {
+			args: args{
+				mathType: v1.MathTransformTypeModulo,
+				modulo:   &two,
+				i:        7.5,
+			},
+			want: want{
+				o: 1.5,
+			},
+		},
+		"ModuloByZero": {
+			args: args{
+				mathType: v1.MathTransformTypeModulo,
+				modulo:   &zero,
+				i:        7,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "modulo",
+				},
+			},
+		},
+		"RoundSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeRound,
+				i:        2.5,
+			},
+			want: want{
+				o: int64(3),
+			},
+		},
+		"RoundSuccessInt": {
+			args: args{
+				mathType: v1.MathTransformTypeRound,
+				i:        2,
+			},
+			want: want{
+				o: int64(2),
+			},
+		},
+		"RoundOverflow": {
+			args: args{
+				mathType: v1.MathTransformTypeRound,
+				i:        1e19,
+			},
+			want: want{
+				err: errors.Errorf(errFmtMathOverflow, v1.MathTransformTypeRound),
+			},
+		},
+		"FloorSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeFloor,
+				i:        -2.5,
+			},
+			want: want{
+				o: int64(-3),
+			},
+		},
+		"CeilSuccess": {
+			args: args{
+				mathType: v1.MathTransformTypeCeil,
+				i:        2.1,
+			},
+			want: want{
+				o: int64(3),
+			},
+		},
+		"ConvertUnitGibiToMebi": {
+			args: args{
+				mathType:    v1.MathTransformTypeConvertUnit,
+				convertUnit: &v1.ConvertUnitTransform{From: v1.UnitGibi, To: v1.UnitMebi},
+				i:           int64(4),
+			},
+			want: want{
+				o: int64(4096),
+			},
+		},
+		"ConvertUnitMebiToGibiTruncates": {
+			args: args{
+				mathType:    v1.MathTransformTypeConvertUnit,
+				convertUnit: &v1.ConvertUnitTransform{From: v1.UnitMebi, To: v1.UnitGibi},
+				i:           1536,
+			},
+			want: want{
+				o: int64(1),
+			},
+		},
+		"ConvertUnitFloat64": {
+			args: args{
+				mathType:    v1.MathTransformTypeConvertUnit,
+				convertUnit: &v1.ConvertUnitTransform{From: v1.UnitMebi, To: v1.UnitGibi},
+				i:           1536.0,
+			},
+			want: want{
+				o: 1.5,
+			},
+		},
+		"ConvertUnitOverflow": {
+			args: args{
+				mathType:    v1.MathTransformTypeConvertUnit,
+				convertUnit: &v1.ConvertUnitTransform{From: v1.UnitExbi},
+				i:           16,
+			},
+			want: want{
+				err: errors.Errorf(errFmtMathOverflow, v1.MathTransformTypeConvertUnit),
+			},
+		},
+		"ConvertUnitUnknownUnit": {
+			args: args{
+				mathType:    v1.MathTransformTypeConvertUnit,
+				convertUnit: &v1.ConvertUnitTransform{From: "Zi"},
+				i:           16,
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "convertUnit.from",
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			tr := v1.MathTransform{Type: tc.mathType, Multiply: tc.multiplier, ClampMin: tc.clampMin, ClampMax: tc.clampMax}
+			tr := v1.MathTransform{
+				Type:        tc.mathType,
+				Multiply:    tc.multiplier,
+				ClampMin:    tc.clampMin,
+				ClampMax:    tc.clampMax,
+				Add:         tc.add,
+				Subtract:    tc.subtract,
+				Divide:      tc.divide,
+				Modulo:      tc.modulo,
+				ConvertUnit: tc.convertUnit,
+			}
 			got, err := ResolveMath(tr, tc.i)
 
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
@@ -648,15 +904,20 @@ func TestMathResolve(t *testing.T) {
 func TestStringResolve(t *testing.T) {
 
 	type args struct {
-		stype   v1.StringTransformType
-		fmts    *string
-		convert *v1.StringConversionType
-		trim    *string
-		regexp  *v1.StringTransformRegexp
-		i       any
+		stype     v1.StringTransformType
+		fmts      *string
+		convert   *v1.StringConversionType
+		trim      *string
+		regexp    *v1.StringTransformRegexp
+		join      *v1.StringTransformJoin
+		split     *v1.StringTransformSplit
+		replace   *v1.StringTransformReplace
+		substring *v1.StringTransformSubstring
+		truncate  *v1.StringTransformTruncate
+		i         any
 	}
 	type want struct {
-		o   string
+		o   any
 		err error
 	}
 	sFmt := "verycool%s"
@@ -783,7 +1044,6 @@ func TestStringResolve(t *testing.T) {
 				i:       "ThisStringIsNotBase64",
 			},
 			want: want{
-				o:   "N\x18\xacJ\xda\xe2\x9e\x02,6\x8bAjǺ",
 				err: errors.Wrap(errors.New("illegal base64 data at input byte 20"), errDecodeString),
 			},
 		},
@@ -804,7 +1064,6 @@ func TestStringResolve(t *testing.T) {
 				i:       func() {},
 			},
 			want: want{
-				o:   "0000000000000000000000000000000000000000",
 				err: errors.Wrap(errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON), errHash),
 			},
 		},
@@ -825,7 +1084,6 @@ func TestStringResolve(t *testing.T) {
 				i:       func() {},
 			},
 			want: want{
-				o:   "0000000000000000000000000000000000000000000000000000000000000000",
 				err: errors.Wrap(errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON), errHash),
 			},
 		},
@@ -866,7 +1124,6 @@ func TestStringResolve(t *testing.T) {
 				i:       func() {},
 			},
 			want: want{
-				o:   "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
 				err: errors.Wrap(errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON), errHash),
 			},
 		},
@@ -981,19 +1238,160 @@ func TestStringResolve(t *testing.T) {
 				},
 			},
 			want: want{
-				o:   "",
 				err: errors.Wrap(errors.New("json: unsupported type: func()"), errMarshalJSON),
 			},
 		},
+		"JoinSuccess": {
+			args: args{
+				stype: v1.StringTransformTypeJoin,
+				join:  &v1.StringTransformJoin{Separator: "-"},
+				i:     []any{"cool", int64(42), true},
+			},
+			want: want{
+				o: "cool-42-true",
+			},
+		},
+		"JoinNonArrayInput": {
+			args: args{
+				stype: v1.StringTransformTypeJoin,
+				join:  &v1.StringTransformJoin{Separator: "-"},
+				i:     "cool",
+			},
+			want: want{
+				err: errors.Errorf(errFmtStringJoinInputNonArray, "cool"),
+			},
+		},
+		"JoinNotSet": {
+			args: args{
+				stype: v1.StringTransformTypeJoin,
+				i:     []any{"cool"},
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeJoin, string(v1.StringTransformTypeJoin)),
+			},
+		},
+		"SplitSuccess": {
+			args: args{
+				stype: v1.StringTransformTypeSplit,
+				split: &v1.StringTransformSplit{Separator: ","},
+				i:     "a,b,c",
+			},
+			want: want{
+				o: []any{"a", "b", "c"},
+			},
+		},
+		"SplitNotSet": {
+			args: args{
+				stype: v1.StringTransformTypeSplit,
+				i:     "a,b,c",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeSplit, string(v1.StringTransformTypeSplit)),
+			},
+		},
+		"ReplaceSuccess": {
+			args: args{
+				stype:   v1.StringTransformTypeReplace,
+				replace: &v1.StringTransformReplace{Search: ".", Replace: "-"},
+				i:       "my.cool.bucket",
+			},
+			want: want{
+				o: "my-cool-bucket",
+			},
+		},
+		"ReplaceRemove": {
+			args: args{
+				stype:   v1.StringTransformTypeReplace,
+				replace: &v1.StringTransformReplace{Search: "-"},
+				i:       "my-cool-bucket",
+			},
+			want: want{
+				o: "mycoolbucket",
+			},
+		},
+		"SubstringSuccess": {
+			args: args{
+				stype:     v1.StringTransformTypeSubstring,
+				substring: &v1.StringTransformSubstring{Start: 3, End: pointer.Int(7)},
+				i:         "my-cool-bucket",
+			},
+			want: want{
+				o: "cool",
+			},
+		},
+		"SubstringToEnd": {
+			args: args{
+				stype:     v1.StringTransformTypeSubstring,
+				substring: &v1.StringTransformSubstring{Start: 8},
+				i:         "my-cool-bucket",
+			},
+			want: want{
+				o: "bucket",
+			},
+		},
+		"SubstringStartBeyondInput": {
+			args: args{
+				stype:     v1.StringTransformTypeSubstring,
+				substring: &v1.StringTransformSubstring{Start: 20, End: pointer.Int(30)},
+				i:         "my-cool-bucket",
+			},
+			want: want{
+				o: "",
+			},
+		},
+		"SubstringMultibyte": {
+			args: args{
+				stype:     v1.StringTransformTypeSubstring,
+				substring: &v1.StringTransformSubstring{Start: 1, End: pointer.Int(3)},
+				i:         "héllo",
+			},
+			want: want{
+				o: "él",
+			},
+		},
+		"TruncateSuccess": {
+			args: args{
+				stype:    v1.StringTransformTypeTruncate,
+				truncate: &v1.StringTransformTruncate{Length: 7},
+				i:        "my-cool-bucket",
+			},
+			want: want{
+				o: "my-cool",
+			},
+		},
+		"TruncateShortInput": {
+			args: args{
+				stype:    v1.StringTransformTypeTruncate,
+				truncate: &v1.StringTransformTruncate{Length: 63},
+				i:        "my-cool-bucket",
+			},
+			want: want{
+				o: "my-cool-bucket",
+			},
+		},
+		"TruncateNotSet": {
+			args: args{
+				stype: v1.StringTransformTypeTruncate,
+				i:     "my-cool-bucket",
+			},
+			want: want{
+				err: errors.Errorf(errStringTransformTypeTruncate, string(v1.StringTransformTypeTruncate)),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 
 			tr := v1.StringTransform{Type: tc.stype,
-				Format:  tc.fmts,
-				Convert: tc.convert,
-				Trim:    tc.trim,
-				Regexp:  tc.regexp,
+				Format:    tc.fmts,
+				Convert:   tc.convert,
+				Trim:      tc.trim,
+				Regexp:    tc.regexp,
+				Join:      tc.join,
+				Split:     tc.split,
+				Replace:   tc.replace,
+				Substring: tc.substring,
+				Truncate:  tc.truncate,
 			}
 
 			got, err := ResolveString(tr, tc.i)
@@ -1121,6 +1519,131 @@ func TestConvertResolve(t *testing.T) {
 				o: int64(1),
 			},
 		},
+		"StringToObjectJSON": {
+			args: args{
+				i:      `{"policy":{"version":1,"effect":"Allow","actions":["a","b"]}}`,
+				to:     v1.TransformIOTypeObject,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatJSON))),
+			},
+			want: want{
+				o: map[string]any{
+					"policy": map[string]any{
+						"version": int64(1),
+						"effect":  "Allow",
+						"actions": []any{"a", "b"},
+					},
+				},
+			},
+		},
+		"StringToObjectInvalidJSON": {
+			args: args{
+				i:      `{"policy":`,
+				to:     v1.TransformIOTypeObject,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatJSON))),
+			},
+			want: want{
+				err: errors.Wrap(func() error {
+					m := map[string]any{}
+					return kjson.Unmarshal([]byte(`{"policy":`), &m)
+				}(), errParseJSON),
+			},
+		},
+		"StringToObjectYAML": {
+			args: args{
+				i:      "policy:\n  version: 1\n  effect: Allow\n",
+				to:     v1.TransformIOTypeObject,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatYAML))),
+			},
+			want: want{
+				o: map[string]any{
+					"policy": map[string]any{
+						"version": int64(1),
+						"effect":  "Allow",
+					},
+				},
+			},
+		},
+		"StringToArrayJSON": {
+			args: args{
+				i:      `["a", 2, true]`,
+				to:     v1.TransformIOTypeArray,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatJSON))),
+			},
+			want: want{
+				o: []any{"a", int64(2), true},
+			},
+		},
+		"StringToArrayYAML": {
+			args: args{
+				i:      "- a\n- b\n",
+				to:     v1.TransformIOTypeArray,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatYAML))),
+			},
+			want: want{
+				o: []any{"a", "b"},
+			},
+		},
+		"StringToArrayInvalidYAML": {
+			args: args{
+				i:      "a: b: c",
+				to:     v1.TransformIOTypeArray,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatYAML))),
+			},
+			want: want{
+				err: errors.Wrap(func() error {
+					_, err := yaml.YAMLToJSON([]byte("a: b: c"))
+					return err
+				}(), errParseYAML),
+			},
+		},
+		"StringToObjectNoFormat": {
+			args: args{
+				i:  `{"a":"b"}`,
+				to: v1.TransformIOTypeObject,
+			},
+			want: want{
+				err: errors.Errorf(errFmtConvertFormatPairNotSupported, "string", "object", string(v1.ConvertTransformFormatNone)),
+			},
+		},
+		"ObjectToStringJSON": {
+			args: args{
+				i:      map[string]any{"b": int64(1), "a": "c"},
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatJSON))),
+			},
+			want: want{
+				o: `{"a":"c","b":1}`,
+			},
+		},
+		"ObjectToStringYAML": {
+			args: args{
+				i:      map[string]any{"b": int64(1), "a": "c"},
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatYAML))),
+			},
+			want: want{
+				o: "a: c\nb: 1\n",
+			},
+		},
+		"ArrayToStringJSON": {
+			args: args{
+				i:      []any{"a", int64(1)},
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatJSON))),
+			},
+			want: want{
+				o: `["a",1]`,
+			},
+		},
+		"ObjectToObjectNoOp": {
+			args: args{
+				i:  map[string]any{"a": "b"},
+				to: v1.TransformIOTypeObject,
+			},
+			want: want{
+				o: map[string]any{"a": "b"},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
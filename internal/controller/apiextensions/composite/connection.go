@@ -18,6 +18,7 @@ package composite
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -42,6 +43,11 @@ const (
 	errFmtConnDetailKey  = "connection detail of type %q key is not set"
 	errFmtConnDetailVal  = "connection detail of type %q value is not set"
 	errFmtConnDetailPath = "connection detail of type %q fromFieldPath is not set"
+	errFmtConnDetailComb = "connection detail of type %q combine is not set"
+	errFmtConnDetailVar  = "connection detail of type %q variable at index %d must set exactly one of fromConnectionSecretKey and fromFieldPath"
+
+	errFmtConnDetailTransform = "cannot transform connection detail %q"
+	errFmtConnDetailMarshal   = "cannot marshal connection detail %q"
 )
 
 // A ConnectionDetailsFetcherFn fetches the connection details of the supplied
@@ -196,41 +202,98 @@ func (fn ConnectionDetailsExtractorFn) ExtractConnection(cd resource.Composed, c
 // ExtractConnectionDetails extracts XR connection details from the supplied
 // composed resource. If no ExtractConfigs are supplied no connection details
 // will be returned.
-func ExtractConnectionDetails(cd resource.Composed, data managed.ConnectionDetails, cfg ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) { //nolint:gocyclo // TODO(negz): Break extraction out from validation, like we do with readiness.
+func ExtractConnectionDetails(cd resource.Composed, data managed.ConnectionDetails, cfg ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
 	out := map[string][]byte{}
 	for _, cfg := range cfg {
 		if cfg.Name == "" {
 			return nil, errors.Errorf(errConnDetailName)
 		}
-		switch tp := cfg.Type; tp {
-		case ConnectionDetailTypeFromValue:
-			if cfg.Value == nil {
-				return nil, errors.Errorf(errFmtConnDetailVal, tp)
-			}
-			out[cfg.Name] = []byte(*cfg.Value)
-		case ConnectionDetailTypeFromConnectionSecretKey:
-			if cfg.FromConnectionSecretKey == nil {
-				return nil, errors.Errorf(errFmtConnDetailKey, tp)
-			}
-			if data[*cfg.FromConnectionSecretKey] == nil {
-				// We don't consider this an error because it's possible the
-				// key will still be written at some point in the future.
-				continue
+		v, ok, err := extractConnectionDetail(cd, data, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		for i, t := range cfg.Transforms {
+			if v, err = Resolve(t, v, cfg.Variables); err != nil {
+				return nil, errors.Wrapf(errors.Wrapf(err, errFmtTransformAtIndex, i), errFmtConnDetailTransform, cfg.Name)
 			}
-			out[cfg.Name] = data[*cfg.FromConnectionSecretKey]
-		case ConnectionDetailTypeFromFieldPath:
-			if cfg.FromFieldPath == nil {
-				return nil, errors.Errorf(errFmtConnDetailPath, tp)
+		}
+		b, err := connectionDetailBytes(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtConnDetailMarshal, cfg.Name)
+		}
+		out[cfg.Name] = b
+	}
+	return out, nil
+}
+
+// extractConnectionDetail returns the untransformed value of the supplied
+// connection detail. It returns false if the value doesn't exist (yet).
+func extractConnectionDetail(cd resource.Composed, data managed.ConnectionDetails, cfg ConnectionDetailExtractConfig) (any, bool, error) { //nolint:gocyclo // TODO(negz): Break extraction out from validation, like we do with readiness.
+	switch tp := cfg.Type; tp {
+	case ConnectionDetailTypeFromValue:
+		if cfg.Value == nil {
+			return nil, false, errors.Errorf(errFmtConnDetailVal, tp)
+		}
+		return *cfg.Value, true, nil
+	case ConnectionDetailTypeFromConnectionSecretKey:
+		if cfg.FromConnectionSecretKey == nil {
+			return nil, false, errors.Errorf(errFmtConnDetailKey, tp)
+		}
+		if data[*cfg.FromConnectionSecretKey] == nil {
+			// We don't consider this an error because it's possible the
+			// key will still be written at some point in the future.
+			return nil, false, nil
+		}
+		return string(data[*cfg.FromConnectionSecretKey]), true, nil
+	case ConnectionDetailTypeFromFieldPath:
+		if cfg.FromFieldPath == nil {
+			return nil, false, errors.Errorf(errFmtConnDetailPath, tp)
+		}
+		// Note we're checking that the error _is_ nil. If we hit an error
+		// we silently avoid including this connection secret. It's possible
+		// the path will start existing with a valid value in future.
+		v, err := fromFieldPath(cd, *cfg.FromFieldPath)
+		return v, err == nil, nil
+	case ConnectionDetailTypeCombine:
+		if cfg.Combine == nil {
+			return nil, false, errors.Errorf(errFmtConnDetailComb, tp)
+		}
+		in := make([]any, len(cfg.Combine.Variables))
+		for i, v := range cfg.Combine.Variables {
+			from, fromData := cd, data
+			if v.FromResourceName != nil {
+				// The other resource may not have been composed yet.
+				r, ok := cfg.Resources[*v.FromResourceName]
+				if !ok || r.Resource == nil {
+					return nil, false, nil
+				}
+				from, fromData = r.Resource, r.ConnectionDetails
 			}
-			// Note we're checking that the error _is_ nil. If we hit an error
-			// we silently avoid including this connection secret. It's possible
-			// the path will start existing with a valid value in future.
-			if b, err := fromFieldPath(cd, *cfg.FromFieldPath); err == nil {
-				out[cfg.Name] = b
+			switch {
+			case v.FromConnectionSecretKey != nil && v.FromFieldPath == nil:
+				if fromData[*v.FromConnectionSecretKey] == nil {
+					return nil, false, nil
+				}
+				in[i] = string(fromData[*v.FromConnectionSecretKey])
+			case v.FromFieldPath != nil && v.FromConnectionSecretKey == nil:
+				fv, err := fromFieldPath(from, *v.FromFieldPath)
+				if err != nil {
+					return nil, false, nil
+				}
+				in[i] = fv
+			default:
+				return nil, false, errors.Errorf(errFmtConnDetailVar, tp, i)
 			}
 		}
+		// Like a Combine patch, we only produce a value once all of the
+		// variables exist, to avoid a format string receiving fewer inputs
+		// than it expects.
+		return fmt.Sprintf(cfg.Combine.Format, in...), true, nil
 	}
-	return out, nil
+	return nil, false, nil
 }
 
 // A ConnectionDetailType is a type of connection detail.
@@ -241,6 +304,7 @@ const (
 	ConnectionDetailTypeFromConnectionSecretKey ConnectionDetailType = "FromConnectionSecretKey"
 	ConnectionDetailTypeFromFieldPath           ConnectionDetailType = "FromFieldPath"
 	ConnectionDetailTypeFromValue               ConnectionDetailType = "FromValue"
+	ConnectionDetailTypeCombine                 ConnectionDetailType = "Combine"
 )
 
 // A ConnectionDetailExtractConfig configures how an XR connection detail should
@@ -269,11 +333,30 @@ type ConnectionDetailExtractConfig struct {
 	// an explicit value may be set to inject a fixed, non-sensitive connection
 	// secret values, for example a well-known port.
 	Value *string
+
+	// Combine the values of several of the composed resource's connection
+	// details and fields into a single value.
+	Combine *v1.ConnectionDetailCombine
+
+	// Transforms are applied, in order, to the connection detail's value
+	// before it is propagated.
+	Transforms []v1.Transform
+
+	// Variables are available to Transforms. For example a CEL transform may
+	// read the composite resource and the environment.
+	Variables TransformVariables
+
+	// Resources are the composite resource's composed resources, keyed by
+	// name. Combine variables may read from their connection details and
+	// fields.
+	Resources ComposedResourceStates
 }
 
 // ExtractConfigsFromTemplate builds extract configs for the supplied P&T style
-// composed resource template.
-func ExtractConfigsFromTemplate(t *v1.ComposedTemplate) []ConnectionDetailExtractConfig {
+// composed resource template. The supplied variables are available to the
+// connection details' transforms, and the supplied composed resources to their
+// combine variables.
+func ExtractConfigsFromTemplate(t *v1.ComposedTemplate, vars TransformVariables, resources ComposedResourceStates) []ConnectionDetailExtractConfig {
 	if t == nil {
 		return nil
 	}
@@ -284,6 +367,10 @@ func ExtractConfigsFromTemplate(t *v1.ComposedTemplate) []ConnectionDetailExtrac
 			Value:                   t.ConnectionDetails[i].Value,
 			FromConnectionSecretKey: t.ConnectionDetails[i].FromConnectionSecretKey,
 			FromFieldPath:           t.ConnectionDetails[i].FromFieldPath,
+			Combine:                 t.ConnectionDetails[i].Combine,
+			Transforms:              t.ConnectionDetails[i].Transforms,
+			Variables:               vars,
+			Resources:               resources,
 		}
 
 		if t.ConnectionDetails[i].Name != nil {
@@ -329,41 +416,23 @@ func ExtractConfigsFromDesired(dr *iov1alpha1.DesiredResource) []ConnectionDetai
 // the type. We maintain this behaviour for backward compatibility when no type
 // is set.
 func connectionDetailType(d v1.ConnectionDetail) ConnectionDetailType {
-	switch {
-	case d.Type != nil:
-		return ConnectionDetailType(*d.Type)
-	case d.Value != nil:
-		return ConnectionDetailTypeFromValue
-	case d.FromConnectionSecretKey != nil:
-		return ConnectionDetailTypeFromConnectionSecretKey
-	case d.FromFieldPath != nil:
-		return ConnectionDetailTypeFromFieldPath
-	default:
-		// If nothing was specified, assume FromConnectionSecretKey, which was
-		// the only value we originally supported. We don't have enough
-		// information (i.e. the key name) to actually fetch it, so we'll still
-		// return an error eventually.
-		return ConnectionDetailTypeFromConnectionSecretKey
-	}
+	return ConnectionDetailType(d.GetType())
 }
 
-// fromFieldPath tries to read the value from the supplied field path first as a
-// plain string. If this fails, it falls back to reading it as JSON.
-func fromFieldPath(from runtime.Object, path string) ([]byte, error) {
+// fromFieldPath reads the value at the supplied field path.
+func fromFieldPath(from runtime.Object, path string) (any, error) {
 	fromMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
 	if err != nil {
 		return nil, err
 	}
+	return fieldpath.Pave(fromMap).GetValue(path)
+}
 
-	str, err := fieldpath.Pave(fromMap).GetString(path)
-	if err == nil {
-		return []byte(str), nil
-	}
-
-	in, err := fieldpath.Pave(fromMap).GetValue(path)
-	if err != nil {
-		return nil, err
+// connectionDetailBytes returns the supplied connection detail value as a plain
+// string if it is one. If it isn't, it falls back to encoding it as JSON.
+func connectionDetailBytes(v any) ([]byte, error) {
+	if s, ok := v.(string); ok {
+		return []byte(s), nil
 	}
-
-	return json.Marshal(in)
+	return json.Marshal(v)
 }
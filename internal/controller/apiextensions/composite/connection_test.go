@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,10 +35,12 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
 )
 
 var (
@@ -317,6 +320,208 @@ func TestExtractConnectionDetails(t *testing.T) {
 				err: errors.Errorf(errFmtConnDetailPath, v1.ConnectionDetailTypeFromFieldPath),
 			},
 		},
+		"MissingCombineError": {
+			reason: "We should return an error if the combine configuration is missing.",
+			args: args{
+				cfg: []ConnectionDetailExtractConfig{
+					{
+						Name: "cool-detail",
+						Type: ConnectionDetailTypeCombine,
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtConnDetailComb, ConnectionDetailTypeCombine),
+			},
+		},
+		"InvalidCombineVariableError": {
+			reason: "We should return an error if a combine variable sets both a connection secret key and a field path.",
+			args: args{
+				cfg: []ConnectionDetailExtractConfig{
+					{
+						Name: "cool-detail",
+						Type: ConnectionDetailTypeCombine,
+						Combine: &v1.ConnectionDetailCombine{
+							Variables: []v1.ConnectionDetailVariable{{
+								FromConnectionSecretKey: pointer.String("host"),
+								FromFieldPath:           pointer.String("objectMeta.name"),
+							}},
+							Format: "%s",
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtConnDetailVar, ConnectionDetailTypeCombine, 0),
+			},
+		},
+		"TransformError": {
+			reason: "We should return an error if a connection detail's transforms fail.",
+			args: args{
+				cfg: []ConnectionDetailExtractConfig{
+					{
+						Name:  "cool-detail",
+						Type:  ConnectionDetailTypeFromValue,
+						Value: pointer.String("cool"),
+						Transforms: []v1.Transform{{
+							Type: v1.TransformTypeMath,
+							Math: &v1.MathTransform{
+								Multiply: pointer.Int64(2),
+							},
+						}},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrapf(errors.Wrapf(errors.Errorf(errFmtMathInputNonNumber, "cool"), errFmtTransformTypeFailed, v1.TransformTypeMath), errFmtTransformAtIndex, 0), errFmtConnDetailTransform, "cool-detail"),
+			},
+		},
+		"CombineAndTransformSuccess": {
+			reason: "Should combine connection secret keys and fields, and transform connection details.",
+			args: args{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "test",
+						Generation: 4,
+					},
+				},
+				data: managed.ConnectionDetails{
+					"host": []byte("db.example.org"),
+					"user": []byte("admin"),
+				},
+				cfg: []ConnectionDetailExtractConfig{
+					{
+						Type: ConnectionDetailTypeCombine,
+						Name: "url",
+						Combine: &v1.ConnectionDetailCombine{
+							Variables: []v1.ConnectionDetailVariable{
+								{FromConnectionSecretKey: pointer.String("host")},
+								{FromFieldPath: pointer.String("objectMeta.generation")},
+								{FromFieldPath: pointer.String("objectMeta.name")},
+							},
+							Format: "jdbc:postgresql://%s:%d/%s",
+						},
+					},
+					{
+						Type: ConnectionDetailTypeCombine,
+						Name: "missing",
+						Combine: &v1.ConnectionDetailCombine{
+							Variables: []v1.ConnectionDetailVariable{
+								{FromConnectionSecretKey: pointer.String("host")},
+								{FromConnectionSecretKey: pointer.String("password")},
+							},
+							Format: "%s:%s",
+						},
+					},
+					{
+						Type:                    ConnectionDetailTypeFromConnectionSecretKey,
+						Name:                    "user",
+						FromConnectionSecretKey: pointer.String("user"),
+						Transforms: []v1.Transform{{
+							Type: v1.TransformTypeString,
+							String: &v1.StringTransform{
+								Type:    v1.StringTransformTypeConvert,
+								Convert: func() *v1.StringConversionType { c := v1.StringConversionTypeToUpper; return &c }(),
+							},
+						}},
+					},
+					{
+						Type:          ConnectionDetailTypeFromFieldPath,
+						Name:          "generation",
+						FromFieldPath: pointer.String("objectMeta.generation"),
+						Transforms: []v1.Transform{{
+							Type: v1.TransformTypeMath,
+							Math: &v1.MathTransform{
+								Multiply: pointer.Int64(2),
+							},
+						}},
+					},
+				},
+			},
+			want: want{
+				conn: managed.ConnectionDetails{
+					"url":        []byte("jdbc:postgresql://db.example.org:4/test"),
+					"user":       []byte("ADMIN"),
+					"generation": []byte("8"),
+				},
+			},
+		},
+		"TransformVariables": {
+			reason: "Transforms should be able to read the supplied composite resource and environment.",
+			args: args{
+				cd: &fake.Composed{},
+				cfg: []ConnectionDetailExtractConfig{
+					{
+						Type:  ConnectionDetailTypeFromValue,
+						Name:  "endpoint",
+						Value: pointer.String("db"),
+						Transforms: []v1.Transform{{
+							Type: v1.TransformTypeCEL,
+							CEL:  &v1.CELTransform{Expression: "self + '.' + xr.spec.region + '.' + environment.domain"},
+						}},
+						Variables: TransformVariables{
+							Composite: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+								"spec": map[string]any{"region": "us-east-1"},
+							}}},
+							Environment: &env.Environment{Unstructured: unstructured.Unstructured{Object: map[string]any{
+								"domain": "example.org",
+							}}},
+						},
+					},
+				},
+			},
+			want: want{
+				conn: managed.ConnectionDetails{
+					"endpoint": []byte("db.us-east-1.example.org"),
+				},
+			},
+		},
+		"CombineFromResourceName": {
+			reason: "Combine variables should be able to read from other composed resources, and skip the detail if those resources don't exist yet.",
+			args: args{
+				cd: &fake.Composed{},
+				data: managed.ConnectionDetails{
+					"user": []byte("admin"),
+				},
+				cfg: []ConnectionDetailExtractConfig{
+					{
+						Type: ConnectionDetailTypeCombine,
+						Name: "url",
+						Combine: &v1.ConnectionDetailCombine{
+							Variables: []v1.ConnectionDetailVariable{
+								{FromConnectionSecretKey: pointer.String("user")},
+								{FromResourceName: pointer.String("db"), FromConnectionSecretKey: pointer.String("host")},
+								{FromResourceName: pointer.String("db"), FromFieldPath: pointer.String("objectMeta.name")},
+							},
+							Format: "postgres://%s@%s/%s",
+						},
+						Resources: ComposedResourceStates{
+							"db": ComposedResourceState{
+								Resource: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cool-db"}},
+								ConnectionDetails: managed.ConnectionDetails{
+									"host": []byte("db.example.org"),
+								},
+							},
+						},
+					},
+					{
+						Type: ConnectionDetailTypeCombine,
+						Name: "missing",
+						Combine: &v1.ConnectionDetailCombine{
+							Variables: []v1.ConnectionDetailVariable{
+								{FromResourceName: pointer.String("cache"), FromConnectionSecretKey: pointer.String("host")},
+							},
+							Format: "%s",
+						},
+					},
+				},
+			},
+			want: want{
+				conn: managed.ConnectionDetails{
+					"url": []byte("postgres://admin@db.example.org/cool-db"),
+				},
+			},
+		},
 		"FetchConfigSuccess": {
 			reason: "Should extract only the selected set of secret keys",
 			args: args{
@@ -450,11 +655,37 @@ func TestExtractConfigsFromTemplate(t *testing.T) {
 				}},
 			},
 		},
+		"CombineAndTransforms": {
+			reason: "A template's connection detail combine configuration and transforms should be propagated.",
+			args: args{
+				t: &v1.ComposedTemplate{
+					ConnectionDetails: []v1.ConnectionDetail{{
+						Name: pointer.String("url"),
+						Combine: &v1.ConnectionDetailCombine{
+							Variables: []v1.ConnectionDetailVariable{{FromConnectionSecretKey: pointer.String("host")}},
+							Format:    "https://%s",
+						},
+						Transforms: []v1.Transform{{Type: v1.TransformTypeString}},
+					}},
+				},
+			},
+			want: want{
+				cfgs: []ConnectionDetailExtractConfig{{
+					Name: "url",
+					Type: ConnectionDetailTypeCombine,
+					Combine: &v1.ConnectionDetailCombine{
+						Variables: []v1.ConnectionDetailVariable{{FromConnectionSecretKey: pointer.String("host")}},
+						Format:    "https://%s",
+					},
+					Transforms: []v1.Transform{{Type: v1.TransformTypeString}},
+				}},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			cfgs := ExtractConfigsFromTemplate(tc.args.t)
+			cfgs := ExtractConfigsFromTemplate(tc.args.t, TransformVariables{}, nil)
 
 			if diff := cmp.Diff(tc.want.cfgs, cfgs); diff != "" {
 				t.Errorf("\n%s\nExtractConfigsFromTemplate(...): -want, +got:\n%s", tc.reason, diff)
@@ -581,6 +812,13 @@ func TestConnectionDetailType(t *testing.T) {
 			},
 			want: ConnectionDetailTypeFromFieldPath,
 		},
+		"CombineInferred": {
+			d: v1.ConnectionDetail{
+				Name:    &name,
+				Combine: &v1.ConnectionDetailCombine{},
+			},
+			want: ConnectionDetailTypeCombine,
+		},
 		"DefaultToFromConnectionSecretKey": {
 			d: v1.ConnectionDetail{
 				Name: &name,
@@ -19,26 +19,43 @@ package environment
 import (
 	"context"
 	"encoding/json"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	v1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 )
 
 const (
 	errGetEnvironmentConfig = "failed to get config set from reference"
+	errGetConfigMap         = "failed to get ConfigMap from reference"
+	errGetSecret            = "failed to get Secret from reference"
+	errGetObject            = "failed to get object from reference"
 	errMergeData            = "failed to merge data"
 
+	errFmtSecretsNotEnabled = "cannot read environment data from Secret %q: reading environment data from Secrets is not enabled"
+	errFmtUndeclaredSource  = "cannot read environment data from %s %q: it does not match any environment source declared by the composition"
+	errFmtGetFieldPath      = "failed to get field path %q of %s %q"
+	errFmtNotAnObject       = "field path %q of %s %q is not an object"
+
 	environmentGroup   = "internal.crossplane.io"
 	environmentVersion = "v1alpha1"
 	environmentKind    = "Environment"
+
+	// redacted replaces values read from Secrets in redacted messages.
+	redacted = "[REDACTED]"
 )
 
 // NewNilEnvironmentFetcher creates a new NilEnvironmentFetcher.
@@ -50,103 +67,363 @@ func NewNilEnvironmentFetcher() *NilEnvironmentFetcher {
 type NilEnvironmentFetcher struct{}
 
 // Fetch always returns nil.
-func (f *NilEnvironmentFetcher) Fetch(_ context.Context, _ resource.Composite, _ bool) (*Environment, error) {
+func (f *NilEnvironmentFetcher) Fetch(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) (*Environment, error) {
 	return nil, nil
 }
 
+// An APIEnvironmentFetcherOption configures an APIEnvironmentFetcher.
+type APIEnvironmentFetcherOption func(f *APIEnvironmentFetcher)
+
+// WithSecretSources allows an APIEnvironmentFetcher to read environment data
+// from Secrets. Values read from Secrets are redacted by the fetched
+// Environment's Redact and RedactEvent methods.
+func WithSecretSources() APIEnvironmentFetcherOption {
+	return func(f *APIEnvironmentFetcher) {
+		f.secrets = true
+	}
+}
+
 // NewAPIEnvironmentFetcher creates a new APIEnvironmentFetcher
-func NewAPIEnvironmentFetcher(kube client.Client) *APIEnvironmentFetcher {
-	return &APIEnvironmentFetcher{
+func NewAPIEnvironmentFetcher(kube client.Client, opts ...APIEnvironmentFetcherOption) *APIEnvironmentFetcher {
+	f := &APIEnvironmentFetcher{
 		kube: kube,
 	}
+	for _, fn := range opts {
+		fn(f)
+	}
+	return f
 }
 
 // Environment defines unstructured data.
 type Environment struct {
 	unstructured.Unstructured
+
+	// sensitive values were read from Secrets. They're sorted longest first.
+	sensitive []string
+
+	// sources of each top-level key, in the order they were merged.
+	sources map[string][]source
+}
+
+// A Debug view of an Environment.
+type Debug struct {
+	// Data is the merged environment data. Values read from Secrets are
+	// redacted.
+	Data map[string]interface{} `json:"data,omitempty"`
+
+	// Sources of each top-level key of the environment data, in the order
+	// they were merged. Later sources take precedence.
+	Sources map[string][]corev1.ObjectReference `json:"sources,omitempty"`
+}
+
+// Debug returns a view of the environment that shows which object each
+// top-level key was read from. Keys whose value was last read from a Secret
+// are redacted.
+func (e *Environment) Debug() *Debug {
+	if e == nil {
+		return nil
+	}
+	d := &Debug{
+		Data:    make(map[string]interface{}, len(e.sources)),
+		Sources: make(map[string][]corev1.ObjectReference, len(e.sources)),
+	}
+	for k, srcs := range e.sources {
+		refs := make([]corev1.ObjectReference, len(srcs))
+		for i := range srcs {
+			refs[i] = srcs[i].ref
+		}
+		d.Sources[k] = refs
+		d.Data[k] = e.Object[k]
+		if srcs[len(srcs)-1].sensitive {
+			d.Data[k] = redacted
+		}
+	}
+	return d
+}
+
+// Redact returns the supplied error with any values read from Secrets
+// replaced. Errors that may include environment data should be redacted
+// before they're emitted, for example as events or status conditions.
+func (e *Environment) Redact(err error) error {
+	if e == nil || err == nil || len(e.sensitive) == 0 {
+		return err
+	}
+	msg := e.redact(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+	return errors.New(msg)
+}
+
+// RedactEvent returns the supplied event with any values read from Secrets
+// replaced.
+func (e *Environment) RedactEvent(ev event.Event) event.Event {
+	if e == nil || len(e.sensitive) == 0 {
+		return ev
+	}
+	ev.Message = e.redact(ev.Message)
+	return ev
+}
+
+func (e *Environment) redact(msg string) string {
+	oldnew := make([]string, 0, 2*len(e.sensitive))
+	for _, v := range e.sensitive {
+		oldnew = append(oldnew, v, redacted)
+	}
+	return strings.NewReplacer(oldnew...).Replace(msg)
 }
 
 // APIEnvironmentFetcher fetches the Environments referenced by a composite
 // resoruce using a kube client.
 type APIEnvironmentFetcher struct {
 	kube client.Client
+
+	// secrets is true if environment data may be read from Secrets.
+	secrets bool
 }
 
-// Fetch all EnvironmentConfigs referenced by cr and merge their `.Data` into a
-// single Environment.
+// Fetch all EnvironmentConfigs, and any other objects, referenced by cr and
+// merge their data into a single Environment. Objects other than
+// EnvironmentConfigs are only read if they match an environment source
+// declared by the supplied CompositionRevision.
 //
 // Note: The `.Data` path is trimmed from the result so its necessary to include
 // it in patches.
-func (f *APIEnvironmentFetcher) Fetch(ctx context.Context, cr resource.Composite, required bool) (*Environment, error) {
-	var env *Environment
-
+func (f *APIEnvironmentFetcher) Fetch(ctx context.Context, cr resource.Composite, rev *v1.CompositionRevision) (*Environment, error) {
 	// Return an empty environment if the XR references no EnvironmentConfigs.
 	if len(cr.GetEnvironmentConfigReferences()) == 0 {
-		env = &Environment{
-			Unstructured: unstructured.Unstructured{
-				Object: map[string]interface{}{},
-			},
-		}
-	} else {
-		var err error
-		env, err = f.fetchEnvironment(ctx, cr, required)
-		if err != nil {
-			return nil, err
-		}
+		return NewEnvironment()
 	}
 
-	// GVK is necessary for patching because it uses unstructured conversion
-	env.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   environmentGroup,
-		Version: environmentVersion,
-		Kind:    environmentKind,
-	})
-	return env, nil
+	srcs, err := f.fetchSources(ctx, cr, rev)
+	if err != nil {
+		return nil, err
+	}
+	return newEnvironment(srcs...), nil
 }
 
-func (f *APIEnvironmentFetcher) fetchEnvironment(ctx context.Context, cr resource.Composite, required bool) (*Environment, error) {
+// A source of environment data.
+type source struct {
+	data map[string]interface{}
+
+	// ref to the object the data was read from.
+	ref corev1.ObjectReference
+
+	// sensitive data was read from a Secret.
+	sensitive bool
+}
+
+func (f *APIEnvironmentFetcher) fetchSources(ctx context.Context, cr resource.Composite, rev *v1.CompositionRevision) ([]source, error) {
+	var declared []v1.EnvironmentSource
+	if rev != nil && rev.Spec.Environment != nil {
+		declared = rev.Spec.Environment.EnvironmentConfigs
+	}
+	required := rev != nil && rev.Spec.Environment.IsRequired()
+
 	refs := cr.GetEnvironmentConfigReferences()
-	loadedConfigs := []v1alpha1.EnvironmentConfig{}
+	srcs := make([]source, 0, len(refs))
 	for _, ref := range refs {
-		config := v1alpha1.EnvironmentConfig{}
-		nn := types.NamespacedName{
-			Name: ref.Name,
+		// The XR's references may be edited by anyone who can edit the XR,
+		// so we only read objects the composition declared as sources.
+		if !isDeclared(ref, declared) {
+			return nil, errors.Errorf(errFmtUndeclaredSource, ref.Kind, ref.Name)
 		}
-		err := f.kube.Get(ctx, nn, &config)
+		if isSecret(ref) && !f.secrets {
+			return nil, errors.Errorf(errFmtSecretsNotEnabled, ref.Name)
+		}
+		src, err := f.fetchSource(ctx, ref)
+		src.ref = ref
 		if err != nil {
 			// skip if resolution policy is optional
 			if required {
-				return nil, errors.Wrap(err, errGetEnvironmentConfig)
+				return nil, err
 			}
 			continue
 		}
-		loadedConfigs = append(loadedConfigs, config)
+		if src.data == nil {
+			continue
+		}
+		srcs = append(srcs, src)
+	}
+	return srcs, nil
+}
+
+func (f *APIEnvironmentFetcher) fetchSource(ctx context.Context, ref corev1.ObjectReference) (source, error) {
+	nn := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	switch {
+	case isEnvironmentConfig(ref):
+		config := &v1alpha1.EnvironmentConfig{}
+		if err := f.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, config); err != nil {
+			return source{}, errors.Wrap(err, errGetEnvironmentConfig)
+		}
+		if config.Data == nil {
+			return source{}, nil
+		}
+		data, err := unmarshalData(config.Data)
+		return source{data: data}, errors.Wrap(err, errMergeData)
+
+	case isConfigMap(ref):
+		cm := &corev1.ConfigMap{}
+		if err := f.kube.Get(ctx, nn, cm); err != nil {
+			return source{}, errors.Wrap(err, errGetConfigMap)
+		}
+		data := make(map[string]interface{}, len(cm.Data))
+		for k, v := range cm.Data {
+			data[k] = v
+		}
+		return source{data: data}, nil
+
+	case isSecret(ref):
+		s := &corev1.Secret{}
+		if err := f.kube.Get(ctx, nn, s); err != nil {
+			return source{}, errors.Wrap(err, errGetSecret)
+		}
+		data := make(map[string]interface{}, len(s.Data))
+		for k, v := range s.Data {
+			data[k] = string(v)
+		}
+		return source{data: data, sensitive: true}, nil
 	}
 
-	mergedData, err := mergeEnvironmentData(loadedConfigs)
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(ref.APIVersion)
+	u.SetKind(ref.Kind)
+	if err := f.kube.Get(ctx, nn, u); err != nil {
+		return source{}, errors.Wrap(err, errGetObject)
+	}
+	v, err := fieldpath.Pave(u.Object).GetValue(ref.FieldPath)
 	if err != nil {
-		return nil, errors.Wrap(err, errMergeData)
+		return source{}, errors.Wrapf(err, errFmtGetFieldPath, ref.FieldPath, ref.Kind, ref.Name)
 	}
-	return &Environment{
-		unstructured.Unstructured{
-			Object: mergedData,
-		},
-	}, nil
+	data, ok := v.(map[string]interface{})
+	if !ok {
+		return source{}, errors.Errorf(errFmtNotAnObject, ref.FieldPath, ref.Kind, ref.Name)
+	}
+	return source{data: data}, nil
 }
 
-func mergeEnvironmentData(configs []v1alpha1.EnvironmentConfig) (map[string]interface{}, error) {
-	merged := map[string]interface{}{}
+// isDeclared returns true if the supplied reference matches one of the supplied
+// environment sources. A reference matches a source if it's to the kind of
+// object the source reads from, in the namespace and at the field path the
+// source reads from. A reference matches a source of type Reference only if it
+// is to the named object. References to EnvironmentConfigs match regardless of
+// the declared sources; composite resources have always been able to
+// reference them directly.
+func isDeclared(ref corev1.ObjectReference, declared []v1.EnvironmentSource) bool {
+	if isEnvironmentConfig(ref) {
+		return true
+	}
+	for _, src := range declared {
+		want := sourceReference(src.From)
+		if ref.APIVersion != want.APIVersion || ref.Kind != want.Kind || ref.Namespace != want.Namespace || ref.FieldPath != want.FieldPath {
+			continue
+		}
+		if src.Type == v1.EnvironmentSourceTypeReference && (src.Ref == nil || src.Ref.Name != ref.Name) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// isEnvironmentConfig returns true if the supplied reference is to an
+// EnvironmentConfig. References without a kind are to EnvironmentConfigs.
+func isEnvironmentConfig(ref corev1.ObjectReference) bool {
+	if ref.Kind == "" {
+		return true
+	}
+	return ref.GroupVersionKind().GroupKind() == v1alpha1.EnvironmentConfigGroupVersionKind.GroupKind()
+}
+
+// isConfigMap returns true if the supplied reference is to a ConfigMap.
+func isConfigMap(ref corev1.ObjectReference) bool {
+	return ref.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("ConfigMap")
+}
+
+// isSecret returns true if the supplied reference is to a Secret.
+func isSecret(ref corev1.ObjectReference) bool {
+	return ref.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Secret")
+}
+
+// NewEnvironment merges the `.Data` of the supplied EnvironmentConfigs, in
+// order, into a single Environment. Later EnvironmentConfigs take precedence.
+func NewEnvironment(configs ...v1alpha1.EnvironmentConfig) (*Environment, error) {
+	srcs := make([]source, 0, len(configs))
 	for _, e := range configs {
 		if e.Data == nil {
 			continue
 		}
 		data, err := unmarshalData(e.Data)
 		if err != nil {
-			return nil, err
+			return nil, errors.Wrap(err, errMergeData)
+		}
+		srcs = append(srcs, source{data: data, ref: corev1.ObjectReference{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       v1alpha1.EnvironmentConfigKind,
+			Name:       e.GetName(),
+		}})
+	}
+	return newEnvironment(srcs...), nil
+}
+
+// newEnvironment merges the data of the supplied sources, in order, into a
+// single Environment. Later sources take precedence.
+func newEnvironment(srcs ...source) *Environment {
+	env := &Environment{
+		Unstructured: unstructured.Unstructured{
+			Object: mergeEnvironmentData(srcs),
+		},
+		sensitive: sensitiveValues(srcs),
+		sources:   provenance(srcs),
+	}
+
+	// GVK is necessary for patching because it uses unstructured conversion
+	env.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   environmentGroup,
+		Version: environmentVersion,
+		Kind:    environmentKind,
+	})
+
+	return env
+}
+
+func mergeEnvironmentData(srcs []source) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for _, s := range srcs {
+		merged = mergeMaps(merged, s.data)
+	}
+	return merged
+}
+
+// sensitiveValues returns the non-empty values read from Secrets, longest
+// first so that no value is only partially redacted.
+func sensitiveValues(srcs []source) []string {
+	var values []string
+	for _, s := range srcs {
+		if !s.sensitive {
+			continue
+		}
+		for _, v := range s.data {
+			if v, ok := v.(string); ok && v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	return values
+}
+
+// provenance returns the sources of each top-level key of the supplied
+// sources' data, in order.
+func provenance(srcs []source) map[string][]source {
+	p := map[string][]source{}
+	for _, s := range srcs {
+		for k := range s.data {
+			// Don't retain the source's data.
+			p[k] = append(p[k], source{ref: s.ref, sensitive: s.sensitive})
 		}
-		merged = mergeMaps(merged, data)
 	}
-	return merged, nil
+	return p
 }
 
 func unmarshalData(data map[string]extv1.JSON) (map[string]interface{}, error) {
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -29,14 +30,17 @@ import (
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	v1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 )
 
 func TestFetch(t *testing.T) {
 	errBoom := errors.New("boom")
+	optional := xpv1.ResolutionPolicyOptional
 
 	makeJSON := func(m map[string]interface{}) map[string]extv1.JSON {
 		raw, err := json.Marshal(m)
@@ -124,8 +128,25 @@ func TestFetch(t *testing.T) {
 		},
 	}
 
+	// The sources the composition declares, which the objects referenced by
+	// the test cases match.
+	declared := []v1.EnvironmentSource{
+		{Type: v1.EnvironmentSourceTypeSelector, From: &v1.EnvironmentSourceFrom{Type: v1.EnvironmentSourceFromTypeConfigMap, Namespace: pointer.String("cool-namespace")}},
+		{Type: v1.EnvironmentSourceTypeReference, Ref: &v1.EnvironmentSourceReference{Name: "s"}, From: &v1.EnvironmentSourceFrom{Type: v1.EnvironmentSourceFromTypeSecret, Namespace: pointer.String("cool-namespace")}},
+		{Type: v1.EnvironmentSourceTypeReference, Ref: &v1.EnvironmentSourceReference{Name: "o"}, From: &v1.EnvironmentSourceFrom{Type: v1.EnvironmentSourceFromTypeObject, APIVersion: pointer.String("example.org/v1"), Kind: pointer.String("CoolObject"), FieldPath: pointer.String("status.atProvider")}},
+		{Type: v1.EnvironmentSourceTypeReference, Ref: &v1.EnvironmentSourceReference{Name: "o"}, From: &v1.EnvironmentSourceFrom{Type: v1.EnvironmentSourceFromTypeObject, APIVersion: pointer.String("example.org/v1"), Kind: pointer.String("CoolObject"), FieldPath: pointer.String("status.accountId")}},
+	}
+	revision := func(required bool) *v1.CompositionRevision {
+		rev := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{Environment: &v1.EnvironmentConfiguration{EnvironmentConfigs: declared}}}
+		if !required {
+			rev.Spec.Environment.Policy = &xpv1.Policy{Resolution: &optional}
+		}
+		return rev
+	}
+
 	type args struct {
 		kube     client.Client
+		opts     []APIEnvironmentFetcherOption
 		cr       *fake.Composite
 		required *bool
 	}
@@ -199,6 +220,167 @@ func TestFetch(t *testing.T) {
 				env: makeEnvironment(testDataMerged),
 			},
 		},
+		"MergeConfigMapOverEnvironmentConfig": {
+			reason: "It should merge the data of a ConfigMap over the data of an EnvironmentConfig listed before it.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, o client.Object) error {
+						switch o := o.(type) {
+						case *v1alpha1.EnvironmentConfig:
+							o.Data = makeJSON(testData1)
+						case *corev1.ConfigMap:
+							if key.Namespace != "cool-namespace" {
+								t.Errorf("Get(...): want namespace cool-namespace, got %q", key.Namespace)
+							}
+							o.Data = map[string]string{"str": "from configmap", "region": "us-cool-1"}
+						}
+						return nil
+					},
+				},
+				cr: composite(
+					withEnvironmentRefs(
+						corev1.ObjectReference{Name: "a"},
+						corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "cool-namespace", Name: "b"},
+					),
+				),
+			},
+			want: want{
+				env: makeEnvironment(map[string]interface{}{
+					"int":    int(1),
+					"bool":   true,
+					"str":    "from configmap",
+					"region": "us-cool-1",
+					"array": []int{
+						1, 2, 3, 4,
+					},
+					"test": map[string]interface{}{
+						"foo": "bar",
+						"complex": map[string]interface{}{
+							"data": "val",
+						},
+					},
+				}),
+			},
+		},
+		"ErrorOnSecretsNotEnabled": {
+			reason: "It should return an error if a Secret is referenced but reading Secrets is not enabled.",
+			args: args{
+				cr: composite(
+					withEnvironmentRefs(
+						corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: "cool-namespace", Name: "s"},
+					),
+				),
+				required: pointer.Bool(false),
+			},
+			want: want{
+				err: errors.Errorf(errFmtSecretsNotEnabled, "s"),
+			},
+		},
+		"SensitiveSecretData": {
+			reason: "It should read the data of a Secret, and record it as sensitive.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						o.(*corev1.Secret).Data = map[string][]byte{"password": []byte("hunter2"), "username": []byte("admin")}
+						return nil
+					}),
+				},
+				opts: []APIEnvironmentFetcherOption{WithSecretSources()},
+				cr: composite(
+					withEnvironmentRefs(
+						corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: "cool-namespace", Name: "s"},
+					),
+				),
+			},
+			want: want{
+				env: func() *Environment {
+					e := makeEnvironment(map[string]interface{}{"password": "hunter2", "username": "admin"})
+					e.sensitive = []string{"hunter2", "admin"}
+					return e
+				}(),
+			},
+		},
+		"ObjectFieldPath": {
+			reason: "It should read the object at the field path of an arbitrary object.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						o.(*unstructured.Unstructured).Object["status"] = map[string]interface{}{
+							"atProvider": map[string]interface{}{"accountId": "123"},
+						}
+						return nil
+					}),
+				},
+				cr: composite(
+					withEnvironmentRefs(
+						corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "CoolObject", Name: "o", FieldPath: "status.atProvider"},
+					),
+				),
+			},
+			want: want{
+				env: makeEnvironment(map[string]interface{}{"accountId": "123"}),
+			},
+		},
+		"ErrorOnObjectFieldPathNotAnObject": {
+			reason: "It should return an error if the field path of an arbitrary object is not an object.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						o.(*unstructured.Unstructured).Object["status"] = map[string]interface{}{"accountId": "123"}
+						return nil
+					}),
+				},
+				cr: composite(
+					withEnvironmentRefs(
+						corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "CoolObject", Name: "o", FieldPath: "status.accountId"},
+					),
+				),
+			},
+			want: want{
+				err: errors.Errorf(errFmtNotAnObject, "status.accountId", "CoolObject", "o"),
+			},
+		},
+		"ErrorOnUndeclaredNamespace": {
+			reason: "It should return an error if a ConfigMap is referenced in a namespace no declared source reads from.",
+			args: args{
+				cr: composite(
+					withEnvironmentRefs(
+						corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "b"},
+					),
+				),
+				required: pointer.Bool(false),
+			},
+			want: want{
+				err: errors.Errorf(errFmtUndeclaredSource, "ConfigMap", "b"),
+			},
+		},
+		"ErrorOnUndeclaredName": {
+			reason: "It should return an error if a Secret is referenced that no declared source references by name.",
+			args: args{
+				opts: []APIEnvironmentFetcherOption{WithSecretSources()},
+				cr: composite(
+					withEnvironmentRefs(
+						corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: "cool-namespace", Name: "other"},
+					),
+				),
+			},
+			want: want{
+				err: errors.Errorf(errFmtUndeclaredSource, "Secret", "other"),
+			},
+		},
+		"ErrorOnUndeclaredFieldPath": {
+			reason: "It should return an error if an object is referenced at a field path no declared source reads from.",
+			args: args{
+				cr: composite(
+					withEnvironmentRefs(
+						corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "CoolObject", Name: "o", FieldPath: "spec"},
+					),
+				),
+			},
+			want: want{
+				err: errors.Errorf(errFmtUndeclaredSource, "CoolObject", "o"),
+			},
+		},
 		"ErrorOnKubeGetError": {
 			reason: "It should return an error if getting a EnvironmentConfig from a reference fails",
 			args: args{
@@ -236,19 +418,142 @@ func TestFetch(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			f := NewAPIEnvironmentFetcher(tc.args.kube)
+			f := NewAPIEnvironmentFetcher(tc.args.kube, tc.args.opts...)
 			required := true
 			if tc.args.required != nil {
 				required = *tc.args.required
 			}
-			got, err := f.Fetch(context.Background(), tc.args.cr, required)
+			got, err := f.Fetch(context.Background(), tc.args.cr, revision(required))
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.env, got, test.EquateErrors()); diff != "" {
+			if diff := cmp.Diff(tc.want.env, got, cmp.AllowUnexported(Environment{}), cmpopts.IgnoreFields(Environment{}, "sources")); diff != "" {
 				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
 			}
 		})
 	}
 }
+
+func TestRedact(t *testing.T) {
+	type args struct {
+		env *Environment
+		err error
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NilEnvironment": {
+			reason: "A nil Environment should not redact anything.",
+			args: args{
+				err: errors.New("cannot use hunter2"),
+			},
+			want: want{
+				err: errors.New("cannot use hunter2"),
+			},
+		},
+		"RedactSensitiveValues": {
+			reason: "Values read from Secrets should be redacted, longest first.",
+			args: args{
+				env: &Environment{sensitive: []string{"hunter22", "hunter2"}},
+				err: errors.New("cannot use hunter22 or hunter2"),
+			},
+			want: want{
+				err: errors.New("cannot use [REDACTED] or [REDACTED]"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.env.Redact(tc.args.err)
+			if diff := cmp.Diff(tc.want.err, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRedact(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDebug(t *testing.T) {
+	config := corev1.ObjectReference{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: v1alpha1.EnvironmentConfigKind, Name: "cool-config"}
+	secret := corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: "cool-namespace", Name: "cool-secret"}
+	cm := corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "cool-namespace", Name: "cool-cm"}
+
+	type args struct {
+		env *Environment
+	}
+	type want struct {
+		debug *Debug
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NilEnvironment": {
+			reason: "A nil Environment should have no debug view.",
+			args:   args{},
+			want:   want{},
+		},
+		"EmptyEnvironment": {
+			reason: "An empty Environment should have an empty debug view.",
+			args: args{
+				env: newEnvironment(),
+			},
+			want: want{
+				debug: &Debug{
+					Data:    map[string]interface{}{},
+					Sources: map[string][]corev1.ObjectReference{},
+				},
+			},
+		},
+		"MergedSources": {
+			reason: "The sources of each top-level key should be listed in the order they were merged, and keys last read from a Secret should be redacted.",
+			args: args{
+				env: newEnvironment(
+					source{ref: config, data: map[string]interface{}{
+						"region":   "us-east-1",
+						"db":       map[string]interface{}{"host": "db.example.org"},
+						"password": "default",
+					}},
+					source{ref: secret, sensitive: true, data: map[string]interface{}{
+						"password": "hunter2",
+					}},
+					source{ref: cm, data: map[string]interface{}{
+						"db": map[string]interface{}{"port": "5432"},
+					}},
+				),
+			},
+			want: want{
+				debug: &Debug{
+					Data: map[string]interface{}{
+						"region":   "us-east-1",
+						"db":       map[string]interface{}{"host": "db.example.org", "port": "5432"},
+						"password": "[REDACTED]",
+					},
+					Sources: map[string][]corev1.ObjectReference{
+						"region":   {config},
+						"db":       {config, cm},
+						"password": {config, secret},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.env.Debug()
+			if diff := cmp.Diff(tc.want.debug, got); diff != "" {
+				t.Errorf("\n%s\nDebug(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
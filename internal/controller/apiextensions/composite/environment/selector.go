@@ -24,7 +24,11 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
@@ -38,6 +42,11 @@ const (
 	errFmtReferenceEnvironmentConfig   = "failed to build reference at index %d"
 	errFmtResolveLabelValue            = "failed to resolve value for label at index %d"
 	errListEnvironmentConfigs          = "failed to list environments"
+	errInvalidSelector                 = "invalid selector"
+	errNoClaim                         = "composite resource is not bound to a claim"
+	errFmtGetNamespace                 = "failed to get namespace %s"
+	errFmtMissingNamespaceLabel        = "namespace %s has no label %s"
+	errFmtListObjects                  = "failed to list %s objects"
 	errFmtInvalidEnvironmentSourceType = "invalid source type '%s'"
 	errFmtInvalidLabelMatcherType      = "invalid label matcher type '%s'"
 	errFmtRequiredField                = "%s is required by type %s"
@@ -85,14 +94,14 @@ func (s *APIEnvironmentSelector) SelectEnvironment(ctx context.Context, cr resou
 		case v1.EnvironmentSourceTypeReference:
 			refs = append(
 				refs,
-				s.buildEnvironmentConfigRefFromRef(src.Ref),
+				s.buildEnvironmentConfigRefFromRef(src.Ref, src.From),
 			)
 		case v1.EnvironmentSourceTypeSelector:
-			ec, err := s.lookUpConfigs(ctx, cr, src.Selector.MatchLabels)
+			ec, err := s.lookUpConfigs(ctx, cr, src.From, src.Selector)
 			if err != nil {
 				return errors.Wrapf(err, errFmtReferenceEnvironmentConfig, i)
 			}
-			r, err := s.buildEnvironmentConfigRefFromSelector(ec, src.Selector)
+			r, err := s.buildEnvironmentConfigRefFromSelector(ec, src.From, src.Selector)
 			if err != nil {
 				return errors.Wrapf(err, errFmtReferenceEnvironmentConfig, i)
 			}
@@ -105,88 +114,170 @@ func (s *APIEnvironmentSelector) SelectEnvironment(ctx context.Context, cr resou
 	return nil
 }
 
-func (s *APIEnvironmentSelector) buildEnvironmentConfigRefFromRef(ref *v1.EnvironmentSourceReference) corev1.ObjectReference {
+// sourceReference returns a reference, without a name, to the kind of object
+// the supplied source reads environment data from.
+func sourceReference(from *v1.EnvironmentSourceFrom) corev1.ObjectReference {
+	switch from.GetType() {
+	case v1.EnvironmentSourceFromTypeConfigMap:
+		return corev1.ObjectReference{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Namespace:  pointer.StringDeref(from.Namespace, ""),
+		}
+	case v1.EnvironmentSourceFromTypeSecret:
+		return corev1.ObjectReference{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Namespace:  pointer.StringDeref(from.Namespace, ""),
+		}
+	case v1.EnvironmentSourceFromTypeObject:
+		return corev1.ObjectReference{
+			Kind:       pointer.StringDeref(from.Kind, ""),
+			APIVersion: pointer.StringDeref(from.APIVersion, ""),
+			Namespace:  pointer.StringDeref(from.Namespace, ""),
+			FieldPath:  pointer.StringDeref(from.FieldPath, ""),
+		}
+	case v1.EnvironmentSourceFromTypeEnvironmentConfig:
+	}
 	return corev1.ObjectReference{
-		Name:       ref.Name,
 		Kind:       v1alpha1.EnvironmentConfigKind,
 		APIVersion: v1alpha1.SchemeGroupVersion.String(),
 	}
 }
 
-func (s *APIEnvironmentSelector) lookUpConfigs(ctx context.Context, cr resource.Composite, ml []v1.EnvironmentSourceSelectorLabelMatcher) (*v1alpha1.EnvironmentConfigList, error) {
-	matchLabels := make(client.MatchingLabels, len(ml))
-	for i, m := range ml {
-		val, err := ResolveLabelValue(m, cr)
+func (s *APIEnvironmentSelector) buildEnvironmentConfigRefFromRef(ref *v1.EnvironmentSourceReference, from *v1.EnvironmentSourceFrom) corev1.ObjectReference {
+	r := sourceReference(from)
+	r.Name = ref.Name
+	return r
+}
+
+func (s *APIEnvironmentSelector) lookUpConfigs(ctx context.Context, cr resource.Composite, from *v1.EnvironmentSourceFrom, selector *v1.EnvironmentSourceSelector) ([]kunstructured.Unstructured, error) {
+	ml := make(map[string]string, len(selector.MatchLabels))
+	for i, m := range selector.MatchLabels {
+		val, err := s.resolveLabelValue(ctx, m, cr)
 		if err != nil {
 			return nil, errors.Wrapf(err, errFmtResolveLabelValue, i)
 		}
-		matchLabels[m.Key] = val
+		ml[m.Key] = val
+	}
+	sel, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: ml, MatchExpressions: selector.MatchExpressions})
+	if err != nil {
+		return nil, errors.Wrap(err, errInvalidSelector)
 	}
-	res := &v1alpha1.EnvironmentConfigList{}
-	if err := s.kube.List(ctx, res, matchLabels); err != nil {
-		return nil, errors.Wrap(err, errListEnvironmentConfigs)
+	matchLabels := client.MatchingLabelsSelector{Selector: sel}
+
+	ref := sourceReference(from)
+	if isEnvironmentConfig(ref) {
+		res := &v1alpha1.EnvironmentConfigList{}
+		if err := s.kube.List(ctx, res, matchLabels); err != nil {
+			return nil, errors.Wrap(err, errListEnvironmentConfigs)
+		}
+		objs := make([]kunstructured.Unstructured, len(res.Items))
+		for i := range res.Items {
+			m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&res.Items[i])
+			if err != nil {
+				return nil, err
+			}
+			objs[i] = kunstructured.Unstructured{Object: m}
+		}
+		return objs, nil
+	}
+
+	res := &kunstructured.UnstructuredList{}
+	res.SetAPIVersion(ref.APIVersion)
+	res.SetKind(ref.Kind + "List")
+	opts := []client.ListOption{matchLabels}
+	if ref.Namespace != "" {
+		opts = append(opts, client.InNamespace(ref.Namespace))
 	}
-	return res, nil
+	if err := s.kube.List(ctx, res, opts...); err != nil {
+		return nil, errors.Wrapf(err, errFmtListObjects, ref.Kind)
+	}
+	return res.Items, nil
+}
+
+// resolveLabelValue resolves the value of the supplied label matcher. Values
+// drawn from the namespace of the composite resource's claim are resolved
+// here, because they may require reading the namespace from the API server.
+func (s *APIEnvironmentSelector) resolveLabelValue(ctx context.Context, m v1.EnvironmentSourceSelectorLabelMatcher, cr resource.Composite) (string, error) {
+	switch m.Type { //nolint:exhaustive // Other types are resolved by ResolveLabelValue.
+	case v1.EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespace:
+		ref := cr.GetClaimReference()
+		if ref == nil {
+			return "", errors.New(errNoClaim)
+		}
+		return ref.Namespace, nil
+	case v1.EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespaceLabel:
+		if m.NamespaceLabelKey == nil {
+			return "", errors.Errorf(errFmtRequiredField, "namespaceLabelKey", string(v1.EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespaceLabel))
+		}
+		ref := cr.GetClaimReference()
+		if ref == nil {
+			return "", errors.New(errNoClaim)
+		}
+		ns := &corev1.Namespace{}
+		if err := s.kube.Get(ctx, types.NamespacedName{Name: ref.Namespace}, ns); err != nil {
+			return "", errors.Wrapf(err, errFmtGetNamespace, ref.Namespace)
+		}
+		val, ok := ns.GetLabels()[*m.NamespaceLabelKey]
+		if !ok {
+			return "", errors.Errorf(errFmtMissingNamespaceLabel, ref.Namespace, *m.NamespaceLabelKey)
+		}
+		return val, nil
+	}
+	return ResolveLabelValue(m, cr)
 }
 
-func (s *APIEnvironmentSelector) buildEnvironmentConfigRefFromSelector(cl *v1alpha1.EnvironmentConfigList, selector *v1.EnvironmentSourceSelector) ([]corev1.ObjectReference, error) {
-	ec := make([]v1alpha1.EnvironmentConfig, 0)
+func (s *APIEnvironmentSelector) buildEnvironmentConfigRefFromSelector(objs []kunstructured.Unstructured, from *v1.EnvironmentSourceFrom, selector *v1.EnvironmentSourceSelector) ([]corev1.ObjectReference, error) {
+	selected := make([]kunstructured.Unstructured, 0)
 
-	if len(cl.Items) == 0 {
+	if len(objs) == 0 {
 		return []corev1.ObjectReference{}, nil
 	}
 
 	switch selector.Mode {
 	case v1.EnvironmentSourceSelectorSingleMode:
-		switch len(cl.Items) {
+		switch len(objs) {
 		case 1:
-			ec = append(ec, cl.Items[0])
+			selected = append(selected, objs[0])
 		default:
-			return nil, errors.Errorf(errFmtFoundMultipleInSingleMode, len(cl.Items))
+			return nil, errors.Errorf(errFmtFoundMultipleInSingleMode, len(objs))
 		}
 	case v1.EnvironmentSourceSelectorMultiMode:
-		err := sortConfigs(cl.Items, selector.SortByFieldPath)
+		err := sortConfigs(objs, selector.SortByFieldPath)
 		if err != nil {
 			return nil, err
 		}
 
-		if selector.MaxMatch != nil {
-			ec = append(ec, cl.Items[:*selector.MaxMatch]...)
+		if selector.MaxMatch != nil && *selector.MaxMatch < uint64(len(objs)) {
+			selected = append(selected, objs[:*selector.MaxMatch]...)
 			break
 		}
-		ec = append(ec, cl.Items...)
+		selected = append(selected, objs...)
 
 	default:
 		// should never happen
 		return nil, errors.Errorf(errFmtUnknownSelectorMode, selector.Mode)
 	}
 
-	envConfigs := make([]corev1.ObjectReference, len(ec))
-	for i, v := range ec {
-		envConfigs[i] = corev1.ObjectReference{
-			Name:       v.Name,
-			Kind:       v1alpha1.EnvironmentConfigKind,
-			APIVersion: v1alpha1.SchemeGroupVersion.String(),
-		}
+	envConfigs := make([]corev1.ObjectReference, len(selected))
+	for i, v := range selected {
+		envConfigs[i] = sourceReference(from)
+		envConfigs[i].Name = v.GetName()
 	}
 
 	return envConfigs, nil
 }
 
-func sortConfigs(ec []v1alpha1.EnvironmentConfig, f string) error { //nolint:gocyclo // TODO(phisco): refactor
+func sortConfigs(ec []kunstructured.Unstructured, f string) error { //nolint:gocyclo // TODO(phisco): refactor
 	p := make([]struct {
-		ec  v1alpha1.EnvironmentConfig
+		ec  kunstructured.Unstructured
 		val any
 	}, len(ec))
 
 	var valsKind reflect.Kind
 	for i := 0; i < len(ec); i++ {
-		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&ec[i])
-		if err != nil {
-			return err
-		}
-
-		val, err := fieldpath.Pave(m).GetValue(f)
+		val, err := fieldpath.Pave(ec[i].Object).GetValue(f)
 		if err != nil {
 			return err
 		}
@@ -27,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -66,6 +67,11 @@ func TestSelect(t *testing.T) {
 			cr.SetName(name)
 		}
 	}
+	withClaimRef := func(namespace string) compositeModifier {
+		return func(cr *fake.Composite) {
+			cr.SetClaimReference(&corev1.ObjectReference{Namespace: namespace, Name: "claim"})
+		}
+	}
 	withEnvironmentRefs := func(refs ...corev1.ObjectReference) compositeModifier {
 		return func(cr *fake.Composite) {
 			cr.SetEnvironmentConfigReferences(refs)
@@ -161,6 +167,96 @@ func TestSelect(t *testing.T) {
 				),
 			},
 		},
+		"RefForConfigMapRef": {
+			reason: "It should create a reference to a ConfigMap for a named reference to a ConfigMap in the config.",
+			args: args{
+				cr: composite(),
+				rev: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						Environment: &v1.EnvironmentConfiguration{
+							EnvironmentConfigs: []v1.EnvironmentSource{
+								{
+									Type: v1.EnvironmentSourceTypeReference,
+									Ref: &v1.EnvironmentSourceReference{
+										Name: "test",
+									},
+									From: &v1.EnvironmentSourceFrom{
+										Type:      v1.EnvironmentSourceFromTypeConfigMap,
+										Namespace: pointer.String("cool-namespace"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cr: composite(
+					withEnvironmentRefs(corev1.ObjectReference{
+						Name:       "test",
+						Namespace:  "cool-namespace",
+						Kind:       "ConfigMap",
+						APIVersion: "v1",
+					}),
+				),
+			},
+		},
+		"RefForLabelSelectedArbitraryObjects": {
+			reason: "It should create references, including the field path, for selected objects that match the labels.",
+			args: args{
+				kube: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						list := obj.(*unstructured.UnstructuredList)
+						if diff := cmp.Diff("CoolObjectList", list.GetKind()); diff != "" {
+							t.Errorf("List(...): -want kind, +got:\n%s", diff)
+						}
+						for _, name := range []string{"test-2", "test-1"} {
+							u := unstructured.Unstructured{}
+							u.SetName(name)
+							list.Items = append(list.Items, u)
+						}
+						return nil
+					}),
+				},
+				cr: composite(),
+				rev: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						Environment: &v1.EnvironmentConfiguration{
+							EnvironmentConfigs: []v1.EnvironmentSource{
+								{
+									Type: v1.EnvironmentSourceTypeSelector,
+									Selector: &v1.EnvironmentSourceSelector{
+										Mode:            v1.EnvironmentSourceSelectorMultiMode,
+										SortByFieldPath: "metadata.name",
+										MatchLabels: []v1.EnvironmentSourceSelectorLabelMatcher{
+											{
+												Type:  v1.EnvironmentSourceSelectorLabelMatcherTypeValue,
+												Key:   "foo",
+												Value: pointer.String("bar"),
+											},
+										},
+									},
+									From: &v1.EnvironmentSourceFrom{
+										Type:       v1.EnvironmentSourceFromTypeObject,
+										APIVersion: pointer.String("example.org/v1"),
+										Kind:       pointer.String("CoolObject"),
+										FieldPath:  pointer.String("status.atProvider"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cr: composite(
+					withEnvironmentRefs(
+						corev1.ObjectReference{Name: "test-1", Kind: "CoolObject", APIVersion: "example.org/v1", FieldPath: "status.atProvider"},
+						corev1.ObjectReference{Name: "test-2", Kind: "CoolObject", APIVersion: "example.org/v1", FieldPath: "status.atProvider"},
+					),
+				),
+			},
+		},
 		"RefForLabelSelectedObjects": {
 			reason: "It should create a name reference for selected EnvironmentConfigs that match the labels.",
 			args: args{
@@ -227,9 +323,10 @@ func TestSelect(t *testing.T) {
 				kube: &test.MockClient{
 					MockList: func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
 						list := obj.(*v1alpha1.EnvironmentConfigList)
-						match := opts[0].(client.MatchingLabels)
-						if match["foo"] != "test-composite" {
-							return errors.Errorf("Expected label selector value to be 'foo', but was '%s'", match["foo"])
+						lo := &client.ListOptions{}
+						opts[0].ApplyToList(lo)
+						if lo.LabelSelector.String() != "foo=test-composite" {
+							return errors.Errorf("Expected label selector to be 'foo=test-composite', but was '%s'", lo.LabelSelector)
 						}
 						list.Items = []v1alpha1.EnvironmentConfig{
 							{
@@ -1059,6 +1156,290 @@ func TestSelect(t *testing.T) {
 				err: errors.Wrap(fmt.Errorf("metadata.annotations: no such field"), "failed to build reference at index 0"),
 			},
 		},
+		"RefForObjectsSelectedByExpressions": {
+			reason: "It should create a name reference for EnvironmentConfigs whose labels satisfy the match expressions.",
+			args: args{
+				kube: &test.MockClient{
+					MockList: func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						lo := &client.ListOptions{}
+						opts[0].ApplyToList(lo)
+						if want := "foo=bar,tier in (gold,silver)"; lo.LabelSelector.String() != want {
+							return errors.Errorf("Expected label selector to be '%s', but was '%s'", want, lo.LabelSelector)
+						}
+						list := obj.(*v1alpha1.EnvironmentConfigList)
+						list.Items = []v1alpha1.EnvironmentConfig{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "test",
+								},
+							},
+						}
+						return nil
+					},
+				},
+				cr: composite(),
+				rev: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						Environment: &v1.EnvironmentConfiguration{
+							EnvironmentConfigs: []v1.EnvironmentSource{
+								{
+									Type: v1.EnvironmentSourceTypeSelector,
+									Selector: &v1.EnvironmentSourceSelector{
+										Mode: v1.EnvironmentSourceSelectorSingleMode,
+										MatchLabels: []v1.EnvironmentSourceSelectorLabelMatcher{
+											{
+												Type:  v1.EnvironmentSourceSelectorLabelMatcherTypeValue,
+												Key:   "foo",
+												Value: pointer.String("bar"),
+											},
+										},
+										MatchExpressions: []metav1.LabelSelectorRequirement{
+											{
+												Key:      "tier",
+												Operator: metav1.LabelSelectorOpIn,
+												Values:   []string{"gold", "silver"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cr: composite(
+					withEnvironmentRefs(environmentConfigRef("test")),
+				),
+			},
+		},
+		"ErrSelectOnInvalidExpression": {
+			reason: "It should return an error if a match expression is invalid.",
+			args: args{
+				kube: &test.MockClient{
+					MockList: test.NewMockListFn(nil),
+				},
+				cr: composite(),
+				rev: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						Environment: &v1.EnvironmentConfiguration{
+							EnvironmentConfigs: []v1.EnvironmentSource{
+								{
+									Type: v1.EnvironmentSourceTypeSelector,
+									Selector: &v1.EnvironmentSourceSelector{
+										Mode: v1.EnvironmentSourceSelectorSingleMode,
+										MatchExpressions: []metav1.LabelSelectorRequirement{
+											{
+												Key:      "tier",
+												Operator: metav1.LabelSelectorOpExists,
+												Values:   []string{"gold"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cr: composite(),
+				err: errors.Wrap(
+					errors.Wrap(
+						errors.New(`values: Invalid value: []string{"gold"}: values set must be empty for exists and does not exist`),
+						errInvalidSelector,
+					),
+					"failed to build reference at index 0",
+				),
+			},
+		},
+		"RefForObjectsSelectedByClaimNamespace": {
+			reason: "It should create a name reference for EnvironmentConfigs labelled with the namespace of the composite resource's claim.",
+			args: args{
+				kube: &test.MockClient{
+					MockList: func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						lo := &client.ListOptions{}
+						opts[0].ApplyToList(lo)
+						if want := "team=team-a"; lo.LabelSelector.String() != want {
+							return errors.Errorf("Expected label selector to be '%s', but was '%s'", want, lo.LabelSelector)
+						}
+						list := obj.(*v1alpha1.EnvironmentConfigList)
+						list.Items = []v1alpha1.EnvironmentConfig{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "team-a",
+								},
+							},
+						}
+						return nil
+					},
+				},
+				cr: composite(
+					withClaimRef("team-a"),
+				),
+				rev: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						Environment: &v1.EnvironmentConfiguration{
+							EnvironmentConfigs: []v1.EnvironmentSource{
+								{
+									Type: v1.EnvironmentSourceTypeSelector,
+									Selector: &v1.EnvironmentSourceSelector{
+										Mode: v1.EnvironmentSourceSelectorSingleMode,
+										MatchLabels: []v1.EnvironmentSourceSelectorLabelMatcher{
+											{
+												Type: v1.EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespace,
+												Key:  "team",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cr: composite(
+					withClaimRef("team-a"),
+					withEnvironmentRefs(environmentConfigRef("team-a")),
+				),
+			},
+		},
+		"ErrSelectByClaimNamespaceWithoutClaim": {
+			reason: "It should return an error if a label value is drawn from the claim's namespace, but there is no claim.",
+			args: args{
+				kube: &test.MockClient{
+					MockList: test.NewMockListFn(nil),
+				},
+				cr: composite(),
+				rev: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						Environment: &v1.EnvironmentConfiguration{
+							EnvironmentConfigs: []v1.EnvironmentSource{
+								{
+									Type: v1.EnvironmentSourceTypeSelector,
+									Selector: &v1.EnvironmentSourceSelector{
+										Mode: v1.EnvironmentSourceSelectorSingleMode,
+										MatchLabels: []v1.EnvironmentSourceSelectorLabelMatcher{
+											{
+												Type: v1.EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespace,
+												Key:  "team",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cr: composite(),
+				err: errors.Wrap(
+					errors.Wrapf(errors.New(errNoClaim), errFmtResolveLabelValue, 0),
+					"failed to build reference at index 0",
+				),
+			},
+		},
+		"RefForObjectsSelectedByClaimNamespaceLabel": {
+			reason: "It should create a name reference for EnvironmentConfigs labelled with a label of the composite resource's claim's namespace.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetLabels(map[string]string{"example.org/team": "a"})
+						return nil
+					}),
+					MockList: func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						lo := &client.ListOptions{}
+						opts[0].ApplyToList(lo)
+						if want := "team=a"; lo.LabelSelector.String() != want {
+							return errors.Errorf("Expected label selector to be '%s', but was '%s'", want, lo.LabelSelector)
+						}
+						list := obj.(*v1alpha1.EnvironmentConfigList)
+						list.Items = []v1alpha1.EnvironmentConfig{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "team-a",
+								},
+							},
+						}
+						return nil
+					},
+				},
+				cr: composite(
+					withClaimRef("team-a"),
+				),
+				rev: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						Environment: &v1.EnvironmentConfiguration{
+							EnvironmentConfigs: []v1.EnvironmentSource{
+								{
+									Type: v1.EnvironmentSourceTypeSelector,
+									Selector: &v1.EnvironmentSourceSelector{
+										Mode: v1.EnvironmentSourceSelectorSingleMode,
+										MatchLabels: []v1.EnvironmentSourceSelectorLabelMatcher{
+											{
+												Type:              v1.EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespaceLabel,
+												Key:               "team",
+												NamespaceLabelKey: pointer.String("example.org/team"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cr: composite(
+					withClaimRef("team-a"),
+					withEnvironmentRefs(environmentConfigRef("team-a")),
+				),
+			},
+		},
+		"ErrSelectOnMissingClaimNamespaceLabel": {
+			reason: "It should return an error if the claim's namespace doesn't have the label a label value is drawn from.",
+			args: args{
+				kube: &test.MockClient{
+					MockGet:  test.NewMockGetFn(nil),
+					MockList: test.NewMockListFn(nil),
+				},
+				cr: composite(
+					withClaimRef("team-a"),
+				),
+				rev: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						Environment: &v1.EnvironmentConfiguration{
+							EnvironmentConfigs: []v1.EnvironmentSource{
+								{
+									Type: v1.EnvironmentSourceTypeSelector,
+									Selector: &v1.EnvironmentSourceSelector{
+										Mode: v1.EnvironmentSourceSelectorSingleMode,
+										MatchLabels: []v1.EnvironmentSourceSelectorLabelMatcher{
+											{
+												Type:              v1.EnvironmentSourceSelectorLabelMatcherTypeFromClaimNamespaceLabel,
+												Key:               "team",
+												NamespaceLabelKey: pointer.String("example.org/team"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cr: composite(
+					withClaimRef("team-a"),
+				),
+				err: errors.Wrap(
+					errors.Wrapf(errors.Errorf(errFmtMissingNamespaceLabel, "team-a", "example.org/team"), errFmtResolveLabelValue, 0),
+					"failed to build reference at index 0",
+				),
+			},
+		},
 	}
 
 	for name, tc := range cases {
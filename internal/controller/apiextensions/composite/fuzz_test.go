@@ -146,7 +146,7 @@ func FuzzTransform(f *testing.F) {
 			return
 		}
 
-		_, _ = Resolve(*t, i)
+		_, _ = Resolve(*t, i, TransformVariables{})
 	})
 }
 
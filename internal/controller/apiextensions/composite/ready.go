@@ -40,6 +40,8 @@ const (
 	errFmtRequiresMatchString     = "type %q requires a match string"
 	errFmtRequiresMatchConditions = "type %q requires a valid match condition"
 	errFmtRequiresMatchInteger    = "type %q requires a match integer"
+	errFmtRequiresCELExpression   = "type %q requires a CEL expression"
+	errFmtCELResultNotBool        = "CEL expression must return a boolean, got %s"
 	errFmtUnknownCheck            = "unknown type %q"
 	errFmtRunCheck                = "cannot run readiness check at index %d"
 )
@@ -57,6 +59,7 @@ const (
 	ReadinessCheckTypeMatchTrue      ReadinessCheckType = "MatchTrue"
 	ReadinessCheckTypeMatchFalse     ReadinessCheckType = "MatchFalse"
 	ReadinessCheckTypeMatchCondition ReadinessCheckType = "MatchCondition"
+	ReadinessCheckTypeCEL            ReadinessCheckType = "CEL"
 	ReadinessCheckTypeNone           ReadinessCheckType = "None"
 )
 
@@ -77,6 +80,9 @@ type ReadinessCheck struct {
 
 	// MatchCondition is the condition you'd like to match if you're using "MatchCondition" type.
 	MatchCondition *MatchConditionReadinessCheck
+
+	// CELExpression is the expression you'd like to evaluate if you're using "CEL" type.
+	CELExpression *string
 }
 
 // MatchConditionReadinessCheck is used to indicate how to tell whether a resource is ready
@@ -116,6 +122,9 @@ func ReadinessCheckFromV1(in *v1.ReadinessCheck) ReadinessCheck {
 			Status: in.MatchCondition.Status,
 		}
 	}
+	if in.CEL != nil {
+		out.CELExpression = pointer.String(in.CEL.Expression)
+	}
 	return out
 }
 
@@ -190,6 +199,11 @@ func (c ReadinessCheck) Validate() error {
 			return errors.Errorf(errFmtRequiresMatchConditions, c.Type)
 		}
 		return nil
+	case ReadinessCheckTypeCEL:
+		if c.CELExpression == nil {
+			return errors.Errorf(errFmtRequiresCELExpression, c.Type)
+		}
+		return nil
 	default:
 		return errors.Errorf(errFmtUnknownCheck, c.Type)
 	}
@@ -243,11 +257,31 @@ func (c ReadinessCheck) IsReady(p *fieldpath.Paved, o ConditionedObject) (bool,
 			return false, resource.Ignore(fieldpath.IsNotFound, err)
 		}
 		return val == true, nil //nolint:gosimple // returning 'val' here as suggested hurts readability
+	case ReadinessCheckTypeCEL:
+		return isReadyCEL(*c.CELExpression, p)
 	}
 
 	return false, nil
 }
 
+// isReadyCEL evaluates the supplied CEL expression against the supplied
+// composed resource, which is made available to it as self.
+func isReadyCEL(expression string, p *fieldpath.Paved) (bool, error) {
+	prg, err := CompileCELReadinessCheck(expression)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := prg.Eval(map[string]any{CELVarSelf: p.UnstructuredContent()})
+	if err != nil {
+		return false, errors.Wrap(err, errCELEval)
+	}
+	ready, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.Errorf(errFmtCELResultNotBool, out.Type().TypeName())
+	}
+	return ready, nil
+}
+
 // A ReadinessChecker checks whether a composed resource is ready or not.
 type ReadinessChecker interface {
 	IsReady(ctx context.Context, o ConditionedObject, rc ...ReadinessCheck) (ready bool, err error)
@@ -395,6 +395,75 @@ func TestIsReady(t *testing.T) {
 				ready: false,
 			},
 		},
+		"CELMissingExpression": {
+			reason: "If a CEL check has no expression, it should return an error",
+			args: args{
+				o:  composed.New(),
+				rc: []ReadinessCheck{{Type: ReadinessCheckTypeCEL}},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrap(errors.Errorf(errFmtRequiresCELExpression, ReadinessCheckTypeCEL), errInvalidCheck), errFmtRunCheck, 0),
+			},
+		},
+		"CELReady": {
+			reason: "If the CEL expression evaluates to true, it should return true",
+			args: args{
+				o: composed.New(func(r *composed.Unstructured) {
+					r.Object = map[string]any{
+						"status": map[string]any{
+							"replicas":      int64(3),
+							"readyReplicas": int64(3),
+						},
+					}
+				}),
+				rc: []ReadinessCheck{{
+					Type:          ReadinessCheckTypeCEL,
+					CELExpression: pointer.String("self.status.readyReplicas == self.status.replicas"),
+				}},
+			},
+			want: want{
+				ready: true,
+			},
+		},
+		"CELNotReady": {
+			reason: "If the CEL expression evaluates to false, it should return false",
+			args: args{
+				o: composed.New(func(r *composed.Unstructured) {
+					r.Object = map[string]any{
+						"status": map[string]any{
+							"replicas":      int64(3),
+							"readyReplicas": int64(1),
+						},
+					}
+				}),
+				rc: []ReadinessCheck{{
+					Type:          ReadinessCheckTypeCEL,
+					CELExpression: pointer.String("self.status.readyReplicas == self.status.replicas"),
+				}},
+			},
+			want: want{
+				ready: false,
+			},
+		},
+		"CELNotBool": {
+			reason: "If the CEL expression doesn't evaluate to a boolean, it should return an error",
+			args: args{
+				o: composed.New(func(r *composed.Unstructured) {
+					r.Object = map[string]any{
+						"status": map[string]any{
+							"replicas": int64(3),
+						},
+					}
+				}),
+				rc: []ReadinessCheck{{
+					Type:          ReadinessCheckTypeCEL,
+					CELExpression: pointer.String("self.status.replicas"),
+				}},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtCELResultNotBool, "int"), errFmtRunCheck, 0),
+			},
+		},
 		"UnknownType": {
 			reason: "If unknown type is chosen, it should return an error",
 			args: args{
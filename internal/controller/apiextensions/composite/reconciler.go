@@ -62,6 +62,7 @@ const (
 	errConfigure              = "cannot configure composite resource"
 	errPublish                = "cannot publish connection details"
 	errUnpublish              = "cannot unpublish connection details"
+	errDeleteComposed         = "cannot delete composed resources"
 	errValidate               = "refusing to use invalid Composition"
 	errAssociate              = "cannot associate composed resources with Composition resource templates"
 	errFetchEnvironment       = "cannot fetch environment"
@@ -150,16 +151,34 @@ func (fn EnvironmentSelectorFn) SelectEnvironment(ctx context.Context, cr resour
 // An EnvironmentFetcher fetches an appropriate environment for the supplied
 // composite resource.
 type EnvironmentFetcher interface {
-	Fetch(ctx context.Context, cr resource.Composite, required bool) (*env.Environment, error)
+	Fetch(ctx context.Context, cr resource.Composite, rev *v1.CompositionRevision) (*env.Environment, error)
 }
 
 // An EnvironmentFetcherFn fetches an appropriate environment for the supplied
 // composite resource.
-type EnvironmentFetcherFn func(ctx context.Context, cr resource.Composite, required bool) (*env.Environment, error)
+type EnvironmentFetcherFn func(ctx context.Context, cr resource.Composite, rev *v1.CompositionRevision) (*env.Environment, error)
 
 // Fetch an appropriate environment for the supplied Composite resource.
-func (fn EnvironmentFetcherFn) Fetch(ctx context.Context, cr resource.Composite, required bool) (*env.Environment, error) {
-	return fn(ctx, cr, required)
+func (fn EnvironmentFetcherFn) Fetch(ctx context.Context, cr resource.Composite, rev *v1.CompositionRevision) (*env.Environment, error) {
+	return fn(ctx, cr, rev)
+}
+
+// A ComposedDeleter deletes the resources composed by a composite resource
+// that is being deleted.
+type ComposedDeleter interface {
+	// DeleteComposed deletes composed resources, returning true once the
+	// composite resource may be deleted.
+	DeleteComposed(ctx context.Context, cr resource.Composite) (bool, error)
+}
+
+// A ComposedDeleterFn deletes the resources composed by a composite resource
+// that is being deleted.
+type ComposedDeleterFn func(ctx context.Context, cr resource.Composite) (bool, error)
+
+// DeleteComposed deletes composed resources, returning true once the composite
+// resource may be deleted.
+func (fn ComposedDeleterFn) DeleteComposed(ctx context.Context, cr resource.Composite) (bool, error) {
+	return fn(ctx, cr)
 }
 
 // A Configurator configures a composite resource using its composition.
@@ -201,6 +220,11 @@ type CompositionResult struct {
 	Composed          []ComposedResource
 	ConnectionDetails managed.ConnectionDetails
 	Events            []event.Event
+
+	// FunctionResults records the outcome of each step of the Composition
+	// Function pipeline, if any. They may be returned even if composition
+	// failed.
+	FunctionResults []FunctionStepResult
 }
 
 // A Composer composes (i.e. creates, updates, or deletes) resources given the
@@ -279,6 +303,14 @@ func WithCompositeFinalizer(f resource.Finalizer) ReconcilerOption {
 	}
 }
 
+// WithComposedDeleter specifies how the Reconciler should delete composed
+// resources when a composite resource is deleted.
+func WithComposedDeleter(d ComposedDeleter) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composite.ComposedDeleter = d
+	}
+}
+
 // WithCompositionSelector specifies how the composition to be used should be
 // selected.
 func WithCompositionSelector(s CompositionSelector) ReconcilerOption {
@@ -359,6 +391,7 @@ type environment struct {
 
 type compositeResource struct {
 	resource.Finalizer
+	ComposedDeleter
 	CompositionSelector
 	CompositionUpdatePolicySelector
 	EnvironmentSelector
@@ -398,6 +431,7 @@ func NewReconciler(mgr manager.Manager, of resource.CompositeKind, opts ...Recon
 
 		composite: compositeResource{
 			Finalizer:           resource.NewAPIFinalizer(kube, finalizer),
+			ComposedDeleter:     NewDependencyOrderedDeleter(kube),
 			CompositionSelector: NewAPILabelSelectorResolver(kube),
 			EnvironmentSelector: env.NewNoopEnvironmentSelector(),
 			Configurator:        NewConfiguratorChain(NewAPINamingConfigurator(kube), NewAPIConfigurator(kube)),
@@ -475,6 +509,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log = log.WithValues("deletion-timestamp", xr.GetDeletionTimestamp())
 
 		xr.SetConditions(xpv1.Deleting())
+
+		// Composed resources are deleted by the API server's garbage collector
+		// once the XR is gone, but some must be deleted in order first.
+		deleted, err := r.composite.DeleteComposed(ctx, xr)
+		if err != nil {
+			log.Debug(errDeleteComposed, "error", err)
+			err = errors.Wrap(err, errDeleteComposed)
+			r.record.Event(xr, event.Warning(reasonDelete, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
+		if !deleted {
+			log.Debug("Waiting for composed resources to be deleted")
+			xr.SetConditions(xpv1.ReconcileSuccess())
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
+
 		if err := r.composite.UnpublishConnection(ctx, xr, nil); err != nil {
 			log.Debug(errUnpublish, "error", err)
 			err = errors.Wrap(err, errUnpublish)
@@ -559,7 +610,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
-	env, err := r.environment.Fetch(ctx, xr, rev.Spec.Environment.IsRequired())
+	env, err := r.environment.Fetch(ctx, xr, rev)
 	if err != nil {
 		log.Debug(errFetchEnvironment, "error", err)
 		err = errors.Wrap(err, errFetchEnvironment)
@@ -570,7 +621,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// TODO(negz): Pass this method a copy of xr, to make very clear that
 	// anything it does won't be reflected in the state of xr?
 	res, err := r.resource.Compose(ctx, xr, CompositionRequest{Revision: rev, Environment: env})
+
+	// Compose may update xr, which would overwrite any status we set before
+	// calling it.
+	if err := SetEnvironmentStatus(xr, rev.Spec.Environment, env); err != nil {
+		log.Debug(errSetEnvironmentStatus, "error", err)
+	}
+	if err := SetFunctionResults(xr, res.FunctionResults); err != nil {
+		log.Debug(errSetFunctionResults, "error", err)
+	}
 	if err != nil {
+		// Composition errors may include data read from Secrets.
+		err = env.Redact(err)
 		log.Debug(errCompose, "error", err)
 		err = errors.Wrap(err, errCompose)
 		r.record.Event(xr, event.Warning(reasonCompose, err))
@@ -597,6 +659,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		if e.Type == event.TypeWarning {
 			warnings++
 		}
+		e = env.RedactEvent(e)
 		log.Debug(e.Message)
 		r.record.Event(xr, e)
 	}
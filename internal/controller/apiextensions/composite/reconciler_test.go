@@ -24,9 +24,11 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -34,6 +36,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -42,6 +45,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
 )
 
@@ -94,6 +98,58 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errGet),
 			},
 		},
+		"DeleteComposedError": {
+			reason: "We should return any error encountered while deleting composed resources.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetDeletionTimestamp(&now)
+						})),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+							want.SetDeletionTimestamp(&now)
+							want.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(errors.Wrap(errBoom, errDeleteComposed)))
+						})),
+					}),
+					WithComposedDeleter(ComposedDeleterFn(func(ctx context.Context, cr resource.Composite) (bool, error) {
+						return false, errBoom
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"WaitingForComposedDeletion": {
+			reason: "We should requeue without removing our finalizer while waiting for composed resources to be deleted.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetDeletionTimestamp(&now)
+						})),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+							want.SetDeletionTimestamp(&now)
+							want.SetConditions(xpv1.Deleting(), xpv1.ReconcileSuccess())
+						})),
+					}),
+					WithComposedDeleter(ComposedDeleterFn(func(ctx context.Context, cr resource.Composite) (bool, error) {
+						return false, nil
+					})),
+					WithCompositeFinalizer(resource.FinalizerFns{
+						RemoveFinalizerFn: func(ctx context.Context, obj resource.Object) error {
+							t.Errorf("We should not remove our finalizer while waiting for composed resources to be deleted")
+							return nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"UnpublishConnectionError": {
 			reason: "We should return any error encountered while unpublishing connection details.",
 			args: args{
@@ -381,7 +437,7 @@ func TestReconcile(t *testing.T) {
 					})),
 					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
 					WithConfigurator(ConfiguratorFn(func(ctx context.Context, cr resource.Composite, rev *v1.CompositionRevision) error { return nil })),
-					WithEnvironmentFetcher(EnvironmentFetcherFn(func(ctx context.Context, cr resource.Composite, required bool) (*env.Environment, error) {
+					WithEnvironmentFetcher(EnvironmentFetcherFn(func(ctx context.Context, cr resource.Composite, rev *v1.CompositionRevision) (*env.Environment, error) {
 						return nil, errBoom
 					})),
 					WithCompositionUpdatePolicySelector(CompositionUpdatePolicySelectorFn(func(ctx context.Context, cr resource.Composite) error { return nil })),
@@ -425,6 +481,41 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"ComposeResourcesErrorWithFunctionResults": {
+			reason: "We should record the results of any Composition Function pipeline steps that ran, even if composing resources failed.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetCompositionReference(&corev1.ObjectReference{})
+							_ = SetFunctionResults(cr, []FunctionStepResult{{Step: "cool-step", Function: "cool-fn", Fatal: 1, Message: "oh no"}})
+							cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errCompose)))
+						})),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(ctx context.Context, xr resource.Composite, req CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{FunctionResults: []FunctionStepResult{{Step: "cool-step", Function: "cool-fn", Fatal: 1, Message: "oh no"}}}, errBoom
+					})),
+					WithCompositionUpdatePolicySelector(CompositionUpdatePolicySelectorFn(func(ctx context.Context, cr resource.Composite) error { return nil })),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"PublishConnectionDetailsError": {
 			reason: "We should return any error encountered while publishing connection details.",
 			args: args{
@@ -601,6 +692,66 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{RequeueAfter: defaultPollInterval},
 			},
 		},
+		"DebugEnvironmentSurvivesCompose": {
+			reason: "We should record the debug view of the environment after composing resources, since composing may update the XR and overwrite its status.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetAnnotations(map[string]string{AnnotationKeyDebugEnvironment: "true"})
+						})),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetAnnotations(map[string]string{AnnotationKeyDebugEnvironment: "true"})
+							cr.SetCompositionReference(&corev1.ObjectReference{})
+							cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+							_ = fieldpath.Pave(cr.(*composite.Unstructured).Object).SetValue(FieldPathEnvironment, map[string]any{
+								"data": map[string]any{"region": "us-east-1"},
+								"sources": map[string]any{
+									"region": []any{
+										map[string]any{
+											"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+											"kind":       v1alpha1.EnvironmentConfigKind,
+											"name":       "cool-config",
+										},
+									},
+								},
+							})
+						})),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{
+							Environment: &v1.EnvironmentConfiguration{AllowDebug: pointer.Bool(true)},
+						}}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithEnvironmentFetcher(EnvironmentFetcherFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) (*env.Environment, error) {
+						return env.NewEnvironment(v1alpha1.EnvironmentConfig{
+							ObjectMeta: metav1.ObjectMeta{Name: "cool-config"},
+							Data:       map[string]extv1.JSON{"region": {Raw: []byte(`"us-east-1"`)}},
+						})
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, xr resource.Composite, _ CompositionRequest) (CompositionResult, error) {
+						// Updating the XR replaces its status with the
+						// stored copy, which has no environment.
+						delete(xr.(*composite.Unstructured).Object, "status")
+						return CompositionResult{}, nil
+					})),
+					WithCompositionUpdatePolicySelector(CompositionUpdatePolicySelectorFn(func(ctx context.Context, cr resource.Composite) error { return nil })),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
 		"ReconciliationPausedSuccessful": {
 			reason: `If a composite resource has the pause annotation with value "true", there should be no further requeue requests.`,
 			args: args{
@@ -19,6 +19,8 @@ package controller
 
 import (
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
+
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 )
 
 // Options specific to pkg controllers.
@@ -36,4 +38,13 @@ type Options struct {
 	// Registry is the default registry to use when pulling containers for
 	// Composition Functions
 	Registry string
+
+	// FunctionRunner is used to run Composition Functions that were installed
+	// as Function packages. Composite resource controllers share it so they
+	// can share its response cache.
+	FunctionRunner composite.FunctionRunner
+
+	// FunctionMetrics is used to record metrics about each step of a
+	// Composition Function pipeline.
+	FunctionMetrics composite.FunctionPipelineMetrics
 }
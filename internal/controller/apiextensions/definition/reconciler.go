@@ -33,6 +33,7 @@ import (
 	kcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
@@ -47,7 +48,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
-	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	secretsv1alpha1 "github.com/crossplane/crossplane/apis/secrets/v1alpha1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
@@ -413,7 +415,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	u := &kunstructured.Unstructured{}
 	u.SetGroupVersionKind(d.GetCompositeGroupVersionKind())
 
-	if err := r.composite.Start(composite.ControllerName(d.GetName()), ko, controller.For(u, &handler.EnqueueRequestForObject{})); err != nil {
+	w := []controller.Watch{controller.For(u, &handler.EnqueueRequestForObject{})}
+
+	// If Composition environments are enabled we index composite resources by
+	// the EnvironmentConfigs they reference, so that we can requeue them as
+	// soon as an EnvironmentConfig they reference changes.
+	if r.options.Features.Enabled(features.EnableAlphaEnvironmentConfigs) {
+		i := NewEnvironmentConfigIndex(d.GetCompositeGroupVersionKind())
+		w = append(w,
+			controller.For(u, i),
+			controller.For(&v1alpha1.EnvironmentConfig{},
+				NewEnqueueRequestForReferencingComposites(i, r.record.WithAnnotations("controller", composite.ControllerName(d.GetName()))),
+				predicate.ResourceVersionChangedPredicate{}))
+	}
+
+	if err := r.composite.Start(composite.ControllerName(d.GetName()), ko, w...); err != nil {
 		log.Debug(errStartController, "error", err)
 		err = errors.Wrap(err, errStartController)
 		r.record.Event(d, event.Warning(reasonEstablishXR, err))
@@ -448,9 +464,13 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 	// fetcher that will always return nil. All environment features are
 	// subsequently skipped if the environment is nil.
 	if co.Features.Enabled(features.EnableAlphaEnvironmentConfigs) {
+		var fo []environment.APIEnvironmentFetcherOption
+		if co.Features.Enabled(features.EnableAlphaEnvironmentSecrets) {
+			fo = append(fo, environment.WithSecretSources())
+		}
 		o = append(o,
 			composite.WithEnvironmentSelector(environment.NewAPIEnvironmentSelector(c)),
-			composite.WithEnvironmentFetcher(environment.NewAPIEnvironmentFetcher(c)))
+			composite.WithEnvironmentFetcher(environment.NewAPIEnvironmentFetcher(c, fo...)))
 	}
 
 	// If external secret stores aren't enabled we just fetch connection details
@@ -466,7 +486,7 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 	if co.Features.Enabled(features.EnableAlphaExternalSecretStores) {
 		pc := []managed.ConnectionPublisher{
 			composite.NewAPIFilteredSecretPublisher(c, d.GetConnectionSecretKeys()),
-			composite.NewSecretStoreConnectionPublisher(connection.NewDetailsManager(c, v1alpha1.StoreConfigGroupVersionKind,
+			composite.NewSecretStoreConnectionPublisher(connection.NewDetailsManager(c, secretsv1alpha1.StoreConfigGroupVersionKind,
 				connection.WithTLSConfig(co.ESSOptions.TLSConfig)), d.GetConnectionSecretKeys()),
 		}
 
@@ -474,7 +494,7 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 		// connection details from both secrets and external stores.
 		fetcher = composite.ConnectionDetailsFetcherChain{
 			composite.NewSecretConnectionDetailsFetcher(c),
-			connection.NewDetailsManager(c, v1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(co.ESSOptions.TLSConfig)),
+			connection.NewDetailsManager(c, secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(co.ESSOptions.TLSConfig)),
 		}
 
 		cc := composite.NewConfiguratorChain(
@@ -499,15 +519,23 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 	// Composition validation ensures that a Composition that uses functions
 	// must have named resources templates.
 	if co.Features.Enabled(features.EnableAlphaCompositionFunctions) {
+		po := []composite.PTFComposerOption{
+			composite.WithComposedResourceGetter(composite.NewExistingComposedResourceGetter(c, fetcher)),
+			composite.WithCompositeConnectionDetailsFetcher(fetcher),
+			composite.WithFunctionPipelineRunner(composite.NewFunctionPipeline(
+				composite.ContainerFunctionRunnerFn(composite.RunFunction),
+				composite.WithKubernetesAuthentication(c, co.Namespace, co.ServiceAccount, co.Registry),
+			)),
+		}
+		if co.FunctionRunner != nil {
+			po = append(po, composite.WithFunctionStepRunner(composite.NewPackagedFunctionPipeline(co.FunctionRunner)))
+		}
+		if co.FunctionMetrics != nil {
+			po = append(po, composite.WithFunctionPipelineMetrics(co.FunctionMetrics))
+		}
+
 		fb := composite.NewFallBackComposer(
-			composite.NewPTFComposer(c,
-				composite.WithComposedResourceGetter(composite.NewExistingComposedResourceGetter(c, fetcher)),
-				composite.WithCompositeConnectionDetailsFetcher(fetcher),
-				composite.WithFunctionPipelineRunner(composite.NewFunctionPipeline(
-					composite.ContainerFunctionRunnerFn(composite.RunFunction),
-					composite.WithKubernetesAuthentication(c, co.Namespace, co.ServiceAccount, co.Registry),
-				)),
-			),
+			composite.NewPTFComposer(c, po...),
 			composite.NewPTComposer(c, composite.WithComposedConnectionDetailsFetcher(fetcher)),
 			composite.FallBackForAnonymousTemplates(c),
 		)
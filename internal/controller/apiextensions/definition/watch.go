@@ -0,0 +1,243 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+// Event reasons.
+const (
+	reasonEnvironmentChanged event.Reason = "EnvironmentConfigChanged"
+)
+
+type adder interface {
+	Add(item any)
+}
+
+// An EnvironmentConfigIndex indexes composite resources of a particular kind
+// by the EnvironmentConfigs they reference. It's kept up to date by handling
+// the events of a watch on the kind of composite resource.
+type EnvironmentConfigIndex struct {
+	gvk schema.GroupVersionKind
+
+	mx sync.RWMutex
+
+	// Composite resource UIDs, keyed by name, keyed by the name of the
+	// EnvironmentConfig they reference.
+	composites map[string]map[string]types.UID
+
+	// EnvironmentConfig names, keyed by the name of the composite resource
+	// that references them.
+	configs map[string][]string
+}
+
+// NewEnvironmentConfigIndex returns an empty index of the supplied kind of
+// composite resource.
+func NewEnvironmentConfigIndex(gvk schema.GroupVersionKind) *EnvironmentConfigIndex {
+	return &EnvironmentConfigIndex{
+		gvk:        gvk,
+		composites: make(map[string]map[string]types.UID),
+		configs:    make(map[string][]string),
+	}
+}
+
+// Create indexes the EnvironmentConfigs referenced by a composite resource.
+func (i *EnvironmentConfigIndex) Create(_ context.Context, evt kevent.CreateEvent, _ workqueue.RateLimitingInterface) {
+	i.index(evt.Object)
+}
+
+// Update indexes the EnvironmentConfigs referenced by a composite resource.
+func (i *EnvironmentConfigIndex) Update(_ context.Context, evt kevent.UpdateEvent, _ workqueue.RateLimitingInterface) {
+	i.index(evt.ObjectNew)
+}
+
+// Delete removes a composite resource from the index.
+func (i *EnvironmentConfigIndex) Delete(_ context.Context, evt kevent.DeleteEvent, _ workqueue.RateLimitingInterface) {
+	if evt.Object == nil {
+		return
+	}
+	i.remove(evt.Object.GetName())
+}
+
+// Generic indexes the EnvironmentConfigs referenced by a composite resource.
+func (i *EnvironmentConfigIndex) Generic(_ context.Context, evt kevent.GenericEvent, _ workqueue.RateLimitingInterface) {
+	i.index(evt.Object)
+}
+
+func (i *EnvironmentConfigIndex) index(obj client.Object) {
+	u, ok := obj.(*kunstructured.Unstructured)
+	if !ok {
+		return
+	}
+	xr := &composite.Unstructured{Unstructured: *u}
+
+	names := make([]string, 0)
+	for _, ref := range xr.GetEnvironmentConfigReferences() {
+		// References without a kind predate support for reading environment
+		// data from other kinds of object, and are to EnvironmentConfigs.
+		if ref.Kind != "" && ref.Kind != v1alpha1.EnvironmentConfigKind {
+			continue
+		}
+		names = append(names, ref.Name)
+	}
+
+	i.mx.Lock()
+	defer i.mx.Unlock()
+	i.removeLocked(xr.GetName())
+	if len(names) == 0 {
+		return
+	}
+	i.configs[xr.GetName()] = names
+	for _, n := range names {
+		if i.composites[n] == nil {
+			i.composites[n] = make(map[string]types.UID)
+		}
+		i.composites[n][xr.GetName()] = xr.GetUID()
+	}
+}
+
+func (i *EnvironmentConfigIndex) remove(name string) {
+	i.mx.Lock()
+	defer i.mx.Unlock()
+	i.removeLocked(name)
+}
+
+func (i *EnvironmentConfigIndex) removeLocked(name string) {
+	for _, n := range i.configs[name] {
+		delete(i.composites[n], name)
+		if len(i.composites[n]) == 0 {
+			delete(i.composites, n)
+		}
+	}
+	delete(i.configs, name)
+}
+
+// Referencing returns the composite resources that reference the named
+// EnvironmentConfig, sorted by name. Only the kind, name, and UID of each
+// composite resource are set.
+func (i *EnvironmentConfigIndex) Referencing(name string) []*kunstructured.Unstructured {
+	i.mx.RLock()
+	defer i.mx.RUnlock()
+
+	out := make([]*kunstructured.Unstructured, 0, len(i.composites[name]))
+	for n, uid := range i.composites[name] {
+		u := &kunstructured.Unstructured{}
+		u.SetGroupVersionKind(i.gvk)
+		u.SetName(n)
+		u.SetUID(uid)
+		out = append(out, u)
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].GetName() < out[b].GetName() })
+	return out
+}
+
+// EnqueueRequestForReferencingComposites enqueues a request for all composite
+// resources that reference an EnvironmentConfig when the EnvironmentConfig
+// changes.
+type EnqueueRequestForReferencingComposites struct {
+	index  *EnvironmentConfigIndex
+	record event.Recorder
+}
+
+// NewEnqueueRequestForReferencingComposites returns an EventHandler that
+// enqueues the composite resources in the supplied index that reference a
+// changed EnvironmentConfig. A single event is recorded on the changed
+// EnvironmentConfig, rather than one on each composite resource.
+func NewEnqueueRequestForReferencingComposites(i *EnvironmentConfigIndex, r event.Recorder) *EnqueueRequestForReferencingComposites {
+	return &EnqueueRequestForReferencingComposites{index: i, record: r}
+}
+
+// Create enqueues a request for all composite resources that reference a
+// given EnvironmentConfig. It doesn't record an event, because the initial
+// list of EnvironmentConfigs produces a create event for each one.
+func (e *EnqueueRequestForReferencingComposites) Create(_ context.Context, evt kevent.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(evt.Object, q)
+}
+
+// Update enqueues a request for all composite resources that reference a
+// given EnvironmentConfig, if its data or labels changed.
+func (e *EnqueueRequestForReferencingComposites) Update(_ context.Context, evt kevent.UpdateEvent, q workqueue.RateLimitingInterface) {
+	old, ok := evt.ObjectOld.(*v1alpha1.EnvironmentConfig)
+	if !ok {
+		return
+	}
+	ec, ok := evt.ObjectNew.(*v1alpha1.EnvironmentConfig)
+	if !ok {
+		return
+	}
+	// Periodic resyncs and metadata-only updates don't change the data the
+	// composite resources read or the label selectors they match.
+	if equality.Semantic.DeepEqual(old.Data, ec.Data) && equality.Semantic.DeepEqual(old.GetLabels(), ec.GetLabels()) {
+		return
+	}
+	e.add(ec, "updated", q)
+}
+
+// Delete enqueues a request for all composite resources that reference a
+// given EnvironmentConfig.
+func (e *EnqueueRequestForReferencingComposites) Delete(_ context.Context, evt kevent.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.add(evt.Object, "deleted", q)
+}
+
+// Generic enqueues a request for all composite resources that reference a
+// given EnvironmentConfig.
+func (e *EnqueueRequestForReferencingComposites) Generic(_ context.Context, evt kevent.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(evt.Object, q)
+}
+
+// add enqueues the composite resources that reference the supplied
+// EnvironmentConfig, and records that they were enqueued because it changed.
+func (e *EnqueueRequestForReferencingComposites) add(obj client.Object, change string, queue adder) {
+	n := e.enqueue(obj, queue)
+	if n == 0 {
+		return
+	}
+	e.record.Event(obj, event.Normal(reasonEnvironmentChanged, fmt.Sprintf("EnvironmentConfig %s; requeued %d referencing %s composite resources", change, n, e.index.gvk.Kind)))
+}
+
+// enqueue the composite resources that reference the supplied
+// EnvironmentConfig, returning how many were enqueued.
+func (e *EnqueueRequestForReferencingComposites) enqueue(obj client.Object, queue adder) int {
+	ec, ok := obj.(*v1alpha1.EnvironmentConfig)
+	if !ok {
+		return 0
+	}
+
+	xrs := e.index.Referencing(ec.GetName())
+	for _, xr := range xrs {
+		queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: xr.GetName()}})
+	}
+	return len(xrs)
+}
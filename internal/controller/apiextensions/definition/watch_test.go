@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+var (
+	_ handler.EventHandler = &EnvironmentConfigIndex{}
+	_ handler.EventHandler = &EnqueueRequestForReferencingComposites{}
+)
+
+type recordFn func(obj runtime.Object, e event.Event)
+
+func (fn recordFn) Event(obj runtime.Object, e event.Event)    { fn(obj, e) }
+func (fn recordFn) WithAnnotations(_ ...string) event.Recorder { return fn }
+
+type fakeQueue struct {
+	workqueue.RateLimitingInterface
+	add func(item any)
+}
+
+func (q *fakeQueue) Add(item any) { q.add(item) }
+
+func TestEnqueueRequestForReferencingComposites(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCoolComposite"}
+
+	xr := func(name string, refs ...corev1.ObjectReference) client.Object {
+		cp := composite.New(composite.WithGroupVersionKind(gvk))
+		cp.SetName(name)
+		cp.SetUID(types.UID(name + "-uid"))
+		cp.SetEnvironmentConfigReferences(refs)
+		return &cp.Unstructured
+	}
+	ref := func(kind, name string) corev1.ObjectReference {
+		return corev1.ObjectReference{Kind: kind, Name: name}
+	}
+	ec := func(name, region string) *v1alpha1.EnvironmentConfig {
+		return &v1alpha1.EnvironmentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Data:       map[string]extv1.JSON{"region": {Raw: []byte(`"` + region + `"`)}},
+		}
+	}
+
+	deleted := func(o client.Object) func(e *EnqueueRequestForReferencingComposites, q workqueue.RateLimitingInterface) {
+		return func(e *EnqueueRequestForReferencingComposites, q workqueue.RateLimitingInterface) {
+			e.Delete(context.Background(), kevent.DeleteEvent{Object: o}, q)
+		}
+	}
+
+	type args struct {
+		composites []client.Object
+		deleted    []client.Object
+		handle     func(e *EnqueueRequestForReferencingComposites, q workqueue.RateLimitingInterface)
+	}
+	type want struct {
+		requests []reconcile.Request
+		events   []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ObjectIsNotAnEnvironmentConfig": {
+			reason: "Nothing should be enqueued when the object isn't an EnvironmentConfig.",
+			args: args{
+				composites: []client.Object{xr("cool-xr", ref(v1alpha1.EnvironmentConfigKind, "cool-config"))},
+				handle:     deleted(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cool-config"}}),
+			},
+		},
+		"NoReferencingComposites": {
+			reason: "Nothing should be enqueued when no composite resource references the EnvironmentConfig.",
+			args: args{
+				composites: []client.Object{xr("cool-xr", ref(v1alpha1.EnvironmentConfigKind, "other-config"))},
+				handle:     deleted(ec("cool-config", "us-east-1")),
+			},
+		},
+		"ReferenceToOtherKind": {
+			reason: "Nothing should be enqueued when a composite resource references a different kind of object with the same name.",
+			args: args{
+				composites: []client.Object{xr("cool-xr", ref("ConfigMap", "cool-config"))},
+				handle:     deleted(ec("cool-config", "us-east-1")),
+			},
+		},
+		"DeletedComposite": {
+			reason: "Nothing should be enqueued for a composite resource that was deleted.",
+			args: args{
+				composites: []client.Object{xr("cool-xr", ref(v1alpha1.EnvironmentConfigKind, "cool-config"))},
+				deleted:    []client.Object{xr("cool-xr")},
+				handle:     deleted(ec("cool-config", "us-east-1")),
+			},
+		},
+		"ReferencingComposites": {
+			reason: "All composite resources that reference the EnvironmentConfig should be enqueued, including those with untyped references, and one event should be recorded.",
+			args: args{
+				composites: []client.Object{
+					xr("cool-xr", ref(v1alpha1.EnvironmentConfigKind, "cool-config")),
+					xr("other-xr", ref(v1alpha1.EnvironmentConfigKind, "other-config")),
+					xr("legacy-xr", ref("", "cool-config")),
+				},
+				handle: deleted(ec("cool-config", "us-east-1")),
+			},
+			want: want{
+				requests: []reconcile.Request{
+					{NamespacedName: types.NamespacedName{Name: "cool-xr"}},
+					{NamespacedName: types.NamespacedName{Name: "legacy-xr"}},
+				},
+				events: []string{"cool-config: EnvironmentConfig deleted; requeued 2 referencing XCoolComposite composite resources"},
+			},
+		},
+		"Created": {
+			reason: "Referencing composite resources should be enqueued when an EnvironmentConfig is created, but no event should be recorded because the initial list of EnvironmentConfigs produces create events.",
+			args: args{
+				composites: []client.Object{xr("cool-xr", ref(v1alpha1.EnvironmentConfigKind, "cool-config"))},
+				handle: func(e *EnqueueRequestForReferencingComposites, q workqueue.RateLimitingInterface) {
+					e.Create(context.Background(), kevent.CreateEvent{Object: ec("cool-config", "us-east-1")}, q)
+				},
+			},
+			want: want{
+				requests: []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "cool-xr"}}},
+			},
+		},
+		"UpdatedData": {
+			reason: "Referencing composite resources should be enqueued and an event recorded when an EnvironmentConfig's data changes.",
+			args: args{
+				composites: []client.Object{xr("cool-xr", ref(v1alpha1.EnvironmentConfigKind, "cool-config"))},
+				handle: func(e *EnqueueRequestForReferencingComposites, q workqueue.RateLimitingInterface) {
+					e.Update(context.Background(), kevent.UpdateEvent{ObjectOld: ec("cool-config", "us-east-1"), ObjectNew: ec("cool-config", "eu-west-1")}, q)
+				},
+			},
+			want: want{
+				requests: []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "cool-xr"}}},
+				events:   []string{"cool-config: EnvironmentConfig updated; requeued 1 referencing XCoolComposite composite resources"},
+			},
+		},
+		"UpdatedMetadataOnly": {
+			reason: "Nothing should be enqueued when an EnvironmentConfig's data and labels didn't change.",
+			args: args{
+				composites: []client.Object{xr("cool-xr", ref(v1alpha1.EnvironmentConfigKind, "cool-config"))},
+				handle: func(e *EnqueueRequestForReferencingComposites, q workqueue.RateLimitingInterface) {
+					updated := ec("cool-config", "us-east-1")
+					updated.SetAnnotations(map[string]string{"cool": "annotation"})
+					e.Update(context.Background(), kevent.UpdateEvent{ObjectOld: ec("cool-config", "us-east-1"), ObjectNew: updated}, q)
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			i := NewEnvironmentConfigIndex(gvk)
+			for _, o := range tc.args.composites {
+				i.Create(context.Background(), kevent.CreateEvent{Object: o}, nil)
+			}
+			for _, o := range tc.args.deleted {
+				i.Delete(context.Background(), kevent.DeleteEvent{Object: o}, nil)
+			}
+
+			var events []string
+			e := NewEnqueueRequestForReferencingComposites(i, recordFn(func(obj runtime.Object, ev event.Event) {
+				if ev.Reason != reasonEnvironmentChanged {
+					t.Errorf("\n%s\nEvent(...): recorded event with unexpected reason %s", tc.reason, ev.Reason)
+				}
+				events = append(events, obj.(client.Object).GetName()+": "+ev.Message)
+			}))
+
+			var requests []reconcile.Request
+			tc.args.handle(e, &fakeQueue{add: func(item any) {
+				requests = append(requests, item.(reconcile.Request))
+			}})
+
+			if diff := cmp.Diff(tc.want.requests, requests); diff != "" {
+				t.Errorf("\n%s\nhandle(...): -want requests, +got requests:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, events); diff != "" {
+				t.Errorf("\n%s\nhandle(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnvironmentConfigIndexUpdate(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCoolComposite"}
+
+	before := composite.New(composite.WithGroupVersionKind(gvk))
+	before.SetName("cool-xr")
+	before.SetEnvironmentConfigReferences([]corev1.ObjectReference{{Kind: v1alpha1.EnvironmentConfigKind, Name: "old-config"}})
+
+	after := composite.New(composite.WithGroupVersionKind(gvk))
+	after.SetName("cool-xr")
+	after.SetEnvironmentConfigReferences([]corev1.ObjectReference{{Kind: v1alpha1.EnvironmentConfigKind, Name: "new-config"}})
+
+	i := NewEnvironmentConfigIndex(gvk)
+	i.Create(context.Background(), kevent.CreateEvent{Object: &before.Unstructured}, nil)
+	i.Update(context.Background(), kevent.UpdateEvent{ObjectOld: &before.Unstructured, ObjectNew: &after.Unstructured}, nil)
+
+	if got := i.Referencing("old-config"); len(got) != 0 {
+		t.Errorf("Referencing(%q): want no composite resources after update, got %d", "old-config", len(got))
+	}
+	if got := i.Referencing("new-config"); len(got) != 1 || got[0].GetName() != "cool-xr" {
+		t.Errorf("Referencing(%q): want composite resource %q after update, got %v", "new-config", "cool-xr", got)
+	}
+}
@@ -0,0 +1,447 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usage manages the lifecycle of Usage objects.
+package usage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+	xpusage "github.com/crossplane/crossplane/internal/usage"
+)
+
+const (
+	timeout   = 2 * time.Minute
+	finalizer = "usage.apiextensions.crossplane.io"
+
+	// waitPoll is how long we wait before checking again whether the using
+	// resource of a deleted Usage is gone.
+	waitPoll = 30 * time.Second
+
+	// Labels and annotations.
+	inUseLabelKey        = "crossplane.io/in-use"
+	detailsAnnotationKey = "crossplane.io/usage-details"
+)
+
+// Error strings.
+const (
+	errGetUsage          = "cannot get usage"
+	errResolveSelectors  = "cannot resolve selectors"
+	errNoUsingOrReason   = "usage must specify either a using resource or a reason"
+	errListUsages        = "cannot list usages"
+	errGetUsing          = "cannot get using resource"
+	errGetUsed           = "cannot get used resource"
+	errAddOwnerToUsage   = "cannot update usage resource with owner ref"
+	errAddDetails        = "cannot update usage resource with added details"
+	errAddInUseLabel     = "cannot add in use use label to the used resource"
+	errRemoveInUseLabel  = "cannot remove in use label from the used resource"
+	errAddFinalizer      = "cannot add finalizer"
+	errRemoveFinalizer   = "cannot remove finalizer"
+	errDeleteUsage       = "cannot delete usage whose using resource is gone"
+	errUpdateStatus      = "cannot update status of usage"
+	errIndexUsages       = "cannot index usages by the resource they use"
+	errUsingNotFoundYet  = "using resource does not exist yet"
+	errFmtUsingNotExists = "using resource %s/%s does not exist"
+)
+
+// Event reasons.
+const (
+	reasonResolveSelectors event.Reason = "ResolveSelectors"
+	reasonListUsages       event.Reason = "ListUsages"
+	reasonGetUsed          event.Reason = "GetUsedResource"
+	reasonGetUsing         event.Reason = "GetUsingResource"
+	reasonOwnerRefToUsage  event.Reason = "AddOwnerRefToUsage"
+	reasonAddInUseLabel    event.Reason = "AddInUseLabel"
+	reasonRemoveInUseLabel event.Reason = "RemoveInUseLabel"
+	reasonAddFinalizer     event.Reason = "AddFinalizer"
+	reasonRemoveFinalizer  event.Reason = "RemoveFinalizer"
+	reasonDeleteUsage      event.Reason = "DeleteUsage"
+	reasonWaitUsing        event.Reason = "WaitingUsingDeleted"
+
+	reasonUsageConfigured event.Reason = "UsageConfigured"
+)
+
+// Setup adds a controller that reconciles Usages by labelling the resources
+// they use, so that the Usage webhook may block their deletion.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "usage/" + strings.ToLower(v1alpha1.UsageGroupKind)
+
+	// The Usage webhook uses this index too, to find the Usages of a resource
+	// that is being deleted.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Usage{}, xpusage.InUseIndexKey, xpusage.IndexUsageOf); err != nil {
+		return errors.Wrap(err, errIndexUsages)
+	}
+
+	r := NewReconciler(mgr,
+		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.Usage{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(log logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = log
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record Kubernetes events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithClient specifies how the Reconciler should interact with the Kubernetes
+// API.
+func WithClient(c client.Client) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.client = c
+	}
+}
+
+// WithFinalizer specifies how the Reconciler should add and remove
+// finalizers to and from Usages.
+func WithFinalizer(f resource.Finalizer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.usage.Finalizer = f
+	}
+}
+
+// WithSelectorResolver specifies how the Reconciler should resolve the
+// selectors of Usages.
+func WithSelectorResolver(sr SelectorResolver) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.usage.SelectorResolver = sr
+	}
+}
+
+type usageResource struct {
+	resource.Finalizer
+	SelectorResolver
+}
+
+// NewReconciler returns a Reconciler of Usages.
+func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
+	kube := mgr.GetClient()
+
+	r := &Reconciler{
+		client: kube,
+		usage: usageResource{
+			Finalizer:        resource.NewAPIFinalizer(kube, finalizer),
+			SelectorResolver: NewAPISelectorResolver(kube),
+		},
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+	return r
+}
+
+// A Reconciler reconciles Usages.
+type Reconciler struct {
+	client client.Client
+	usage  usageResource
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Reconcile a Usage resource by resolving its selectors, defining an owner
+// reference to the using resource, and labelling the used resource as in use.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocyclo // Reconcilers are typically complex.
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	u := &v1alpha1.Usage{}
+	if err := r.client.Get(ctx, req.NamespacedName, u); err != nil {
+		log.Debug(errGetUsage, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetUsage)
+	}
+
+	if err := r.usage.ResolveSelectors(ctx, u); err != nil {
+		log.Debug(errResolveSelectors, "error", err)
+		err = errors.Wrap(err, errResolveSelectors)
+		r.record.Event(u, event.Warning(reasonResolveSelectors, err))
+		return reconcile.Result{}, err
+	}
+
+	log = log.WithValues(
+		"uid", u.GetUID(),
+		"version", u.GetResourceVersion(),
+		"name", u.GetName(),
+	)
+
+	of := u.Spec.Of
+	by := u.Spec.By
+
+	// Identify used resource as an unstructured object.
+	used := composed.New(composed.FromReference(corev1.ObjectReference{
+		Kind:       of.Kind,
+		Name:       of.ResourceRef.Name,
+		APIVersion: of.APIVersion,
+	}))
+
+	if meta.WasDeleted(u) {
+		if by != nil {
+			// Identify using resource as an unstructured object.
+			using := composed.New(composed.FromReference(corev1.ObjectReference{
+				Kind:       by.Kind,
+				Name:       by.ResourceRef.Name,
+				APIVersion: by.APIVersion,
+			}))
+			// Get the using resource
+			err := r.client.Get(ctx, types.NamespacedName{Name: by.ResourceRef.Name}, using)
+			if resource.IgnoreNotFound(err) != nil {
+				log.Debug(errGetUsing, "error", err)
+				err = errors.Wrap(err, errGetUsing)
+				r.record.Event(u, event.Warning(reasonGetUsing, err))
+				return reconcile.Result{}, err
+			}
+
+			if err == nil {
+				// The using resource is still there, so we need to wait for
+				// it to be deleted.
+				msg := "Waiting for the using resource to be deleted."
+				log.Debug(msg)
+				r.record.Event(u, event.Normal(reasonWaitUsing, msg))
+				return reconcile.Result{RequeueAfter: waitPoll}, nil
+			}
+		}
+
+		// Get the used resource
+		err := r.client.Get(ctx, types.NamespacedName{Name: of.ResourceRef.Name}, used)
+		if resource.IgnoreNotFound(err) != nil {
+			log.Debug(errGetUsed, "error", err)
+			err = errors.Wrap(err, errGetUsed)
+			r.record.Event(u, event.Warning(reasonGetUsed, err))
+			return reconcile.Result{}, err
+		}
+
+		// The used resource may have been deleted already.
+		if err == nil {
+			// Remove the in-use label from the used resource unless another
+			// Usage uses it too.
+			l := &v1alpha1.UsageList{}
+			if err := r.client.List(ctx, l, client.MatchingFields{xpusage.InUseIndexKey: xpusage.IndexValueForObject(used.GetUnstructured())}); err != nil {
+				log.Debug(errListUsages, "error", err)
+				err = errors.Wrap(err, errListUsages)
+				r.record.Event(u, event.Warning(reasonListUsages, err))
+				return reconcile.Result{}, err
+			}
+			if !usedByOthers(l.Items, u) {
+				meta.RemoveLabels(used, inUseLabelKey)
+			}
+			if err := r.client.Update(ctx, used); resource.IgnoreNotFound(err) != nil {
+				log.Debug(errRemoveInUseLabel, "error", err)
+				err = errors.Wrap(err, errRemoveInUseLabel)
+				r.record.Event(u, event.Warning(reasonRemoveInUseLabel, err))
+				return reconcile.Result{}, err
+			}
+		}
+
+		// Remove the finalizer from the usage
+		if err := r.usage.RemoveFinalizer(ctx, u); err != nil {
+			log.Debug(errRemoveFinalizer, "error", err)
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errRemoveFinalizer)
+			r.record.Event(u, event.Warning(reasonRemoveFinalizer, err))
+			return reconcile.Result{}, err
+		}
+
+		log.Debug("Successfully deleted usage")
+		return reconcile.Result{}, nil
+	}
+
+	if by == nil && u.Spec.Reason == nil {
+		log.Debug(errNoUsingOrReason)
+		r.record.Event(u, event.Warning(reasonUsageConfigured, errors.New(errNoUsingOrReason)))
+		u.SetConditions(xpv1.ReconcileError(errors.New(errNoUsingOrReason)))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, u), errUpdateStatus)
+	}
+
+	// Add finalizer for Usage resource.
+	if err := r.usage.AddFinalizer(ctx, u); err != nil {
+		log.Debug(errAddFinalizer, "error", err)
+		if kerrors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		err = errors.Wrap(err, errAddFinalizer)
+		r.record.Event(u, event.Warning(reasonAddFinalizer, err))
+		return reconcile.Result{}, err
+	}
+
+	// Get the used resource
+	if err := r.client.Get(ctx, types.NamespacedName{Name: of.ResourceRef.Name}, used); err != nil {
+		log.Debug(errGetUsed, "error", err)
+		err = errors.Wrap(err, errGetUsed)
+		r.record.Event(u, event.Warning(reasonGetUsed, err))
+		return reconcile.Result{}, err
+	}
+
+	// Label the used resource as in use, so that the Usage webhook intercepts
+	// requests to delete it.
+	if used.GetLabels()[inUseLabelKey] != "true" {
+		meta.AddLabels(used, map[string]string{inUseLabelKey: "true"})
+		if err := r.client.Update(ctx, used); err != nil {
+			log.Debug(errAddInUseLabel, "error", err)
+			err = errors.Wrap(err, errAddInUseLabel)
+			r.record.Event(u, event.Warning(reasonAddInUseLabel, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	if by != nil {
+		// Identify using resource as an unstructured object.
+		using := composed.New(composed.FromReference(corev1.ObjectReference{
+			Kind:       by.Kind,
+			Name:       by.ResourceRef.Name,
+			APIVersion: by.APIVersion,
+		}))
+
+		// Get the using resource
+		err := r.client.Get(ctx, types.NamespacedName{Name: by.ResourceRef.Name}, using)
+		if kerrors.IsNotFound(err) && ownedBy(u, by) {
+			// The using resource existed, but is gone. The garbage collector
+			// would delete the Usage eventually, but there's no reason to
+			// block deletion of the used resource until then.
+			log.Debug("Using resource is gone, deleting usage")
+			if err := r.client.Delete(ctx, u); resource.IgnoreNotFound(err) != nil {
+				log.Debug(errDeleteUsage, "error", err)
+				err = errors.Wrap(err, errDeleteUsage)
+				r.record.Event(u, event.Warning(reasonDeleteUsage, err))
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{Requeue: true}, nil
+		}
+		if kerrors.IsNotFound(err) {
+			// The using resource doesn't exist yet, e.g. because it's
+			// composed after the Usage.
+			log.Debug(errUsingNotFoundYet)
+			r.record.Event(u, event.Normal(reasonGetUsing, fmt.Sprintf(errFmtUsingNotExists, by.Kind, by.ResourceRef.Name)))
+			return reconcile.Result{RequeueAfter: waitPoll}, nil
+		}
+		if err != nil {
+			log.Debug(errGetUsing, "error", err)
+			err = errors.Wrap(err, errGetUsing)
+			r.record.Event(u, event.Warning(reasonGetUsing, err))
+			return reconcile.Result{}, err
+		}
+
+		// Add owner reference to the using resource, so that the Usage is
+		// garbage collected when the using resource is deleted.
+		if !hasOwnerReference(u, using.GetUID()) {
+			meta.AddOwnerReference(u, meta.AsOwner(meta.TypedReferenceTo(using, using.GetObjectKind().GroupVersionKind())))
+			if err := r.client.Update(ctx, u); err != nil {
+				log.Debug(errAddOwnerToUsage, "error", err)
+				err = errors.Wrap(err, errAddOwnerToUsage)
+				r.record.Event(u, event.Warning(reasonOwnerRefToUsage, err))
+				return reconcile.Result{}, err
+			}
+		}
+	}
+
+	if d := detailsOf(u); u.GetAnnotations()[detailsAnnotationKey] != d {
+		meta.AddAnnotations(u, map[string]string{detailsAnnotationKey: d})
+		if err := r.client.Update(ctx, u); err != nil {
+			log.Debug(errAddDetails, "error", err)
+			err = errors.Wrap(err, errAddDetails)
+			r.record.Event(u, event.Warning(reasonUsageConfigured, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	u.SetConditions(xpv1.Available())
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, u), errUpdateStatus)
+}
+
+// detailsOf returns a human readable description of the supplied Usage.
+func detailsOf(u *v1alpha1.Usage) string {
+	of := fmt.Sprintf("%s/%s", u.Spec.Of.Kind, u.Spec.Of.ResourceRef.Name)
+	if by := u.Spec.By; by != nil {
+		return fmt.Sprintf("%s/%s uses %s", by.Kind, by.ResourceRef.Name, of)
+	}
+	return fmt.Sprintf("%s protected (%s)", of, *u.Spec.Reason)
+}
+
+// usedByOthers returns true if any of the supplied Usages other than u uses
+// the same resource.
+func usedByOthers(usages []v1alpha1.Usage, u *v1alpha1.Usage) bool {
+	for _, o := range usages {
+		if o.GetUID() != u.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+// ownedBy returns true if the supplied Usage has an owner reference to the
+// supplied resource.
+func ownedBy(u *v1alpha1.Usage, r *v1alpha1.Resource) bool {
+	for _, ref := range u.GetOwnerReferences() {
+		if ref.APIVersion == r.APIVersion && ref.Kind == r.Kind && ref.Name == r.ResourceRef.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasOwnerReference(o client.Object, uid types.UID) bool {
+	for _, ref := range o.GetOwnerReferences() {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
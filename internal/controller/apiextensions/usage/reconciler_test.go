@@ -0,0 +1,468 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
+	now := metav1.Now()
+
+	usedBy := func() v1alpha1.Usage {
+		return v1alpha1.Usage{
+			ObjectMeta: metav1.ObjectMeta{Name: "used-by-user", UID: "usage-uid"},
+			Spec: v1alpha1.UsageSpec{
+				Of: v1alpha1.Resource{
+					APIVersion:  "nop.crossplane.io/v1alpha1",
+					Kind:        "NopResource",
+					ResourceRef: &v1alpha1.ResourceRef{Name: "used"},
+				},
+				By: &v1alpha1.Resource{
+					APIVersion:  "nop.crossplane.io/v1alpha1",
+					Kind:        "NopResource",
+					ResourceRef: &v1alpha1.ResourceRef{Name: "user"},
+				},
+			},
+		}
+	}
+
+	// getFn returns the supplied Usage, and either errors or returns the
+	// named composed resources.
+	getFn := func(u v1alpha1.Usage, composedErr map[string]error) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha1.Usage:
+				*o = u
+				return nil
+			case *composed.Unstructured:
+				if err := composedErr[key.Name]; err != nil {
+					return err
+				}
+				o.SetName(key.Name)
+				o.SetUID(types.UID("uid-" + key.Name))
+				return nil
+			}
+			return errBoom
+		}
+	}
+
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+
+	type args struct {
+		mgr  manager.Manager
+		opts []ReconcilerOption
+	}
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UsageNotFound": {
+			reason: "We should not return an error if the Usage was not found.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(notFound),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GetUsageError": {
+			reason: "We should return any other error encountered while getting a Usage.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(errBoom),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetUsage),
+			},
+		},
+		"ResolveSelectorsError": {
+			reason: "We should return any error encountered while resolving selectors.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: getFn(usedBy(), nil),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errResolveSelectors),
+			},
+		},
+		"NoUsingOrReason": {
+			reason: "We should report an error if a Usage specifies neither a using resource nor a reason.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+							u := usedBy()
+							u.Spec.By = nil
+							return getFn(u, nil)(ctx, key, obj)
+						},
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(obj client.Object) error {
+							u := obj.(*v1alpha1.Usage)
+							if diff := cmp.Diff(errNoUsingOrReason, u.GetCondition("Synced").Message); diff != "" {
+								t.Errorf("StatusUpdate(...): -want message, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"AddFinalizerError": {
+			reason: "We should return any error encountered while adding a finalizer.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: getFn(usedBy(), nil),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return errBoom
+					}}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errAddFinalizer),
+			},
+		},
+		"GetUsedError": {
+			reason: "We should return any error encountered while getting the used resource.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: getFn(usedBy(), map[string]error{"used": errBoom}),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetUsed),
+			},
+		},
+		"AddInUseLabelError": {
+			reason: "We should return any error encountered while labelling the used resource as in use.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:    getFn(usedBy(), nil),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errAddInUseLabel),
+			},
+		},
+		"UsingNotFoundYet": {
+			reason: "We should wait for a using resource that doesn't exist yet.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:    getFn(usedBy(), map[string]error{"user": notFound}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: waitPoll},
+			},
+		},
+		"UsingGone": {
+			reason: "We should delete a Usage whose using resource is gone.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+							u := usedBy()
+							u.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "nop.crossplane.io/v1alpha1", Kind: "NopResource", Name: "user", UID: "uid-user"}})
+							return getFn(u, map[string]error{"user": notFound})(ctx, key, obj)
+						},
+						MockUpdate: test.NewMockUpdateFn(nil),
+						MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+							if _, ok := obj.(*v1alpha1.Usage); !ok {
+								t.Errorf("Delete(...): expected a Usage, got %T", obj)
+							}
+							return nil
+						},
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"SuccessfulWithUsing": {
+			reason: "We should label the used resource, and make the using resource an owner of the Usage.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: getFn(usedBy(), nil),
+						MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							switch o := obj.(type) {
+							case *composed.Unstructured:
+								if diff := cmp.Diff("true", o.GetLabels()[inUseLabelKey]); diff != "" {
+									t.Errorf("Update(...): -want in-use label, +got:\n%s", diff)
+								}
+							case *v1alpha1.Usage:
+								if !hasOwnerReference(o, "uid-user") {
+									t.Errorf("Update(...): Usage is not owned by the using resource")
+								}
+							}
+							return nil
+						},
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(obj client.Object) error {
+							u := obj.(*v1alpha1.Usage)
+							if diff := cmp.Diff("NopResource/user uses NopResource/used", u.GetAnnotations()[detailsAnnotationKey]); diff != "" {
+								t.Errorf("StatusUpdate(...): -want details, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"SuccessfulWithReason": {
+			reason: "We should label the used resource of a Usage with a reason.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+							u := usedBy()
+							u.Spec.By = nil
+							u.Spec.Reason = pointer.String("important")
+							return getFn(u, nil)(ctx, key, obj)
+						},
+						MockUpdate: test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(obj client.Object) error {
+							u := obj.(*v1alpha1.Usage)
+							if diff := cmp.Diff("NopResource/used protected (important)", u.GetAnnotations()[detailsAnnotationKey]); diff != "" {
+								t.Errorf("StatusUpdate(...): -want details, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeletedWaitingForUsing": {
+			reason: "We should wait for the using resource to be deleted before deleting a Usage.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+							u := usedBy()
+							u.SetDeletionTimestamp(&now)
+							return getFn(u, nil)(ctx, key, obj)
+						},
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: waitPoll},
+			},
+		},
+		"DeletedListUsagesError": {
+			reason: "We should return any error encountered while listing the Usages of the used resource.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+							u := usedBy()
+							u.SetDeletionTimestamp(&now)
+							return getFn(u, map[string]error{"user": notFound})(ctx, key, obj)
+						},
+						MockList: test.NewMockListFn(errBoom),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListUsages),
+			},
+		},
+		"DeletedUsedByOthers": {
+			reason: "We should not remove the in-use label from a resource that other Usages use.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+							u := usedBy()
+							u.SetDeletionTimestamp(&now)
+							if err := getFn(u, map[string]error{"user": notFound})(ctx, key, obj); err != nil {
+								return err
+							}
+							obj.SetLabels(map[string]string{inUseLabelKey: "true"})
+							return nil
+						},
+						MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+							obj.(*v1alpha1.UsageList).Items = []v1alpha1.Usage{usedBy(), {ObjectMeta: metav1.ObjectMeta{UID: "other-uid"}}}
+							return nil
+						},
+						MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							if diff := cmp.Diff("true", obj.GetLabels()[inUseLabelKey]); diff != "" {
+								t.Errorf("Update(...): -want in-use label, +got:\n%s", diff)
+							}
+							return nil
+						},
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+					WithFinalizer(resource.FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeletedRemoveFinalizerError": {
+			reason: "We should return any error encountered while removing the finalizer, once the in-use label is removed.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+							u := usedBy()
+							u.SetDeletionTimestamp(&now)
+							if err := getFn(u, map[string]error{"user": notFound})(ctx, key, obj); err != nil {
+								return err
+							}
+							obj.SetLabels(map[string]string{inUseLabelKey: "true"})
+							return nil
+						},
+						MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+							obj.(*v1alpha1.UsageList).Items = []v1alpha1.Usage{usedBy()}
+							return nil
+						},
+						MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							if _, ok := obj.GetLabels()[inUseLabelKey]; ok {
+								t.Errorf("Update(...): in-use label was not removed")
+							}
+							return nil
+						},
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(SelectorResolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })),
+					WithFinalizer(resource.FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error { return errBoom }}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errRemoveFinalizer),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.mgr, append(tc.args.opts, WithLogger(testLog))...)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+// Error strings.
+const (
+	errUpdateAfterResolveSelector = "cannot update usage after resolving selector"
+	errResolveSelectorForUsing    = "cannot resolve selector for using resource"
+	errResolveSelectorForUsed     = "cannot resolve selector for used resource"
+	errNoSelectorToResolve        = "no selector defined for resolving"
+	errListResourceMatchingLabels = "cannot list resources matching labels"
+
+	errFmtResourcesNotFound                  = "no %q found matching labels: %q"
+	errFmtResourcesNotFoundWithControllerRef = "no %q found matching labels: %q, and with same controller reference"
+)
+
+// A SelectorResolver resolves the selectors of a Usage to references.
+type SelectorResolver interface {
+	ResolveSelectors(ctx context.Context, u *v1alpha1.Usage) error
+}
+
+// A SelectorResolverFn is a function that satisfies SelectorResolver.
+type SelectorResolverFn func(ctx context.Context, u *v1alpha1.Usage) error
+
+// ResolveSelectors of the supplied Usage.
+func (fn SelectorResolverFn) ResolveSelectors(ctx context.Context, u *v1alpha1.Usage) error {
+	return fn(ctx, u)
+}
+
+// An APISelectorResolver resolves the selectors of a Usage by listing
+// resources from the API server.
+type APISelectorResolver struct {
+	client client.Client
+}
+
+// NewAPISelectorResolver returns a SelectorResolver that lists resources from
+// the API server.
+func NewAPISelectorResolver(c client.Client) *APISelectorResolver {
+	return &APISelectorResolver{client: c}
+}
+
+// ResolveSelectors of the supplied Usage. Selectors are only resolved once,
+// for resources that aren't already referenced. The Usage is updated if any
+// selector was resolved.
+func (r *APISelectorResolver) ResolveSelectors(ctx context.Context, u *v1alpha1.Usage) error {
+	of := u.Spec.Of
+	by := u.Spec.By
+
+	ofUnresolved := of.ResourceRef == nil || of.ResourceRef.Name == ""
+	byUnresolved := by != nil && (by.ResourceRef == nil || by.ResourceRef.Name == "")
+	if !ofUnresolved && !byUnresolved {
+		return nil
+	}
+
+	if ofUnresolved {
+		if err := r.resolveSelector(ctx, &u.Spec.Of, u); err != nil {
+			return errors.Wrap(err, errResolveSelectorForUsed)
+		}
+	}
+	if byUnresolved {
+		if err := r.resolveSelector(ctx, u.Spec.By, u); err != nil {
+			return errors.Wrap(err, errResolveSelectorForUsing)
+		}
+	}
+
+	return errors.Wrap(r.client.Update(ctx, u), errUpdateAfterResolveSelector)
+}
+
+func (r *APISelectorResolver) resolveSelector(ctx context.Context, rs *v1alpha1.Resource, u *v1alpha1.Usage) error {
+	if rs.ResourceSelector == nil {
+		return errors.New(errNoSelectorToResolve)
+	}
+
+	l := &kunstructured.UnstructuredList{}
+	l.SetAPIVersion(rs.APIVersion)
+	l.SetKind(rs.Kind + "List")
+	if err := r.client.List(ctx, l, client.MatchingLabels(rs.ResourceSelector.MatchLabels)); err != nil {
+		return errors.Wrap(err, errListResourceMatchingLabels)
+	}
+
+	matchController := rs.ResourceSelector.MatchControllerRef != nil && *rs.ResourceSelector.MatchControllerRef
+	for i := range l.Items {
+		o := &l.Items[i]
+		if matchController && !meta.HaveSameController(o, u) {
+			continue
+		}
+		rs.ResourceRef = &v1alpha1.ResourceRef{Name: o.GetName()}
+		return nil
+	}
+
+	if matchController {
+		return errors.Errorf(errFmtResourcesNotFoundWithControllerRef, rs.Kind, rs.ResourceSelector.MatchLabels)
+	}
+	return errors.Errorf(errFmtResourcesNotFound, rs.Kind, rs.ResourceSelector.MatchLabels)
+}
@@ -39,6 +39,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
@@ -85,6 +86,13 @@ const (
 	reasonInstall            event.Reason = "InstallPackageRevision"
 )
 
+// An endpointer is a package or package revision that serves an endpoint, for
+// example a Function that serves RunFunctionRequests over gRPC.
+type endpointer interface {
+	GetEndpoint() string
+	SetEndpoint(e string)
+}
+
 // ReconcilerOption is used to configure the Reconciler.
 type ReconcilerOption func(*Reconciler)
 
@@ -232,6 +240,39 @@ func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// SetupFunction adds a controller that reconciles Functions.
+func SetupFunction(mgr ctrl.Manager, o controller.Options) error {
+	name := "packages/" + strings.ToLower(v1alpha1.FunctionGroupKind)
+	np := func() v1.Package { return &v1alpha1.Function{} }
+	nr := func() v1.PackageRevision { return &v1alpha1.FunctionRevision{} }
+	nrl := func() v1.PackageRevisionList { return &v1alpha1.FunctionRevisionList{} }
+
+	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, errCreateK8sClient)
+	}
+	f, err := xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	if err != nil {
+		return errors.Wrap(err, errBuildFetcher)
+	}
+
+	r := NewReconciler(mgr,
+		WithNewPackageFn(np),
+		WithNewPackageRevisionFn(nr),
+		WithNewPackageRevisionListFn(nrl),
+		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.Function{}).
+		Owns(&v1alpha1.FunctionRevision{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
 // NewReconciler creates a new package reconciler.
 func NewReconciler(mgr ctrl.Manager, opts ...ReconcilerOption) *Reconciler {
 	r := &Reconciler{
@@ -381,6 +422,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnknownPackageRevisionHealth)))
 	}
 
+	// Packages that serve an endpoint publish the endpoint of their current
+	// revision.
+	if e, ok := p.(endpointer); ok {
+		if re, ok := pr.(endpointer); ok {
+			e.SetEndpoint(re.GetEndpoint())
+		}
+	}
+
 	// Create the non-existent package revision.
 	pr.SetName(revisionName)
 	pr.SetLabels(map[string]string{v1.LabelParentPackage: p.GetName()})
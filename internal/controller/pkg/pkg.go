@@ -24,6 +24,7 @@ import (
 	"github.com/crossplane/crossplane/internal/controller/pkg/manager"
 	"github.com/crossplane/crossplane/internal/controller/pkg/resolver"
 	"github.com/crossplane/crossplane/internal/controller/pkg/revision"
+	"github.com/crossplane/crossplane/internal/features"
 )
 
 // Setup package controllers.
@@ -39,5 +40,13 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 			return err
 		}
 	}
+	if o.Features.Enabled(features.EnableAlphaCompositionFunctions) {
+		if err := manager.SetupFunction(mgr, o); err != nil {
+			return err
+		}
+		if err := revision.SetupFunctionRevision(mgr, o); err != nil {
+			return err
+		}
+	}
 	return nil
 }
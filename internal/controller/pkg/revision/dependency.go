@@ -91,6 +91,9 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 		} else if dep.Provider != nil {
 			pdep.Package = *dep.Provider
 			pdep.Type = v1beta1.ProviderPackageType
+		} else if dep.Function != nil {
+			pdep.Package = *dep.Function
+			pdep.Type = v1beta1.FunctionPackageType
 		}
 		pdep.Constraints = dep.Version
 		sources[i] = pdep
@@ -356,6 +356,69 @@ func TestResolve(t *testing.T) {
 				err:   errors.Errorf(errFmtMissingDependencies, []string{"not-here-1", "not-here-2"}),
 			},
 		},
+		"ErrorSelfNotExistMissingFunctionDependency": {
+			reason: "Should add Function dependencies to the lock and return error if they are missing.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							want := []v1beta1.Dependency{{
+								Package:     "function-not-here",
+								Type:        v1beta1.FunctionPackageType,
+								Constraints: ">=v0.1.0",
+							}}
+							l := obj.(*v1beta1.Lock)
+							if diff := cmp.Diff(want, l.Packages[0].Dependencies); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(nodes []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockNodeExists: func(_ string) bool {
+								return false
+							},
+							MockAddNode: func(_ dag.Node) error {
+								return nil
+							},
+							MockAddOrUpdateNodes: func(_ ...dag.Node) {},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{
+								{
+									Function: pointer.String("function-not-here"),
+									Version:  ">=v0.1.0",
+								},
+							},
+						},
+					},
+				},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.PackageRevisionSpec{
+						Package:      "hasheddan/config-nop-a:v0.0.1",
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				total: 1,
+				err:   errors.Errorf(errFmtMissingDependencies, []string{"function-not-here"}),
+			},
+		},
 		"ErrorSelfExistMissingDependencies": {
 			reason: "Should return error if self exists and missing dependencies.",
 			args: args{
@@ -25,6 +25,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgmetav1alpha1 "github.com/crossplane/crossplane/apis/pkg/meta/v1alpha1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	"github.com/crossplane/crossplane/internal/initializer"
@@ -56,6 +57,12 @@ const (
 	essCertsDir         = "/ess/tls"
 )
 
+// Functions are expected to serve RunFunctionRequests over gRPC on this port.
+const (
+	grpcPortName   = "grpc"
+	grpcPortNumber = 9443
+)
+
 //nolint:gocyclo // TODO(negz): Can this be refactored for less complexity (and fewer arguments?)
 func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRevision, cc *v1alpha1.ControllerConfig, namespace string, pullSecrets []corev1.LocalObjectReference) (*corev1.ServiceAccount, *appsv1.Deployment, *corev1.Service) {
 	s := &corev1.ServiceAccount{
@@ -319,3 +326,96 @@ func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRe
 	}
 	return s, d, svc
 }
+
+// buildFunctionDeployment builds the ServiceAccount, Deployment and Service
+// used to run a long-running Function. The Function's runtime image is the
+// package image itself.
+func buildFunctionDeployment(function *pkgmetav1alpha1.Function, revision v1.PackageRevision, namespace string, pullSecrets []corev1.LocalObjectReference) (*corev1.ServiceAccount, *appsv1.Deployment, *corev1.Service) {
+	ref := meta.AsController(meta.TypedReferenceTo(revision, v1alpha1.FunctionRevisionGroupVersionKind))
+	s := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            revision.GetName(),
+			Namespace:       namespace,
+			OwnerReferences: []metav1.OwnerReference{ref},
+		},
+		ImagePullSecrets: pullSecrets,
+	}
+	pullPolicy := corev1.PullIfNotPresent
+	if revision.GetPackagePullPolicy() != nil {
+		pullPolicy = *revision.GetPackagePullPolicy()
+	}
+	labels := map[string]string{
+		"pkg.crossplane.io/revision": revision.GetName(),
+		"pkg.crossplane.io/function": function.GetName(),
+	}
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            revision.GetName(),
+			Namespace:       namespace,
+			OwnerReferences: []metav1.OwnerReference{ref},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      function.GetName(),
+					Namespace: namespace,
+					Labels:    labels,
+				},
+				Spec: corev1.PodSpec{
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: &runAsNonRoot,
+						RunAsUser:    &runAsUser,
+						RunAsGroup:   &runAsGroup,
+					},
+					ServiceAccountName: s.GetName(),
+					ImagePullSecrets:   revision.GetPackagePullSecrets(),
+					Containers: []corev1.Container{
+						{
+							Name:            function.GetName(),
+							Image:           revision.GetSource(),
+							ImagePullPolicy: pullPolicy,
+							SecurityContext: &corev1.SecurityContext{
+								RunAsUser:                &runAsUser,
+								RunAsGroup:               &runAsGroup,
+								AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+								Privileged:               &privileged,
+								RunAsNonRoot:             &runAsNonRoot,
+							},
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          promPortName,
+									ContainerPort: promPortNumber,
+								},
+								{
+									Name:          grpcPortName,
+									ContainerPort: grpcPortNumber,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            revision.GetName(),
+			Namespace:       namespace,
+			OwnerReferences: []metav1.OwnerReference{ref},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       grpcPortName,
+					Protocol:   corev1.ProtocolTCP,
+					Port:       grpcPortNumber,
+					TargetPort: intstr.FromString(grpcPortName),
+				},
+			},
+		},
+	}
+	return s, d, svc
+}
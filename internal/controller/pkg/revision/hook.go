@@ -18,6 +18,7 @@ package revision
 
 import (
 	"context"
+	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgmetav1alpha1 "github.com/crossplane/crossplane/apis/pkg/meta/v1alpha1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	"github.com/crossplane/crossplane/internal/xpkg"
@@ -45,6 +47,16 @@ const (
 	errApplyProviderSA               = "cannot apply provider package service account"
 	errApplyProviderService          = "cannot apply provider package service"
 	errUnavailableProviderDeployment = "provider package deployment is unavailable"
+
+	errNotFunction                   = "not a function package"
+	errNotFunctionRevision           = "not a function revision"
+	errDeleteFunctionDeployment      = "cannot delete function package deployment"
+	errDeleteFunctionSA              = "cannot delete function package service account"
+	errDeleteFunctionService         = "cannot delete function package service"
+	errApplyFunctionDeployment       = "cannot apply function package deployment"
+	errApplyFunctionSA               = "cannot apply function package service account"
+	errApplyFunctionService          = "cannot apply function package service"
+	errUnavailableFunctionDeployment = "function package deployment is unavailable"
 )
 
 // A Hooks performs operations before and after a revision establishes objects.
@@ -175,6 +187,91 @@ func (h *ProviderHooks) getControllerConfig(ctx context.Context, pr v1.PackageRe
 	return cc, errors.Wrap(err, errGetControllerConfig)
 }
 
+// FunctionHooks performs operations for a function package that requires a
+// long-running gRPC server before and after the revision establishes objects.
+type FunctionHooks struct {
+	client         resource.ClientApplicator
+	namespace      string
+	serviceAccount string
+}
+
+// NewFunctionHooks creates a new FunctionHooks.
+func NewFunctionHooks(client resource.ClientApplicator, namespace, serviceAccount string) *FunctionHooks {
+	return &FunctionHooks{
+		client:         client,
+		namespace:      namespace,
+		serviceAccount: serviceAccount,
+	}
+}
+
+// Pre cleans up a packaged function deployment, service account and service
+// if the revision is inactive.
+func (h *FunctionHooks) Pre(ctx context.Context, pkg runtime.Object, pr v1.PackageRevision) error {
+	pkgFunction, ok := pkg.(*pkgmetav1alpha1.Function)
+	if !ok {
+		return errors.New(errNotFunction)
+	}
+
+	// Do not clean up SA, deployment and service if revision is not inactive.
+	if pr.GetDesiredState() != v1.PackageRevisionInactive {
+		return nil
+	}
+
+	s, d, svc := buildFunctionDeployment(pkgFunction, pr, h.namespace, []corev1.LocalObjectReference{})
+	if err := h.client.Delete(ctx, d); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errDeleteFunctionDeployment)
+	}
+	if err := h.client.Delete(ctx, s); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errDeleteFunctionSA)
+	}
+	if err := h.client.Delete(ctx, svc); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errDeleteFunctionService)
+	}
+	return nil
+}
+
+// Post creates a packaged function deployment, service account and service if
+// the revision is active, and publishes the function's gRPC endpoint.
+func (h *FunctionHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.PackageRevision) error {
+	pkgFunction, ok := pkg.(*pkgmetav1alpha1.Function)
+	if !ok {
+		return errors.New(errNotFunction)
+	}
+	fnRev, ok := pr.(*v1alpha1.FunctionRevision)
+	if !ok {
+		return errors.New(errNotFunctionRevision)
+	}
+	if pr.GetDesiredState() != v1.PackageRevisionActive {
+		return nil
+	}
+	sa := &corev1.ServiceAccount{}
+	if err := h.client.Get(ctx, types.NamespacedName{Namespace: h.namespace, Name: h.serviceAccount}, sa); err != nil {
+		return errors.Wrap(err, errGetServiceAccount)
+	}
+	s, d, svc := buildFunctionDeployment(pkgFunction, pr, h.namespace, append(pr.GetPackagePullSecrets(), sa.ImagePullSecrets...))
+	if err := h.client.Apply(ctx, s); err != nil {
+		return errors.Wrap(err, errApplyFunctionSA)
+	}
+	if err := h.client.Apply(ctx, d); err != nil {
+		return errors.Wrap(err, errApplyFunctionDeployment)
+	}
+	if err := h.client.Apply(ctx, svc); err != nil {
+		return errors.Wrap(err, errApplyFunctionService)
+	}
+	pr.SetControllerReference(v1.ControllerReference{Name: d.GetName()})
+	fnRev.SetEndpoint(fmt.Sprintf("dns:///%s.%s:%d", svc.GetName(), svc.GetNamespace(), grpcPortNumber))
+
+	for _, c := range d.Status.Conditions {
+		if c.Type == appsv1.DeploymentAvailable {
+			if c.Status == corev1.ConditionTrue {
+				return nil
+			}
+			return errors.Errorf("%s: %s", errUnavailableFunctionDeployment, c.Message)
+		}
+	}
+	return nil
+}
+
 // ConfigurationHooks performs operations for a configuration package before and
 // after the revision establishes objects.
 type ConfigurationHooks struct{}
@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgmetav1alpha1 "github.com/crossplane/crossplane/apis/pkg/meta/v1alpha1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 )
@@ -272,6 +274,90 @@ func TestHookPre(t *testing.T) {
 				},
 			},
 		},
+		"ErrNotFunction": {
+			reason: "Should return error if not function.",
+			args: args{
+				hook: &FunctionHooks{},
+			},
+			want: want{
+				err: errors.New(errNotFunction),
+			},
+		},
+		"FunctionActive": {
+			reason: "Should do nothing if function revision is active.",
+			args: args{
+				hook: &FunctionHooks{},
+				pkg:  &pkgmetav1alpha1.Function{},
+				rev: &v1alpha1.FunctionRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1alpha1.FunctionRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+		},
+		"ErrFunctionDeleteService": {
+			reason: "Should return error if we fail to delete service for inactive function revision.",
+			args: args{
+				hook: &FunctionHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if _, ok := o.(*corev1.Service); ok {
+									return errBoom
+								}
+								return nil
+							}),
+						},
+					},
+				},
+				pkg: &pkgmetav1alpha1.Function{},
+				rev: &v1alpha1.FunctionRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionInactive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1alpha1.FunctionRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionInactive,
+					},
+				},
+				err: errors.Wrap(errBoom, errDeleteFunctionService),
+			},
+		},
+		"SuccessfulFunctionDelete": {
+			reason: "Should not return error when deployment, service account and service are deleted successfully.",
+			args: args{
+				hook: &FunctionHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+					},
+				},
+				pkg: &pkgmetav1alpha1.Function{},
+				rev: &v1alpha1.FunctionRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionInactive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1alpha1.FunctionRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionInactive,
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -642,6 +728,103 @@ func TestHookPost(t *testing.T) {
 				},
 			},
 		},
+		"ErrNotFunction": {
+			reason: "Should return error if not function.",
+			args: args{
+				hook: &FunctionHooks{},
+			},
+			want: want{
+				err: errors.New(errNotFunction),
+			},
+		},
+		"ErrNotFunctionRevision": {
+			reason: "Should return error if the supplied package revision is not a function revision.",
+			args: args{
+				hook: &FunctionHooks{},
+				pkg:  &pkgmetav1alpha1.Function{},
+				rev:  &v1.ProviderRevision{},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{},
+				err: errors.New(errNotFunctionRevision),
+			},
+		},
+		"ErrFunctionApplyService": {
+			reason: "Should return error if we fail to apply service for active function revision.",
+			args: args{
+				hook: &FunctionHooks{
+					namespace:      saNamespace,
+					serviceAccount: saName,
+					client: resource.ClientApplicator{
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if _, ok := o.(*corev1.Service); ok {
+								return errBoom
+							}
+							return nil
+						}),
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil),
+						},
+					},
+				},
+				pkg: &pkgmetav1alpha1.Function{},
+				rev: &v1alpha1.FunctionRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1alpha1.FunctionRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+				err: errors.Wrap(errBoom, errApplyFunctionService),
+			},
+		},
+		"SuccessfulFunctionApply": {
+			reason: "Should publish the function's endpoint if successfully applied service account, deployment and service for active function revision.",
+			args: args{
+				hook: &FunctionHooks{
+					namespace:      saNamespace,
+					serviceAccount: saName,
+					client: resource.ClientApplicator{
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil),
+						},
+					},
+				},
+				pkg: &pkgmetav1alpha1.Function{},
+				rev: &v1alpha1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-cool",
+					},
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				rev: &v1alpha1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "function-cool",
+					},
+					Spec: v1.PackageRevisionSpec{
+						DesiredState: v1.PackageRevisionActive,
+					},
+					Status: v1alpha1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							ControllerRef: v1.ControllerReference{Name: "function-cool"},
+						},
+						Endpoint: "dns:///function-cool.crossplane-system:9443",
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -46,8 +46,6 @@ const (
 )
 
 const (
-	layerAnnotation     = "io.crossplane.xpkg"
-	baseAnnotationValue = "base"
 	// maxLayers is the maximum number of layers an image can have.
 	maxLayers = 256
 )
@@ -117,7 +115,7 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	var tarc io.ReadCloser
 	foundAnnotated := false
 	for _, l := range manifest.Layers {
-		if a, ok := l.Annotations[layerAnnotation]; !ok || a != baseAnnotationValue {
+		if a, ok := l.Annotations[xpkg.AnnotationKey]; !ok || a != xpkg.PackageAnnotation {
 			continue
 		}
 		// NOTE(hasheddan): the xpkg specification dictates that only one layer
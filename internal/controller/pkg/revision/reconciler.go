@@ -313,6 +313,53 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// SetupFunctionRevision adds a controller that reconciles FunctionRevisions.
+func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
+	name := "packages/" + strings.ToLower(v1alpha1.FunctionRevisionGroupKind)
+	nr := func() v1.PackageRevision { return &v1alpha1.FunctionRevision{} }
+
+	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize host clientset with in cluster config")
+	}
+
+	metaScheme, err := xpkg.BuildMetaScheme()
+	if err != nil {
+		return errors.New("cannot build meta scheme for package parser")
+	}
+	objScheme, err := xpkg.BuildObjectScheme()
+	if err != nil {
+		return errors.New("cannot build object scheme for package parser")
+	}
+	f, err := xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	if err != nil {
+		return errors.Wrap(err, "cannot build fetcher for package parser")
+	}
+
+	r := NewReconciler(mgr,
+		WithCache(o.Cache),
+		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1beta1.FunctionPackageType)),
+		WithHooks(NewFunctionHooks(resource.ClientApplicator{
+			Client:     mgr.GetClient(),
+			Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
+		}, o.Namespace, o.ServiceAccount)),
+		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace)),
+		WithNewPackageRevisionFn(nr),
+		WithParser(parser.New(metaScheme, objScheme)),
+		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithLinter(xpkg.NewFunctionLinter()),
+		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.FunctionRevision{}).
+		Owns(&appsv1.Deployment{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
 // NewReconciler creates a new package revision reconciler.
 func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 
@@ -25,6 +25,10 @@ const (
 	// environments. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/c4bcbe/design/one-pager-composition-environment.md
 	EnableAlphaEnvironmentConfigs feature.Flag = "EnableAlphaEnvironmentConfigs"
+	// EnableAlphaEnvironmentSecrets enables alpha support for reading
+	// composition environment data from Secrets. It has no effect unless
+	// EnableAlphaEnvironmentConfigs is enabled too.
+	EnableAlphaEnvironmentSecrets feature.Flag = "EnableAlphaEnvironmentSecrets"
 	// EnableAlphaExternalSecretStores enables alpha support for
 	// External Secret Stores. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/390ddd/design/design-doc-external-secret-stores.md
@@ -40,4 +44,9 @@ const (
 	// details.
 	// https://github.com/crossplane/crossplane/blob/f32496bed53a393c8239376fd8266ddf2ef84d61/design/design-doc-composition-validating-webhook.md
 	EnableAlphaCompositionWebhookSchemaValidation feature.Flag = "EnableAlphaCompositionWebhookSchemaValidation"
+
+	// EnableAlphaUsages enables alpha support for deletion ordering and
+	// protection with Usage resource. See the below design for more details.
+	// https://github.com/crossplane/crossplane/blob/master/design/one-pager-generic-usage-type.md
+	EnableAlphaUsages feature.Flag = "EnableAlphaUsages"
 )
@@ -32,7 +32,14 @@ import (
 )
 
 const (
-	errApplyWebhookConfiguration = "cannot apply webhook configuration"
+	errApplyWebhookConfiguration  = "cannot apply webhook configuration"
+	errDeleteWebhookConfiguration = "cannot delete webhook configuration"
+)
+
+// The names controller-gen gives the webhook configurations it generates.
+const (
+	generatedValidatingWebhookConfigurationName = "validating-webhook-configuration"
+	generatedMutatingWebhookConfigurationName   = "mutating-webhook-configuration"
 )
 
 // WithWebhookConfigurationsFs is used to configure the filesystem the CRDs will
@@ -43,6 +50,18 @@ func WithWebhookConfigurationsFs(fs afero.Fs) WebhookConfigurationsOption {
 	}
 }
 
+// WithoutWebhookConfigurations configures the step not to install the named
+// webhook configurations, for example because the feature whose webhook they
+// configure is disabled. Any previously installed configuration with one of
+// the supplied names is deleted.
+func WithoutWebhookConfigurations(names ...string) WebhookConfigurationsOption {
+	return func(c *WebhookConfigurations) {
+		for _, n := range names {
+			c.skip[n] = true
+		}
+	}
+}
+
 // WebhookConfigurationsOption configures WebhookConfigurations step.
 type WebhookConfigurationsOption func(*WebhookConfigurations)
 
@@ -54,6 +73,7 @@ func NewWebhookConfigurations(path string, s *runtime.Scheme, tlsSecretRef types
 		TLSSecretRef:     tlsSecretRef,
 		ServiceReference: svc,
 		fs:               afero.NewOsFs(),
+		skip:             make(map[string]bool),
 	}
 	for _, f := range opts {
 		f(c)
@@ -69,7 +89,8 @@ type WebhookConfigurations struct {
 	TLSSecretRef     types.NamespacedName
 	ServiceReference admv1.ServiceReference
 
-	fs afero.Fs
+	fs   afero.Fs
+	skip map[string]bool
 }
 
 // Run applies all webhook ValidatingWebhookConfigurations and
@@ -103,6 +124,12 @@ func (c *WebhookConfigurations) Run(ctx context.Context, kube client.Client) err
 	}
 	pa := resource.NewAPIPatchingApplicator(kube)
 	for _, obj := range pkg.GetObjects() {
+		if o, ok := obj.(client.Object); ok && c.skip[o.GetName()] {
+			if err := kube.Delete(ctx, o); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err, errDeleteWebhookConfiguration)
+			}
+			continue
+		}
 		switch conf := obj.(type) {
 		case *admv1.ValidatingWebhookConfiguration:
 			for i := range conf.Webhooks {
@@ -112,7 +139,9 @@ func (c *WebhookConfigurations) Run(ctx context.Context, kube client.Client) err
 				conf.Webhooks[i].ClientConfig.Service.Port = c.ServiceReference.Port
 			}
 			// See https://github.com/kubernetes-sigs/controller-tools/issues/658
-			conf.SetName("crossplane")
+			if conf.GetName() == generatedValidatingWebhookConfigurationName {
+				conf.SetName("crossplane")
+			}
 		case *admv1.MutatingWebhookConfiguration:
 			for i := range conf.Webhooks {
 				conf.Webhooks[i].ClientConfig.CABundle = caBundle
@@ -121,7 +150,9 @@ func (c *WebhookConfigurations) Run(ctx context.Context, kube client.Client) err
 				conf.Webhooks[i].ClientConfig.Service.Port = c.ServiceReference.Port
 			}
 			// See https://github.com/kubernetes-sigs/controller-tools/issues/658
-			conf.SetName("crossplane")
+			if conf.GetName() == generatedMutatingWebhookConfigurationName {
+				conf.SetName("crossplane")
+			}
 		default:
 			return errors.Errorf("only MutatingWebhookConfiguration and ValidatingWebhookConfiguration kinds are accepted, got %T", obj)
 		}
@@ -107,6 +107,35 @@ func TestWebhookConfigurations(t *testing.T) {
 				},
 			},
 		},
+		"SkipDisabled": {
+			reason: "Webhook configurations that should not be installed should be deleted rather than applied",
+			args: args{
+				opts: []WebhookConfigurationsOption{
+					WithWebhookConfigurationsFs(fs),
+					WithoutWebhookConfigurations("validating-webhook-configuration"),
+				},
+				svc: svc,
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if s, ok := obj.(*corev1.Secret); ok {
+							secret.DeepCopyInto(s)
+							return nil
+						}
+						return kerrors.NewNotFound(schema.GroupResource{}, "")
+					},
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if obj.GetName() != "validating-webhook-configuration" {
+							t.Errorf("unexpected delete of %q", obj.GetName())
+						}
+						return kerrors.NewNotFound(schema.GroupResource{}, "")
+					},
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						t.Errorf("unexpected create of %q", obj.GetName())
+						return nil
+					},
+				},
+			},
+		},
 		"CertNotFound": {
 			reason: "If TLS Secret cannot be found, then it should not proceed",
 			args: args{
@@ -0,0 +1,170 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usage contains the admission webhook that blocks deletion of
+// resources that are in use.
+package usage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+const (
+	// InUseIndexKey is the key of the index of Usages by the resource they
+	// use. Its values are produced by IndexValueForObject.
+	InUseIndexKey = "inuse.apiversion.kind.name"
+
+	// WebhookPath is the path the webhook is served at. It must match the
+	// path in the webhook configuration.
+	WebhookPath = "/validate-no-usages"
+
+	// WebhookConfigurationName is the name of the webhook configuration. It
+	// must match the name in the webhook configuration.
+	WebhookConfigurationName = "crossplane-no-usages"
+)
+
+// Error strings.
+const (
+	errFmtUnexpectedOp = "unexpected operation %q, expected \"DELETE\""
+	errUnmarshal       = "cannot unmarshal object"
+	errListUsages      = "cannot list Usages"
+)
+
+// IndexValueForObject returns the value under which Usages of the supplied
+// object are indexed.
+func IndexValueForObject(u *unstructured.Unstructured) string {
+	return indexValue(u.GetAPIVersion(), u.GetKind(), u.GetName())
+}
+
+// IndexUsageOf indexes the supplied Usage by the resource it uses. Usages that
+// haven't resolved a reference to the resource they use aren't indexed.
+func IndexUsageOf(o client.Object) []string {
+	u, ok := o.(*v1alpha1.Usage)
+	if !ok || u.Spec.Of.ResourceRef == nil || u.Spec.Of.ResourceRef.Name == "" {
+		return nil
+	}
+	return []string{indexValue(u.Spec.Of.APIVersion, u.Spec.Of.Kind, u.Spec.Of.ResourceRef.Name)}
+}
+
+func indexValue(apiVersion, kind, name string) string {
+	return fmt.Sprintf("%s.%s.%s", apiVersion, kind, name)
+}
+
+// SetupWebhookWithManager sets up the webhook with the manager. The webhook
+// relies on the InUseIndexKey index, which is set up by the Usage controller.
+func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options) error {
+	h := NewHandler(mgr.GetClient(), WithLogger(options.Logger.WithValues("webhook", "no-usages")))
+	mgr.GetWebhookServer().Register(WebhookPath, &webhook.Admission{Handler: h})
+	return nil
+}
+
+// HandlerOption is used to configure the Handler.
+type HandlerOption func(*Handler)
+
+// WithLogger configures the logger for the Handler.
+func WithLogger(l logging.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.log = l
+	}
+}
+
+// Handler implements the admission Handler for Usage.
+type Handler struct {
+	reader client.Reader
+	log    logging.Logger
+}
+
+// NewHandler returns a new Handler.
+func NewHandler(reader client.Reader, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		reader: reader,
+		log:    logging.NewNopLogger(),
+	}
+
+	for _, f := range opts {
+		f(h)
+	}
+	return h
+}
+
+// Handle handles the admission request, validating there is no Usage of the
+// resource being deleted.
+func (h *Handler) Handle(ctx context.Context, request admission.Request) admission.Response {
+	if request.Operation != admissionv1.Delete {
+		return admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, request.Operation))
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(request.OldObject.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errUnmarshal))
+	}
+	return h.validateNoUsages(ctx, u)
+}
+
+func (h *Handler) validateNoUsages(ctx context.Context, u *unstructured.Unstructured) admission.Response {
+	log := h.log.WithValues("apiVersion", u.GetAPIVersion(), "kind", u.GetKind(), "name", u.GetName())
+	log.Debug("Validating no usages")
+
+	l := &v1alpha1.UsageList{}
+	if err := h.reader.List(ctx, l, client.MatchingFields{InUseIndexKey: IndexValueForObject(u)}); err != nil {
+		log.Debug(errListUsages, "error", err)
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errListUsages))
+	}
+	if len(l.Items) == 0 {
+		return admission.Allowed("")
+	}
+
+	msg := inUseMessage(l.Items)
+	log.Debug("Resource is in use, rejecting deletion", "message", msg)
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Code:    int32(http.StatusConflict),
+				Reason:  metav1.StatusReasonConflict,
+				Message: msg,
+			},
+		},
+	}
+}
+
+// inUseMessage explains why a resource used by the supplied Usages can't be
+// deleted.
+func inUseMessage(usages []v1alpha1.Usage) string {
+	first := usages[0]
+	if by := first.Spec.By; by != nil && by.ResourceRef != nil {
+		return fmt.Sprintf("This resource is in-use by %d Usage(s), including the Usage %q by resource %s/%s.", len(usages), first.GetName(), by.Kind, by.ResourceRef.Name)
+	}
+	if first.Spec.Reason != nil {
+		return fmt.Sprintf("This resource is in-use by %d Usage(s), including the Usage %q with reason: %q.", len(usages), first.GetName(), *first.Spec.Reason)
+	}
+	return fmt.Sprintf("This resource is in-use by %d Usage(s), including the Usage %q.", len(usages), first.GetName())
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+var _ admission.Handler = &Handler{}
+
+func TestHandle(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	used := []byte(`{"apiVersion":"nop.crossplane.io/v1alpha1","kind":"NopResource","metadata":{"name":"used"}}`)
+
+	type args struct {
+		reader  client.Reader
+		request admission.Request
+	}
+	type want struct {
+		resp admission.Response
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnexpectedOperation": {
+			reason: "We should return an error if the request isn't a DELETE.",
+			args: args{
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Create,
+					},
+				},
+			},
+			want: want{
+				resp: admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, admissionv1.Create)),
+			},
+		},
+		"UnmarshalError": {
+			reason: "We should return an error if we can't unmarshal the object being deleted.",
+			args: args{
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Delete,
+						OldObject: runtime.RawExtension{Raw: []byte("wat")},
+					},
+				},
+			},
+			want: want{
+				resp: admission.Errored(http.StatusBadRequest, errors.Wrap(errors.New("invalid character 'w' looking for beginning of value"), errUnmarshal)),
+			},
+		},
+		"ListUsagesError": {
+			reason: "We should return an error if we can't list Usages.",
+			args: args{
+				reader: &test.MockClient{
+					MockList: test.NewMockListFn(errBoom),
+				},
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Delete,
+						OldObject: runtime.RawExtension{Raw: used},
+					},
+				},
+			},
+			want: want{
+				resp: admission.Errored(http.StatusInternalServerError, errors.Wrap(errBoom, errListUsages)),
+			},
+		},
+		"NotInUse": {
+			reason: "We should allow the deletion of a resource that isn't used by any Usage.",
+			args: args{
+				reader: &test.MockClient{
+					MockList: func(_ context.Context, _ client.ObjectList, opts ...client.ListOption) error {
+						o := &client.ListOptions{}
+						o.ApplyOptions(opts)
+						if diff := cmp.Diff("inuse.apiversion.kind.name=nop.crossplane.io/v1alpha1.NopResource.used", o.FieldSelector.String()); diff != "" {
+							t.Errorf("MockList(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Delete,
+						OldObject: runtime.RawExtension{Raw: used},
+					},
+				},
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"InUseByResource": {
+			reason: "We should reject the deletion of a resource that is used by another resource.",
+			args: args{
+				reader: &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+						l := obj.(*v1alpha1.UsageList)
+						l.Items = []v1alpha1.Usage{{
+							ObjectMeta: metav1.ObjectMeta{Name: "used-by-user"},
+							Spec: v1alpha1.UsageSpec{
+								Of: v1alpha1.Resource{
+									APIVersion:  "nop.crossplane.io/v1alpha1",
+									Kind:        "NopResource",
+									ResourceRef: &v1alpha1.ResourceRef{Name: "used"},
+								},
+								By: &v1alpha1.Resource{
+									APIVersion:  "nop.crossplane.io/v1alpha1",
+									Kind:        "NopResource",
+									ResourceRef: &v1alpha1.ResourceRef{Name: "user"},
+								},
+							},
+						}}
+						return nil
+					},
+				},
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Delete,
+						OldObject: runtime.RawExtension{Raw: used},
+					},
+				},
+			},
+			want: want{
+				resp: admission.Response{
+					AdmissionResponse: admissionv1.AdmissionResponse{
+						Allowed: false,
+						Result: &metav1.Status{
+							Code:    int32(http.StatusConflict),
+							Reason:  metav1.StatusReasonConflict,
+							Message: `This resource is in-use by 1 Usage(s), including the Usage "used-by-user" by resource NopResource/user.`,
+						},
+					},
+				},
+			},
+		},
+		"InUseWithReason": {
+			reason: "We should reject the deletion of a resource that is protected by a Usage with a reason.",
+			args: args{
+				reader: &test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+						l := obj.(*v1alpha1.UsageList)
+						l.Items = []v1alpha1.Usage{{
+							ObjectMeta: metav1.ObjectMeta{Name: "protect-used"},
+							Spec: v1alpha1.UsageSpec{
+								Of: v1alpha1.Resource{
+									APIVersion:  "nop.crossplane.io/v1alpha1",
+									Kind:        "NopResource",
+									ResourceRef: &v1alpha1.ResourceRef{Name: "used"},
+								},
+								Reason: pointer.String("Very important!"),
+							},
+						}}
+						return nil
+					},
+				},
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Delete,
+						OldObject: runtime.RawExtension{Raw: used},
+					},
+				},
+			},
+			want: want{
+				resp: admission.Response{
+					AdmissionResponse: admissionv1.AdmissionResponse{
+						Allowed: false,
+						Result: &metav1.Status{
+							Code:    int32(http.StatusConflict),
+							Reason:  metav1.StatusReasonConflict,
+							Message: `This resource is in-use by 1 Usage(s), including the Usage "protect-used" with reason: "Very important!".`,
+						},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(tc.args.reader)
+			got := h.Handle(context.Background(), tc.args.request)
+			if diff := cmp.Diff(tc.want.resp, got); diff != "" {
+				t.Errorf("%s\nHandle(...): -want response, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -54,6 +54,11 @@ const (
 	errFmtGetCRDs     = "cannot get the needed CRDs: %v"
 )
 
+// Warning strings.
+const (
+	warnPipelineModeDisabled = "Composition uses the Pipeline mode, but Composition Functions are not enabled. Its pipeline will be ignored."
+)
+
 // SetupWebhookWithManager sets up the webhook with the manager.
 func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options) error {
 	if options.Features.Enabled(features.EnableAlphaCompositionWebhookSchemaValidation) {
@@ -92,6 +97,12 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (adm
 		return warns, apierrors.NewInvalid(comp.GroupVersionKind().GroupKind(), comp.GetName(), validationErrs)
 	}
 
+	// The Pipeline mode is an alpha feature, and has no effect unless
+	// Composition Functions are enabled.
+	if comp.Spec.GetMode() == v1.CompositionModePipeline && !v.options.Features.Enabled(features.EnableAlphaCompositionFunctions) {
+		warns = append(warns, warnPipelineModeDisabled)
+	}
+
 	if !v.options.Features.Enabled(features.EnableAlphaCompositionWebhookSchemaValidation) {
 		return warns, nil
 	}
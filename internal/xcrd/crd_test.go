@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
@@ -305,6 +306,8 @@ func TestForCompositeResource(t *testing.T) {
 													"apiVersion": {Type: "string"},
 													"name":       {Type: "string"},
 													"kind":       {Type: "string"},
+													"namespace":  {Type: "string"},
+													"fieldPath":  {Type: "string"},
 												},
 												Required: []string{"apiVersion", "kind"},
 											},
@@ -408,6 +411,56 @@ func TestForCompositeResource(t *testing.T) {
 											"lastPublishedTime": {Type: "string", Format: "date-time"},
 										},
 									},
+									"environment": {
+										Description: "Environment is the merged Composition environment, and the sources of each of its top-level keys. It's only recorded when the Composition allows debugging its environment and the crossplane.io/debug-environment annotation is \"true\". Values read from Secrets are redacted.",
+										Type:        "object",
+										Properties: map[string]extv1.JSONSchemaProps{
+											"data": {
+												Type:                   "object",
+												XPreserveUnknownFields: pointer.Bool(true),
+											},
+											"sources": {
+												Type: "object",
+												AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+													Allows: true,
+													Schema: &extv1.JSONSchemaProps{
+														Type: "array",
+														Items: &extv1.JSONSchemaPropsOrArray{
+															Schema: &extv1.JSONSchemaProps{
+																Type: "object",
+																Properties: map[string]extv1.JSONSchemaProps{
+																	"apiVersion": {Type: "string"},
+																	"kind":       {Type: "string"},
+																	"name":       {Type: "string"},
+																	"namespace":  {Type: "string"},
+																	"fieldPath":  {Type: "string"},
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+									"functionResults": {
+										Description: "FunctionResults records the outcome of each step of the Composition Function pipeline.",
+										Type:        "array",
+										Items: &extv1.JSONSchemaPropsOrArray{
+											Schema: &extv1.JSONSchemaProps{
+												Type:     "object",
+												Required: []string{"step", "function", "duration"},
+												Properties: map[string]extv1.JSONSchemaProps{
+													"step":     {Type: "string"},
+													"function": {Type: "string"},
+													"duration": {Type: "string"},
+													"normal":   {Type: "integer"},
+													"warning":  {Type: "integer"},
+													"fatal":    {Type: "integer"},
+													"message":  {Type: "string"},
+												},
+											},
+										},
+									},
 								},
 								XValidations: extv1.ValidationRules{
 									{
@@ -608,6 +661,8 @@ func TestForCompositeResourceEmptyXrd(t *testing.T) {
 													"apiVersion": {Type: "string"},
 													"name":       {Type: "string"},
 													"kind":       {Type: "string"},
+													"namespace":  {Type: "string"},
+													"fieldPath":  {Type: "string"},
 												},
 												Required: []string{"apiVersion", "kind"},
 											},
@@ -704,6 +759,56 @@ func TestForCompositeResourceEmptyXrd(t *testing.T) {
 											"lastPublishedTime": {Type: "string", Format: "date-time"},
 										},
 									},
+									"environment": {
+										Description: "Environment is the merged Composition environment, and the sources of each of its top-level keys. It's only recorded when the Composition allows debugging its environment and the crossplane.io/debug-environment annotation is \"true\". Values read from Secrets are redacted.",
+										Type:        "object",
+										Properties: map[string]extv1.JSONSchemaProps{
+											"data": {
+												Type:                   "object",
+												XPreserveUnknownFields: pointer.Bool(true),
+											},
+											"sources": {
+												Type: "object",
+												AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+													Allows: true,
+													Schema: &extv1.JSONSchemaProps{
+														Type: "array",
+														Items: &extv1.JSONSchemaPropsOrArray{
+															Schema: &extv1.JSONSchemaProps{
+																Type: "object",
+																Properties: map[string]extv1.JSONSchemaProps{
+																	"apiVersion": {Type: "string"},
+																	"kind":       {Type: "string"},
+																	"name":       {Type: "string"},
+																	"namespace":  {Type: "string"},
+																	"fieldPath":  {Type: "string"},
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+									"functionResults": {
+										Description: "FunctionResults records the outcome of each step of the Composition Function pipeline.",
+										Type:        "array",
+										Items: &extv1.JSONSchemaPropsOrArray{
+											Schema: &extv1.JSONSchemaProps{
+												Type:     "object",
+												Required: []string{"step", "function", "duration"},
+												Properties: map[string]extv1.JSONSchemaProps{
+													"step":     {Type: "string"},
+													"function": {Type: "string"},
+													"duration": {Type: "string"},
+													"normal":   {Type: "integer"},
+													"warning":  {Type: "integer"},
+													"fatal":    {Type: "integer"},
+													"message":  {Type: "string"},
+												},
+											},
+										},
+									},
 								},
 							},
 						},
@@ -1145,6 +1250,56 @@ func TestForCompositeResourceClaim(t *testing.T) {
 												"lastPublishedTime": {Type: "string", Format: "date-time"},
 											},
 										},
+										"environment": {
+											Description: "Environment is the merged Composition environment, and the sources of each of its top-level keys. It's only recorded when the Composition allows debugging its environment and the crossplane.io/debug-environment annotation is \"true\". Values read from Secrets are redacted.",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"data": {
+													Type:                   "object",
+													XPreserveUnknownFields: pointer.Bool(true),
+												},
+												"sources": {
+													Type: "object",
+													AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+														Allows: true,
+														Schema: &extv1.JSONSchemaProps{
+															Type: "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "object",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"apiVersion": {Type: "string"},
+																		"kind":       {Type: "string"},
+																		"name":       {Type: "string"},
+																		"namespace":  {Type: "string"},
+																		"fieldPath":  {Type: "string"},
+																	},
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+										"functionResults": {
+											Description: "FunctionResults records the outcome of each step of the Composition Function pipeline.",
+											Type:        "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type:     "object",
+													Required: []string{"step", "function", "duration"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"step":     {Type: "string"},
+														"function": {Type: "string"},
+														"duration": {Type: "string"},
+														"normal":   {Type: "integer"},
+														"warning":  {Type: "integer"},
+														"fatal":    {Type: "integer"},
+														"message":  {Type: "string"},
+													},
+												},
+											},
+										},
 									},
 									XValidations: extv1.ValidationRules{
 										{
@@ -1429,6 +1584,56 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 												"lastPublishedTime": {Type: "string", Format: "date-time"},
 											},
 										},
+										"environment": {
+											Description: "Environment is the merged Composition environment, and the sources of each of its top-level keys. It's only recorded when the Composition allows debugging its environment and the crossplane.io/debug-environment annotation is \"true\". Values read from Secrets are redacted.",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"data": {
+													Type:                   "object",
+													XPreserveUnknownFields: pointer.Bool(true),
+												},
+												"sources": {
+													Type: "object",
+													AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+														Allows: true,
+														Schema: &extv1.JSONSchemaProps{
+															Type: "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "object",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"apiVersion": {Type: "string"},
+																		"kind":       {Type: "string"},
+																		"name":       {Type: "string"},
+																		"namespace":  {Type: "string"},
+																		"fieldPath":  {Type: "string"},
+																	},
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+										"functionResults": {
+											Description: "FunctionResults records the outcome of each step of the Composition Function pipeline.",
+											Type:        "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type:     "object",
+													Required: []string{"step", "function", "duration"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"step":     {Type: "string"},
+														"function": {Type: "string"},
+														"duration": {Type: "string"},
+														"normal":   {Type: "integer"},
+														"warning":  {Type: "integer"},
+														"fatal":    {Type: "integer"},
+														"message":  {Type: "string"},
+													},
+												},
+											},
+										},
 									},
 								},
 							},
@@ -16,7 +16,10 @@ limitations under the License.
 
 package xcrd
 
-import extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
 
 // Label keys.
 const (
@@ -135,6 +138,8 @@ func CompositeResourceSpecProps() map[string]extv1.JSONSchemaProps {
 						"apiVersion": {Type: "string"},
 						"name":       {Type: "string"},
 						"kind":       {Type: "string"},
+						"namespace":  {Type: "string"},
+						"fieldPath":  {Type: "string"},
 					},
 					Required: []string{"apiVersion", "kind"},
 				},
@@ -347,6 +352,56 @@ func CompositeResourceStatusProps() map[string]extv1.JSONSchemaProps {
 				"lastPublishedTime": {Type: "string", Format: "date-time"},
 			},
 		},
+		"environment": {
+			Description: "Environment is the merged Composition environment, and the sources of each of its top-level keys. It's only recorded when the Composition allows debugging its environment and the crossplane.io/debug-environment annotation is \"true\". Values read from Secrets are redacted.",
+			Type:        "object",
+			Properties: map[string]extv1.JSONSchemaProps{
+				"data": {
+					Type:                   "object",
+					XPreserveUnknownFields: pointer.Bool(true),
+				},
+				"sources": {
+					Type: "object",
+					AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+						Allows: true,
+						Schema: &extv1.JSONSchemaProps{
+							Type: "array",
+							Items: &extv1.JSONSchemaPropsOrArray{
+								Schema: &extv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"apiVersion": {Type: "string"},
+										"kind":       {Type: "string"},
+										"name":       {Type: "string"},
+										"namespace":  {Type: "string"},
+										"fieldPath":  {Type: "string"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"functionResults": {
+			Description: "FunctionResults records the outcome of each step of the Composition Function pipeline.",
+			Type:        "array",
+			Items: &extv1.JSONSchemaPropsOrArray{
+				Schema: &extv1.JSONSchemaProps{
+					Type:     "object",
+					Required: []string{"step", "function", "duration"},
+					Properties: map[string]extv1.JSONSchemaProps{
+						"step":     {Type: "string"},
+						"function": {Type: "string"},
+						"duration": {Type: "string"},
+						"normal":   {Type: "integer"},
+						"warning":  {Type: "integer"},
+						"fatal":    {Type: "integer"},
+						"message":  {Type: "string"},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -37,6 +37,7 @@ const (
 	errInitBackend   = "failed to initialize package parsing backend"
 	errTarFromStream = "failed to build tarball from package stream"
 	errLayerFromTar  = "failed to convert tarball to image layer"
+	errAppendLayer   = "failed to append package layer to base image"
 )
 
 // annotatedTeeReadCloser is a copy of io.TeeReader that implements
@@ -78,8 +79,29 @@ func (t *teeReader) Annotate() any {
 	return anno.Annotate()
 }
 
+// A BuildOption modifies how a package is built.
+type BuildOption func(o *buildOpts)
+
+type buildOpts struct {
+	base v1.Image
+}
+
+// WithBase sets the base image of a package. The package's YAML stream is added
+// to the base image as an annotated layer. This allows a package to include
+// the runtime of a Function. Packages are built from scratch by default.
+func WithBase(img v1.Image) BuildOption {
+	return func(o *buildOpts) {
+		o.base = img
+	}
+}
+
 // Build compiles a Crossplane package from an on-disk package.
-func Build(ctx context.Context, b parser.Backend, p parser.Parser, l parser.Linter) (v1.Image, error) {
+func Build(ctx context.Context, b parser.Backend, p parser.Parser, l parser.Linter, opts ...BuildOption) (v1.Image, error) {
+	bo := &buildOpts{base: empty.Image}
+	for _, o := range opts {
+		o(bo)
+	}
+
 	// Get YAML stream.
 	r, err := b.Init(ctx)
 	if err != nil {
@@ -130,8 +152,13 @@ func Build(ctx context.Context, b parser.Backend, p parser.Parser, l parser.Lint
 		return nil, errors.Wrap(err, errLayerFromTar)
 	}
 
-	// Append layer to to scratch image.
-	return mutate.AppendLayers(empty.Image, layer)
+	// Append layer to the base image. We annotate the layer so that it can be
+	// distinguished from the layers of the base image.
+	img, err := mutate.Append(bo.base, mutate.Addendum{
+		Layer:       layer,
+		Annotations: map[string]string{AnnotationKey: PackageAnnotation},
+	})
+	return img, errors.Wrap(err, errAppendLayer)
 }
 
 // copyChunks pleases gosec per https://github.com/securego/gosec/pull/433.
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/v1/random"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/parser"
@@ -133,3 +134,29 @@ func TestBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildWithBase(t *testing.T) {
+	base, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := Build(context.TODO(), parser.NewEchoBackend(""), p, parser.NewPackageLinter(nil, nil, nil), WithBase(base))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := img.Manifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The package layer should be appended to the layers of the base image.
+	if diff := cmp.Diff(3, len(m.Layers)); diff != "" {
+		t.Errorf("\nBuild(...): -want layers, +got layers:\n%s", diff)
+	}
+	want := map[string]string{AnnotationKey: PackageAnnotation}
+	if diff := cmp.Diff(want, m.Layers[2].Annotations); diff != "" {
+		t.Errorf("\nBuild(...): -want annotations, +got annotations:\n%s", diff)
+	}
+}
@@ -65,9 +65,9 @@ func NewConfigurationLinter() parser.Linter {
 }
 
 // NewFunctionLinter is a convenience function for creating a package linter for
-// functions.
+// functions. Functions may include CRDs that describe their input.
 func NewFunctionLinter() parser.Linter {
-	return parser.NewPackageLinter(parser.PackageLinterFns(OneMeta), parser.ObjectLinterFns(IsFunction, PackageValidSemver), parser.ObjectLinterFns())
+	return parser.NewPackageLinter(parser.PackageLinterFns(OneMeta), parser.ObjectLinterFns(IsFunction, PackageValidSemver), parser.ObjectLinterFns(IsCRD))
 }
 
 // OneMeta checks that there is only one meta object in the package.
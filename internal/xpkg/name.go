@@ -42,6 +42,14 @@ const (
 
 	// XpkgMatchPattern is the match pattern for identifying compiled Crossplane packages.
 	XpkgMatchPattern string = "*" + XpkgExtension
+
+	// AnnotationKey is the key of the annotation used to identify the layer
+	// of a Crossplane package image that contains its YAML stream.
+	AnnotationKey string = "io.crossplane.xpkg"
+
+	// PackageAnnotation is the value of the annotation used to identify the
+	// layer of a Crossplane package image that contains its YAML stream.
+	PackageAnnotation string = "base"
 )
 
 const (
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+)
+
+// validateCELExpressions validates that all the CEL expressions used by the
+// transforms and readiness checks of a composition compile. It doesn't need
+// any CRDs to do so.
+func validateCELExpressions(comp *v1.Composition) (errs field.ErrorList) {
+	for i, ps := range comp.Spec.PatchSets {
+		for j, p := range ps.Patches {
+			errs = append(errs, validateCELTransforms(p.Transforms, field.NewPath("spec", "patchSets").Index(i).Child("patches").Index(j))...)
+		}
+	}
+	for i, r := range comp.Spec.Resources {
+		path := field.NewPath("spec", "resources").Index(i)
+		for j, p := range r.Patches {
+			errs = append(errs, validateCELTransforms(p.Transforms, path.Child("patches").Index(j))...)
+		}
+		for j, cd := range r.ConnectionDetails {
+			errs = append(errs, validateCELTransforms(cd.Transforms, path.Child("connectionDetails").Index(j))...)
+		}
+		for j, rc := range r.ReadinessChecks {
+			if rc.Type != v1.ReadinessCheckTypeCEL || rc.CEL == nil {
+				continue
+			}
+			if _, err := composite.CompileCELReadinessCheck(rc.CEL.Expression); err != nil {
+				errs = append(errs, field.Invalid(path.Child("readinessChecks").Index(j).Child("cel", "expression"), rc.CEL.Expression, err.Error()))
+			}
+		}
+	}
+	if comp.Spec.Environment != nil {
+		for i, p := range comp.Spec.Environment.Patches {
+			errs = append(errs, validateCELTransforms(p.Transforms, field.NewPath("spec", "environment", "patches").Index(i))...)
+		}
+	}
+	return errs
+}
+
+func validateCELTransforms(transforms []v1.Transform, path *field.Path) (errs field.ErrorList) {
+	for i, t := range transforms {
+		if t.Type != v1.TransformTypeCEL || t.CEL == nil {
+			continue
+		}
+		if _, err := composite.CompileCELTransform(t.CEL.Expression); err != nil {
+			errs = append(errs, field.Invalid(path.Child("transforms").Index(i).Child("cel", "expression"), t.CEL.Expression, err.Error()))
+		}
+	}
+	return errs
+}
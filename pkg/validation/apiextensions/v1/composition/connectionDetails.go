@@ -23,10 +23,12 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	verrors "github.com/crossplane/crossplane/internal/validation/errors"
+	xpschema "github.com/crossplane/crossplane/pkg/validation/internal/schema"
 )
 
 // validateConnectionDetailsWithSchemas validates the connection details of a composition. It only checks the
-// FromFieldPath as that is the only one we are able to validate with certainty.
+// field paths, and that the types flowing through any transforms are compatible, as those are the only things
+// we are able to validate with certainty.
 func (v *Validator) validateConnectionDetailsWithSchemas(ctx context.Context, comp *v1.Composition) (errs field.ErrorList) {
 	for i, resource := range comp.Spec.Resources {
 		if len(resource.ConnectionDetails) == 0 {
@@ -57,12 +59,36 @@ func validateConnectionDetail(con v1.ConnectionDetail, schema *apiextensions.JSO
 	if schema == nil {
 		return nil
 	}
+	// Values read from a connection secret, fixed values and combined values
+	// are always strings.
+	fromType := xpschema.KnownJSONTypeString
 	// If defined we validate it, logical validation should enforce consistency if needed.
 	if con.FromFieldPath != nil {
-		if _, err := validateFieldPath(schema, *con.FromFieldPath); err != nil {
+		t, err := validateFieldPath(schema, *con.FromFieldPath)
+		if err != nil {
 			return field.Invalid(field.NewPath("fromFieldPath"), *con.FromFieldPath, err.Error())
 		}
+		if con.GetType() == v1.ConnectionDetailTypeFromFieldPath {
+			fromType = t
+		}
+	}
+	if con.Combine != nil {
+		for i, cv := range con.Combine.Variables {
+			// Variables that read from other resources can't be validated
+			// against this resource's schema, so we skip them.
+			if cv.FromFieldPath == nil || cv.FromResourceName != nil {
+				continue
+			}
+			if _, err := validateFieldPath(schema, *cv.FromFieldPath); err != nil {
+				return field.Invalid(field.NewPath("combine", "variables").Index(i).Child("fromFieldPath"), *cv.FromFieldPath, err.Error())
+			}
+		}
+	}
+	// Connection details may be of any type once transformed, as values that
+	// aren't strings are propagated as JSON, so we only check that each
+	// transform accepts the output of the previous one.
+	if _, err := validateTransformsChainIOTypes(con.Transforms, fromType); err != nil {
+		return err
 	}
-	// We don't validate other fields now as they do not have a schema to validate against.
 	return nil
 }
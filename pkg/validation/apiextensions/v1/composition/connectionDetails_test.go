@@ -116,6 +116,86 @@ func TestValidateConnectionDetails(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "should reject connection detail combining an invalid fromFieldPath",
+			args: args{
+				comp: buildDefaultComposition(t, v1.CompositionValidationModeLoose, nil, withConnectionDetails(
+					0,
+					v1.ConnectionDetail{
+						Name: pointer.String("url"),
+						Combine: &v1.ConnectionDetailCombine{
+							Variables: []v1.ConnectionDetailVariable{
+								{FromConnectionSecretKey: pointer.String("host")},
+								{FromFieldPath: pointer.String("spec.someWrongField")},
+							},
+							Format: "%s/%s",
+						},
+					},
+				)),
+				gkToCRD: defaultGKToCRDs(),
+			},
+			want: want{
+				errs: field.ErrorList{
+					{
+						Type:     field.ErrorTypeInvalid,
+						Field:    "spec.resources[0].connectionDetails[0].combine.variables[1].fromFieldPath",
+						BadValue: "spec.someWrongField",
+					},
+				},
+			},
+		},
+		{
+			name: "should accept connection detail with transforms accepting its type",
+			args: args{
+				comp: buildDefaultComposition(t, v1.CompositionValidationModeLoose, nil, withConnectionDetails(
+					0,
+					v1.ConnectionDetail{
+						Name:  pointer.String("port"),
+						Value: pointer.String("5432"),
+						Transforms: []v1.Transform{{
+							Type:    v1.TransformTypeConvert,
+							Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeInt64},
+						}, {
+							Type: v1.TransformTypeMath,
+							Math: &v1.MathTransform{Multiply: pointer.Int64(2)},
+						}},
+					},
+				)),
+				gkToCRD: defaultGKToCRDs(),
+			},
+			want: want{
+				errs: nil,
+			},
+		},
+		{
+			name: "should reject connection detail with transforms not accepting its type",
+			args: args{
+				comp: buildDefaultComposition(t, v1.CompositionValidationModeLoose, nil, withConnectionDetails(
+					0,
+					v1.ConnectionDetail{
+						Name:                    pointer.String("port"),
+						FromConnectionSecretKey: pointer.String("port"),
+						Transforms: []v1.Transform{{
+							Type: v1.TransformTypeMath,
+							Math: &v1.MathTransform{Multiply: pointer.Int64(2)},
+						}},
+					},
+				)),
+				gkToCRD: defaultGKToCRDs(),
+			},
+			want: want{
+				errs: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].connectionDetails[0].transforms[0]",
+						BadValue: v1.Transform{
+							Type: v1.TransformTypeMath,
+							Math: &v1.MathTransform{Multiply: pointer.Int64(2)},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
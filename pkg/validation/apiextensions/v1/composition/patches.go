@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -240,6 +241,21 @@ func (v *Validator) validatePatchWithSchemaInternal(ctx patchValidationCtx) *fie
 			getSchemaForVersion(ctx.resourceCRD, ctx.resourceGVK.Version),
 			nil,
 		)
+	case v1.PatchTypeFromComposedFieldPath:
+		// We don't validate the fromFieldPath against the schema of the
+		// composed resource the patch reads from.
+		fromType, toType, validationErr = validateFromCompositeFieldPathPatch(
+			ctx.patch,
+			nil,
+			getSchemaForVersion(ctx.resourceCRD, ctx.resourceGVK.Version),
+		)
+	case v1.PatchTypeFromEachFieldPath:
+		// The object FromEachFieldPath patches read from has no schema.
+		fromType, toType, validationErr = validateFromCompositeFieldPathPatch(
+			ctx.patch,
+			nil,
+			getSchemaForVersion(ctx.resourceCRD, ctx.resourceGVK.Version),
+		)
 	}
 	if validationErr != nil {
 		return validationErr
@@ -256,13 +272,15 @@ func validateCombineFromCompositePathPatch(patch v1.Patch, from, to *apiextensio
 		return "", "", field.Invalid(field.NewPath("toFieldPath"), toFieldPath, toFieldPathErr.Error())
 	}
 	errs := field.ErrorList{}
+	varTypes := make([]xpschema.KnownJSONType, 0, len(patch.Combine.Variables))
 	for _, variable := range patch.Combine.Variables {
 		fromFieldPath := variable.FromFieldPath
-		_, err := validateFieldPath(from, fromFieldPath)
+		t, err := validateFieldPath(from, fromFieldPath)
 		if err != nil {
 			errs = append(errs, field.Invalid(field.NewPath("fromFieldPath"), fromFieldPath, err.Error()))
 			continue
 		}
+		varTypes = append(varTypes, t)
 	}
 
 	if len(errs) > 0 {
@@ -275,6 +293,14 @@ func validateCombineFromCompositePathPatch(patch v1.Patch, from, to *apiextensio
 			return "", "", field.Required(field.NewPath("combine", "string"), "string combine strategy requires configuration")
 		}
 		fromType = xpschema.KnownJSONTypeString
+	case v1.CombineStrategyMath:
+		if patch.Combine.Math == nil {
+			return "", "", field.Required(field.NewPath("combine", "math"), "math combine strategy requires configuration")
+		}
+		fromType, err = combineMathType(varTypes)
+		if err != nil {
+			return "", "", err
+		}
 	default:
 		return "", "", field.Invalid(field.NewPath("combine", "strategy"), patch.Combine.Strategy, "combine strategy is not supported")
 	}
@@ -284,6 +310,27 @@ func validateCombineFromCompositePathPatch(patch v1.Patch, from, to *apiextensio
 	return fromType, toType, nil
 }
 
+// combineMathType returns the type produced by combining variables of the
+// supplied types using the math strategy. This is an integer if all variables
+// are integers, or a number otherwise.
+func combineMathType(types []xpschema.KnownJSONType) (xpschema.KnownJSONType, *field.Error) {
+	out := xpschema.KnownJSONTypeInteger
+	for i, t := range types {
+		switch t {
+		case "":
+			// The type of this variable is unknown, so we can't know the output
+			// type.
+			return "", nil
+		case xpschema.KnownJSONTypeInteger:
+		case xpschema.KnownJSONTypeNumber:
+			out = xpschema.KnownJSONTypeNumber
+		default:
+			return "", field.Invalid(field.NewPath("combine", "variables").Index(i), t, "math combine strategy requires numeric variables")
+		}
+	}
+	return out, nil
+}
+
 // validateFromCompositeFieldPathPatch validates a patch of type FromCompositeFieldPath.
 func validateFromCompositeFieldPathPatch(patch v1.Patch, from, to *apiextensions.JSONSchemaProps) (fromType, toType xpschema.KnownJSONType, res *field.Error) {
 	fromFieldPath := patch.GetFromFieldPath()
@@ -298,6 +345,16 @@ func validateFromCompositeFieldPathPatch(patch v1.Patch, from, to *apiextensions
 		return "", "", field.Invalid(field.NewPath("toFieldPath"), toFieldPath, err.Error())
 	}
 
+	// Transforms are applied to each element matched by a wildcarded
+	// fromFieldPath. If the toFieldPath has no wildcards the transformed
+	// elements are patched to it as an array.
+	if strings.Contains(fromFieldPath, "[*]") && !strings.Contains(toFieldPath, "[*]") {
+		if toType != "" && toType != xpschema.KnownJSONTypeArray {
+			return "", "", field.Invalid(field.NewPath("toFieldPath"), toFieldPath, fmt.Sprintf("must be an array when fromFieldPath contains wildcards, got %s", toType))
+		}
+		return fromType, "", nil
+	}
+
 	return fromType, toType, nil
 }
 
@@ -406,6 +463,10 @@ func validateFieldPathSegmentField(parent *apiextensions.JSONSchemaProps, segmen
 	if segment.Type != fieldpath.SegmentField {
 		return nil, errors.Errorf("segment is not a field")
 	}
+	// A wildcard matches every element of an array.
+	if segment.Field == "*" && parent.Type == string(xpschema.KnownJSONTypeArray) {
+		return validateFieldPathSegmentIndex(parent, fieldpath.Segment{Type: fieldpath.SegmentIndex})
+	}
 	if propType := parent.Type; propType != "" && propType != string(xpschema.KnownJSONTypeObject) {
 		return nil, errors.Errorf(errFmtFieldAccessWrongType, segment.Field, propType)
 	}
@@ -479,13 +540,19 @@ func IsValidInputForTransform(t *v1.Transform, fromType v1.TransformIOType) erro
 			return errors.Errorf("match transform can only be used with string input types, got %s", fromType)
 		}
 	case v1.TransformTypeString:
-		if fromType != v1.TransformIOTypeString {
-			return errors.Errorf("string transform can only be used with string input types, got %s", fromType)
+		want := v1.TransformIOTypeString
+		if t.String != nil {
+			want = t.String.GetInputType()
+		}
+		if fromType != want {
+			return errors.Errorf("string transform can only be used with %s input types, got %s", want, fromType)
 		}
 	case v1.TransformTypeConvert:
 		if _, err := composite.GetConversionFunc(t.Convert, fromType); err != nil {
 			return err
 		}
+	case v1.TransformTypeCEL:
+		// CEL expressions accept any input type.
 	default:
 		return errors.Errorf("unknown transform type %s", t.Type)
 	}
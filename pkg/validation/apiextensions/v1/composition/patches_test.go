@@ -313,6 +313,184 @@ func TestValidateTransforms(t *testing.T) {
 				toType:   "string",
 			},
 		},
+		"AcceptStringSplitToArray": {
+			reason: "Should accept a string split transform from a string to an array",
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type: v1.TransformTypeString,
+						String: &v1.StringTransform{
+							Type:  v1.StringTransformTypeSplit,
+							Split: &v1.StringTransformSplit{Separator: ","},
+						},
+					},
+				},
+				fromType: "string",
+				toType:   "array",
+			},
+		},
+		"AcceptStringJoinFromArray": {
+			reason: "Should accept a string join transform from an array to a string",
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type: v1.TransformTypeString,
+						String: &v1.StringTransform{
+							Type: v1.StringTransformTypeJoin,
+							Join: &v1.StringTransformJoin{Separator: ","},
+						},
+					},
+				},
+				fromType: "array",
+				toType:   "string",
+			},
+		},
+		"RejectStringJoinFromString": {
+			reason: "Should reject a string join transform from a string",
+			want: want{err: &field.Error{
+				Type:  field.ErrorTypeInvalid,
+				Field: "transforms[0]",
+			}},
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type: v1.TransformTypeString,
+						String: &v1.StringTransform{
+							Type: v1.StringTransformTypeJoin,
+							Join: &v1.StringTransformJoin{Separator: ","},
+						},
+					},
+				},
+				fromType: "string",
+				toType:   "string",
+			},
+		},
+		"RejectStringSplitToString": {
+			reason: "Should reject a string split transform to a string",
+			want: want{err: &field.Error{
+				Type:  field.ErrorTypeInvalid,
+				Field: "transforms",
+			}},
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type: v1.TransformTypeString,
+						String: &v1.StringTransform{
+							Type:  v1.StringTransformTypeSplit,
+							Split: &v1.StringTransformSplit{Separator: ","},
+						},
+					},
+				},
+				fromType: "string",
+				toType:   "string",
+			},
+		},
+		"AcceptMathRoundNumberToInteger": {
+			reason: "Should accept a math round transform from a number to an integer",
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type: v1.TransformTypeMath,
+						Math: &v1.MathTransform{
+							Type: v1.MathTransformTypeRound,
+						},
+					},
+				},
+				fromType: "number",
+				toType:   "integer",
+			},
+		},
+		"RejectMathDivideNumberToInteger": {
+			reason: "Should reject a math divide transform from a number to an integer",
+			want: want{err: &field.Error{
+				Type:  field.ErrorTypeInvalid,
+				Field: "transforms",
+			}},
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type: v1.TransformTypeMath,
+						Math: &v1.MathTransform{
+							Type:   v1.MathTransformTypeDivide,
+							Divide: pointer.Int64(2),
+						},
+					},
+				},
+				fromType: "number",
+				toType:   "integer",
+			},
+		},
+		"AcceptConvertStringToObject": {
+			reason: "Should accept a convert transform that parses a JSON string into an object",
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type: v1.TransformTypeConvert,
+						Convert: &v1.ConvertTransform{
+							ToType: v1.TransformIOTypeObject,
+							Format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatJSON))),
+						},
+					},
+				},
+				fromType: "string",
+				toType:   "object",
+			},
+		},
+		"AcceptConvertArrayToString": {
+			reason: "Should accept a convert transform that serializes an array as a YAML string",
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type: v1.TransformTypeConvert,
+						Convert: &v1.ConvertTransform{
+							ToType: v1.TransformIOTypeString,
+							Format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatYAML))),
+						},
+					},
+				},
+				fromType: "array",
+				toType:   "string",
+			},
+		},
+		"RejectConvertStringToObjectWrongOutputType": {
+			reason: "Should reject a convert transform that outputs an object to an array field",
+			want: want{err: &field.Error{
+				Type:  field.ErrorTypeInvalid,
+				Field: "transforms",
+			}},
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type: v1.TransformTypeConvert,
+						Convert: &v1.ConvertTransform{
+							ToType: v1.TransformIOTypeObject,
+							Format: (*v1.ConvertTransformFormat)(pointer.String(string(v1.ConvertTransformFormatJSON))),
+						},
+					},
+				},
+				fromType: "string",
+				toType:   "array",
+			},
+		},
+		"RejectConvertObjectToStringWithoutFormat": {
+			reason: "Should reject a convert transform from an object to a string without a json or yaml format",
+			want: want{err: &field.Error{
+				Type:  field.ErrorTypeInvalid,
+				Field: "transforms[0]",
+			}},
+			args: args{
+				transforms: []v1.Transform{
+					{
+						Type: v1.TransformTypeConvert,
+						Convert: &v1.ConvertTransform{
+							ToType: v1.TransformIOTypeString,
+						},
+					},
+				},
+				fromType: "object",
+				toType:   "string",
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -363,6 +541,25 @@ func TestValidateFieldPath(t *testing.T) {
 				},
 			},
 		},
+		"AcceptWildcardFieldPath": {
+			reason: "Should validate a field path with a wildcard array index",
+			want:   want{err: nil, fieldType: "string"},
+			args: args{
+				fieldPath: "spec.forProvider.subnets[*].id",
+				schema: &apiextensions.JSONSchemaProps{
+					Properties: map[string]apiextensions.JSONSchemaProps{
+						"spec": {
+							Properties: map[string]apiextensions.JSONSchemaProps{
+								"forProvider": {
+									Properties: map[string]apiextensions.JSONSchemaProps{
+										"subnets": {
+											Type: "array",
+											Items: &apiextensions.JSONSchemaPropsOrArray{
+												Schema: &apiextensions.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]apiextensions.JSONSchemaProps{
+														"id": {Type: "string"}}}}}}}}}}}},
+		},
 		"RejectInvalidFieldPath": {
 			reason: "Should return an error for an invalid field path",
 			want:   want{err: xperrors.Errorf(errFmtFieldInvalid, "wrong")},
@@ -128,6 +128,11 @@ func (v *Validator) Validate(ctx context.Context, obj runtime.Object) (warns []s
 		}
 	}
 
+	// Validate CEL expressions compile, this doesn't require any CRDs
+	if errs := validateCELExpressions(comp); len(errs) != 0 {
+		return nil, errs
+	}
+
 	// Validate patches given the above CRDs, skip if any of the required CRDs is not available
 	for _, f := range []func(context.Context, *v1.Composition) field.ErrorList{
 		v.validatePatchesWithSchemas,
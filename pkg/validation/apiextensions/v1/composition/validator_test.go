@@ -61,6 +61,30 @@ func TestValidatorValidate(t *testing.T) {
 				gkToCRDs: nil,
 			},
 		},
+		"RejectInvalidCELTransform": {
+			reason: "Should reject a Composition with a CEL transform that doesn't compile, even if no CRDs are available",
+			want: want{
+				errs: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[0].transforms[0].cel.expression",
+					},
+				},
+			},
+			args: args{
+				comp: buildDefaultComposition(t, v1.CompositionValidationModeStrict, map[string]any{"someOtherField": "test"},
+					withPatches(0, v1.Patch{
+						Type:          v1.PatchTypeFromCompositeFieldPath,
+						FromFieldPath: pointer.String("spec.someField"),
+						ToFieldPath:   pointer.String("spec.someOtherField"),
+						Transforms: []v1.Transform{{
+							Type: v1.TransformTypeCEL,
+							CEL:  &v1.CELTransform{Expression: "self +"},
+						}},
+					})),
+				gkToCRDs: nil,
+			},
+		},
 		"AcceptStrictAllCRDs": {
 			reason: "Should accept a valid Composition if all CRDs are available",
 			want:   want{errs: nil},
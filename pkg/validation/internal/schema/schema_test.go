@@ -133,6 +133,24 @@ func TestConvertTransformTypeToKnownJSONType(t *testing.T) {
 				t: KnownJSONTypeNumber,
 			},
 		},
+		"Object": {
+			reason: "Object",
+			args: args{
+				c: v1.TransformIOTypeObject,
+			},
+			want: want{
+				t: KnownJSONTypeObject,
+			},
+		},
+		"Array": {
+			reason: "Array",
+			args: args{
+				c: v1.TransformIOTypeArray,
+			},
+			want: want{
+				t: KnownJSONTypeArray,
+			},
+		},
 		"Unknown": {
 			reason: "Unknown returns empty string, should never happen",
 			args: args{
@@ -223,18 +241,18 @@ func TestFromKnownJSONType(t *testing.T) {
 				out: v1.TransformIOTypeBool,
 			},
 		},
-		"InvalidArray": {
-			reason: "Array should not be valid",
+		"ValidArray": {
+			reason: "Array should be valid and convert properly",
 			args:   args{t: KnownJSONTypeArray},
 			want: want{
-				err: xperrors.Errorf(errFmtUnsupportedJSONType, KnownJSONTypeArray),
+				out: v1.TransformIOTypeArray,
 			},
 		},
-		"InvalidObject": {
-			reason: "Object should not be valid",
+		"ValidObject": {
+			reason: "Object should be valid and convert properly",
 			args:   args{t: KnownJSONTypeObject},
 			want: want{
-				err: xperrors.Errorf(errFmtUnsupportedJSONType, KnownJSONTypeObject),
+				out: v1.TransformIOTypeObject,
 			},
 		},
 	}